@@ -10,12 +10,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,13 +32,79 @@ type App struct {
 
 	tasks map[string]*Task
 	order []string
-	queue chan string
+	pending []string
 
 	activeProfileID string
+	customProfiles  []Profile
 	lastCommand     string
 	ytDlpPath       string
+	ffmpegPath      string
+	galleryDlPath   string
+	galleryDlRules  []string
+	aria2cPath      string
+	aria2           Aria2Settings
 	running         map[string]*exec.Cmd
-	useBrowserCookies bool
+	suspended       map[string]bool
+	slotFreed       map[string]bool
+	estimators      map[string]*speedEstimator
+	cookieBrowser   string
+	cookiesFile     string
+	proxy           string
+	siteCredentials []SiteCredential
+	postDownloadHook []string
+	clipboardWatch  ClipboardWatchSettings
+	clipboardLast   string
+	clipboardIgnored map[string]bool
+	started         bool
+	pendingDeepLinks []string
+	hostFallbacks   map[string]string
+	hostOverrides   []HostOverride
+	pipelines       []Pipeline
+	taskTemplates   []TaskTemplate
+	recurrences     []Recurrence
+	subscriptions   []Subscription
+	archiveMu       sync.Mutex
+	pipelineTasks   map[string]bool
+	bandwidthRules  []BandwidthRule
+	bandwidthLimit  string
+	bandwidthKeepRunning bool
+	noAutoResume    bool
+	permanentDeleteFallback bool
+	filenameMode    string
+	collisionPolicy string
+	sizeConfirmThreshold int64
+	maxHeight       int
+	mediaPreferences MediaPreferences
+	runningLimits   map[string]string
+	restartRequested map[string]bool
+	cleanupPolicy   CleanupPolicy
+	reviewedOrphans map[string]bool
+	organizeRules   []OrganizeRule
+	libraryDir      string
+	watchDir        string
+	retentionPolicy RetentionPolicy
+	controlServer   *http.Server
+	remoteServer    *http.Server
+	remoteConfig    RemoteConfig
+	remoteError     string
+	remoteHub       remoteHub
+	store           *taskStore
+	savePending     bool
+	queuePaused     bool
+	shuttingDown    bool
+	slotsChanged    *sync.Cond
+	maxConcurrency  int
+	maxPerHost      int
+	retryPolicy     RetryPolicy
+	cooldownMinutes int
+	hostCooldowns   map[string]time.Time
+	filenameTemplate string
+	activeDownloads int
+	hostActive      map[string]int
+	mqttConfig      MQTTConfig
+	mqttOutbox      chan mqttMessage
+	mqttReconnect   chan struct{}
+	mqttLastStatus  map[string]string
 }
 
 // Task represents a download task.
@@ -45,21 +112,78 @@ type Task struct {
 	ID           string    `json:"id"`
 	URL          string    `json:"url"`
 	Title        string    `json:"title"`
+	Notes        string    `json:"notes"`
+	// TitleEdited is set once the user renames the task; fetched titles no
+	// longer replace it.
+	TitleEdited  bool      `json:"titleEdited,omitempty"`
+	Tags         []string  `json:"tags"`
+	// ExtraArgs are yt-dlp arguments added to this task's download only.
+	ExtraArgs    []string  `json:"extraArgs"`
+	DuplicateOf  string    `json:"duplicateOf"`
+	Extractor    string    `json:"extractor"`
+	VideoID      string    `json:"videoId"`
+	AllowDuplicate bool    `json:"allowDuplicate"`
+	// SizeConfirmed is set once the user accepts a download above the size
+	// confirmation threshold.
+	SizeConfirmed bool     `json:"sizeConfirmed,omitempty"`
 	SourceHost   string    `json:"sourceHost"`
 	Status       string    `json:"status"`
 	Stage        string    `json:"stage"`
 	Progress     string    `json:"progress"`
 	Speed        string    `json:"speed"`
 	ETA          string    `json:"eta"`
+	DownloadedBytes int64  `json:"downloadedBytes"`
+	TotalBytes   int64     `json:"totalBytes"`
+	OutputDir    string    `json:"outputDir"`
+	FilenameTemplate string `json:"filenameTemplate,omitempty"`
+	FormatID     string    `json:"formatId"`
+	StartTime    string    `json:"startTime,omitempty"`
+	EndTime      string    `json:"endTime,omitempty"`
+	BatchID      string    `json:"batchId"`
+	// RecurrenceID links a task to the recurring download that created it.
+	RecurrenceID string    `json:"recurrenceId,omitempty"`
+	// DeletedAt is set while a deleted task can still be restored with
+	// UndoDelete.
+	DeletedAt    *time.Time `json:"deletedAt,omitempty"`
+	ProfileID    string    `json:"profileId"`
+	Subtitles    *SubtitleOptions `json:"subtitles"`
+	Proxy        string    `json:"proxy"`
+	SubtitlePaths []string `json:"subtitlePaths"`
 	OutputPath   string    `json:"outputPath"`
+	Outputs      []TaskOutput `json:"outputs"`
+	// OutputPaths lists the paths in Outputs, primary file first.
+	OutputPaths  []string  `json:"outputPaths"`
+	Embedded     []string  `json:"embedded"`
+	Engine       string    `json:"engine"`
+	// Command is the last command run for this task, with passwords masked.
+	// DryRun tasks resolve metadata and Command without downloading.
+	Command      string    `json:"command,omitempty"`
+	DryRun       bool      `json:"dryRun,omitempty"`
+	// PartialPath is the .part file a retry continues, as reported by yt-dlp
+	// or chosen by the direct engine. ExpectedSHA256 is the checksum a direct
+	// download must match.
+	PartialPath    string  `json:"partialPath,omitempty"`
+	ExpectedSHA256 string  `json:"expectedSha256,omitempty"`
 	MissingOutput bool     `json:"missingOutput"`
+	ModifiedOutput bool    `json:"modifiedOutput"`
 	ErrorMessage string    `json:"errorMessage"`
+	StatusCode   string    `json:"statusCode"`
+	StageCode    string    `json:"stageCode"`
+	StageParams  map[string]string `json:"stageParams,omitempty"`
+	ErrorCode    string    `json:"errorCode"`
+	ErrorParams  map[string]string `json:"errorParams,omitempty"`
+	ErrorHint    string    `json:"errorHint,omitempty"`
+	ErrorAction  string    `json:"errorAction,omitempty"`
 	Resume       bool      `json:"resume"`
+	RetryCount   int       `json:"retryCount"`
+	MaxRetries   int       `json:"maxRetries"`
+	ScheduledAt  *time.Time `json:"scheduledAt"`
 	Duration     int       `json:"duration"`
 	Filesize     int64     `json:"filesize"`
 	Width        int       `json:"width"`
 	Height       int       `json:"height"`
 	CreatedAt    time.Time `json:"createdAt"`
+	Timeline     []StageSpan `json:"timeline"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 }
 
@@ -68,33 +192,111 @@ const (
 	statusRunning = "Running"
 	statusSuccess = "Success"
 	statusFailed  = "Failed"
+	statusCanceled = "Canceled"
+	statusPaused  = "Paused"
+	statusInterrupted = "Interrupted"
+	statusAwaitingConfirmation = "AwaitingConfirmation"
 )
 
-const maxConcurrentDownloads = 3
+const (
+	defaultMaxConcurrency = 3
+	maxConcurrencyLimit   = 10
+	defaultMaxPerHost     = 2
+)
 
+// Profile is a named download preset. Beyond yt-dlp args it can send
+// downloads to its own folder, name files with its own template and run a
+// post-processing chain once the download finishes.
 type Profile struct {
-	ID   string   `json:"id"`
-	Name string   `json:"name"`
-	Args []string `json:"args"`
+	ID               string         `json:"id"`
+	Name             string         `json:"name"`
+	Args             []string       `json:"args"`
+	OutputDir        string         `json:"outputDir"`
+	FilenameTemplate string         `json:"filenameTemplate"`
+	PostProcess      []PipelineStep `json:"postProcess"`
+	Subtitles        *SubtitleOptions `json:"subtitles"`
+	PostDownloadHook []string       `json:"postDownloadHook"`
+	DownloadArchive  bool           `json:"downloadArchive"`
+	TranscodePreset  string         `json:"transcodePreset"`
+	EmbedMetadata    bool           `json:"embedMetadata"`
+	EmbedChapters    bool           `json:"embedChapters"`
+	EmbedThumbnail   bool           `json:"embedThumbnail"`
+	Aria2            *Aria2Settings `json:"aria2"`
+	Builtin          bool           `json:"builtin"`
 }
 
 type appConfig struct {
 	ActiveProfileID string `json:"activeProfileId"`
-	UseBrowserCookies bool `json:"useBrowserCookies"`
+	UseBrowserCookies bool `json:"useBrowserCookies,omitempty"`
+	CookieBrowser   string `json:"cookieBrowser"`
+	CookiesFile     string `json:"cookiesFile"`
+	Proxy           string `json:"proxy"`
+	SiteCredentials []SiteCredential `json:"siteCredentials"`
+	PostDownloadHook []string `json:"postDownloadHook"`
+	ClipboardWatch  ClipboardWatchSettings `json:"clipboardWatch"`
+	HostFallbacks   map[string]string `json:"hostFallbacks"`
+	GalleryDlRules  []string `json:"galleryDlRules"`
+	Aria2           Aria2Settings `json:"aria2"`
+	BandwidthRules  []BandwidthRule `json:"bandwidthRules"`
+	BandwidthLimit  string `json:"bandwidthLimit"`
+	BandwidthKeepRunning bool `json:"bandwidthKeepRunning"`
+	NoAutoResume    bool `json:"noAutoResume"`
+	PermanentDeleteFallback bool `json:"permanentDeleteFallback"`
+	FilenameMode    string `json:"filenameMode"`
+	CollisionPolicy string `json:"collisionPolicy"`
+	SizeConfirmThreshold int64 `json:"sizeConfirmThreshold"`
+	MaxHeight       int `json:"maxHeight"`
+	MediaPreferences MediaPreferences `json:"mediaPreferences"`
+	CleanupPolicy   CleanupPolicy `json:"cleanupPolicy"`
+	OrganizeRules   []OrganizeRule `json:"organizeRules"`
+	LibraryDirectory string `json:"libraryDirectory"`
+	WatchDirectory  string `json:"watchDirectory"`
+	RetentionPolicy RetentionPolicy `json:"retentionPolicy"`
+	CustomProfiles  []Profile `json:"customProfiles,omitempty"`
+	MQTT            MQTTConfig `json:"mqtt"`
+	Remote          RemoteConfig `json:"remote"`
+	HostOverrides   []HostOverride `json:"hostOverrides"`
+	MaxConcurrency  int `json:"maxConcurrency"`
+	MaxPerHost      int `json:"maxPerHost"`
+	RetryPolicy     *RetryPolicy `json:"retryPolicy"`
+	RateLimitCooldown *int `json:"rateLimitCooldownMinutes"`
+	DownloadDirectory string `json:"downloadDirectory"`
+	FilenameTemplate string `json:"filenameTemplate"`
 }
 
 const defaultProfileID = "default"
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{
+	app := &App{
 		tasks:           make(map[string]*Task),
 		order:           make([]string, 0),
-		queue:           make(chan string, 100),
+		pending:         make([]string, 0),
+		hostActive:      make(map[string]int),
+		maxPerHost:      defaultMaxPerHost,
+		retryPolicy:     defaultRetryPolicy(),
+		cooldownMinutes: defaultCooldownMinutes,
+		hostCooldowns:   make(map[string]time.Time),
 		activeProfileID: defaultProfileID,
+		maxConcurrency:  defaultMaxConcurrency,
 		running:         make(map[string]*exec.Cmd),
-		useBrowserCookies: false,
-	}
+		suspended:       make(map[string]bool),
+		slotFreed:       make(map[string]bool),
+		estimators:      make(map[string]*speedEstimator),
+		hostFallbacks:   make(map[string]string),
+		clipboardIgnored: make(map[string]bool),
+		pipelineTasks:   make(map[string]bool),
+		runningLimits:   make(map[string]string),
+		restartRequested: make(map[string]bool),
+		galleryDlRules:  defaultGalleryDlRules,
+		aria2:           Aria2Settings{Connections: defaultAria2Connections},
+		mqttOutbox:      make(chan mqttMessage, 256),
+		mqttReconnect:   make(chan struct{}, 1),
+		mqttLastStatus:  make(map[string]string),
+		remoteConfig:    RemoteConfig{Port: defaultRemotePort},
+	}
+	app.slotsChanged = sync.NewCond(&app.mu)
+	return app
 }
 
 // startup is called when the app starts. The context is saved
@@ -102,32 +304,148 @@ func NewApp() *App {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	a.ytDlpPath = resolveYtDlpPath()
+	a.ffmpegPath = resolveFfmpegPath()
+	a.galleryDlPath = resolveGalleryDlPath()
+	a.aria2cPath = resolveAria2cPath()
+	a.loadProfiles()
 	a.loadConfig()
+	a.loadPipelines()
+	a.loadTaskTemplates()
+	a.loadRecurrences()
+	a.loadSubscriptions()
 	a.loadTasks()
+	a.recoverTasks()
 	go a.worker()
+	go a.queueSummaryLoop()
+	go a.bandwidthLoop()
+	go a.cleanupLoop()
+	go a.subscriptionLoop()
+	go a.scheduleLoop()
+	go a.recurrenceLoop()
+	go a.deleteSweepLoop()
+	go a.watchFolderLoop()
+	go a.ytDlpUpdateLoop()
+	go a.clipboardLoop()
+	go a.mqttLoop()
+	a.startControlServer()
+	if a.remoteConfig.Enabled {
+		_ = a.startRemoteServer()
+	}
+	a.startDeepLinks()
+	a.startTray()
+}
+
+// shutdown is called when the app is closing.
+func (a *App) shutdown(ctx context.Context) {
+	a.stopTray()
+	a.stopControlServer()
+	a.stopRemoteServer()
+	a.interruptRunning()
+	a.stopSuspended()
+	if a.store != nil {
+		a.saveTasks()
+		_ = a.store.close()
+	}
 }
 
 // CreateTasksFromText parses URLs and enqueues download tasks.
-func (a *App) CreateTasksFromText(text string) ([]Task, error) {
+// A non-empty outputDir sends these tasks to that folder instead of the
+// default dated download directory.
+func (a *App) CreateTasksFromText(text string, outputDir string) ([]Task, error) {
 	urls := extractURLs(text)
 	if len(urls) == 0 {
 		return []Task{}, nil
 	}
-
+	outputDir, err := validateOutputDir(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]PreviewEntry, 0, len(urls))
+	for _, url := range urls {
+		entries = append(entries, PreviewEntry{URL: url})
+	}
+	options := newTaskOptions{outputDir: outputDir}
+	if len(entries) > 1 {
+		// Links pasted together are tracked as one batch; see ListBatches.
+		options.batchID = newID()
+	}
+	return a.createTasks(entries, options), nil
+}
+
+// newTaskOptions are the settings shared by tasks created together. Tasks
+// expanded from one playlist share batchID; an empty profileID uses the
+// host's profile, or else the active profile when the task runs. startTime
+// and endTime limit the download to a clip.
+type newTaskOptions struct {
+	outputDir string
+	batchID   string
+	profileID string
+	startTime string
+	endTime   string
+	// engine overrides the backend picked from each entry's URL.
+	engine         string
+	expectedSHA256 string
+	extraArgs      []string
+	dryRun         bool
+	// formatID, subtitles and tags come from a task template.
+	formatID  string
+	subtitles *SubtitleOptions
+	tags      []string
+	// recurrenceID marks tasks created by a recurring download; they may
+	// download the same video again.
+	recurrenceID string
+}
+
+// createTasks adds one queued task per entry and starts them.
+func (a *App) createTasks(entries []PreviewEntry, options newTaskOptions) []Task {
 	now := time.Now()
-	created := make([]Task, 0, len(urls))
-	ids := make([]string, 0, len(urls))
+	created := make([]Task, 0, len(entries))
+	ids := make([]string, 0, len(entries))
+	// engineForURL takes a.mu, so engines are picked before locking.
+	engines := make([]string, len(entries))
+	for i, entry := range entries {
+		engines[i] = options.engine
+		if engines[i] == "" {
+			engines[i] = a.engineForURL(entry.URL)
+		}
+	}
 
 	a.mu.Lock()
-	for _, url := range urls {
+	for i, entry := range entries {
+		url := entry.URL
+		title := strings.TrimSpace(entry.Title)
+		if title == "" {
+			title = defaultTitleFromURL(url)
+		}
+		profileID := options.profileID
+		if profileID == "" {
+			profileID = a.hostProfileLocked(url)
+		}
 		id := newID()
 		task := &Task{
 			ID:        id,
 			URL:       url,
-			Title:     defaultTitleFromURL(url),
+			Title:     title,
 			SourceHost: sourceHostFromURL(url),
+			OutputDir: options.outputDir,
+			BatchID:   options.batchID,
+			ProfileID: profileID,
+			StartTime: options.startTime,
+			EndTime:   options.endTime,
+			ExpectedSHA256: options.expectedSHA256,
+			ExtraArgs: options.extraArgs,
+			DryRun:    options.dryRun,
+			FormatID:  options.formatID,
+			Subtitles: options.subtitles,
+			Tags:      append([]string(nil), options.tags...),
+			RecurrenceID: options.recurrenceID,
+			AllowDuplicate: options.recurrenceID != "",
+			Engine:    engines[i],
 			Status:    statusQueued,
-			Stage:     "Parse URL",
+			StatusCode: statusCodeFor(statusQueued),
+			Stage:     stageLabels[stageParseURL],
+			StageCode: stageParseURL,
+			Timeline:  []StageSpan{{Stage: stageLabels[stageParseURL], StartedAt: now}},
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
@@ -142,69 +460,42 @@ func (a *App) CreateTasksFromText(text string) ([]Task, error) {
 		a.emitTaskUpdate(task)
 	}
 	a.saveTasks()
-	for _, task := range created {
+	// Entries that already carry a title, such as playlist entries, are not
+	// probed up front; that would start a yt-dlp process per entry.
+	for i, task := range created {
+		if task.Engine != engineYtDlp || strings.TrimSpace(entries[i].Title) != "" {
+			continue
+		}
 		go a.prefetchTaskMetadata(task.ID, task.URL)
 	}
-	for _, id := range ids {
-		a.queue <- id
-	}
-
-	return created, nil
+	a.enqueueTasks(ids)
+	return created
 }
 
-// ListTasks returns all known tasks in creation order.
+// ListTasks returns all known tasks in creation order. Deleted tasks that
+// can still be restored with UndoDelete are left out.
 func (a *App) ListTasks() ([]Task, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	out := make([]Task, 0, len(a.order))
 	for _, id := range a.order {
-		if task, ok := a.tasks[id]; ok {
+		if task, ok := a.tasks[id]; ok && !task.deleted() {
 			out = append(out, *task)
 		}
 	}
 	return out, nil
 }
 
-// DeleteTask removes a task by id.
+// DeleteTask removes a task by id and moves its files to the trash.
 func (a *App) DeleteTask(id string) error {
-	a.mu.Lock()
-	task, ok := a.tasks[id]
-	if !ok {
-		a.mu.Unlock()
-		return errors.New("task not found")
-	}
-	if cmd, ok := a.running[id]; ok && cmd.Process != nil {
-		_ = cmd.Process.Kill()
-		delete(a.running, id)
-	}
-	outputPath := task.OutputPath
-	createdAt := task.CreatedAt
-	title := task.Title
-	a.mu.Unlock()
-
-	if outputPath != "" {
-		if info, err := os.Stat(outputPath); err == nil && !info.IsDir() {
-			if err := moveToTrash(outputPath); err != nil {
-				return err
-			}
-		}
-	}
-	cleanupPartialFiles(createdAt, title)
-
-	a.mu.Lock()
-	delete(a.tasks, id)
-	nextOrder := make([]string, 0, len(a.order))
-	for _, existing := range a.order {
-		if existing != id {
-			nextOrder = append(nextOrder, existing)
-		}
-	}
-	a.order = nextOrder
-	a.mu.Unlock()
+	return a.deleteTasks([]string{id}, false).err(id)
+}
 
-	a.saveTasks()
-	return nil
+// RemoveTaskEntry removes a task from the list without touching its
+// downloaded files.
+func (a *App) RemoveTaskEntry(id string) error {
+	return a.deleteTasks([]string{id}, true).err(id)
 }
 
 // OpenTaskFolder opens the output folder for a task.
@@ -216,6 +507,7 @@ func (a *App) OpenTaskFolder(id string) error {
 		return errors.New("task not found")
 	}
 	outputPath := task.OutputPath
+	taskDir := task.OutputDir
 	createdAt := task.CreatedAt
 	a.mu.Unlock()
 
@@ -223,7 +515,7 @@ func (a *App) OpenTaskFolder(id string) error {
 	if outputPath != "" {
 		outputDir = filepath.Dir(outputPath)
 	} else {
-		dir, err := taskOutputDir(createdAt)
+		dir, err := taskOutputDir(taskDir, createdAt)
 		if err != nil {
 			return err
 		}
@@ -241,7 +533,8 @@ func (a *App) OpenTaskFolder(id string) error {
 	return openWithDefaultApp(outputDir)
 }
 
-// OpenTaskFile opens the downloaded file with the system default app.
+// OpenTaskFile opens the task's main file with the system default app,
+// falling back to the first of its other files that still exists.
 func (a *App) OpenTaskFile(id string) error {
 	a.mu.Lock()
 	task, ok := a.tasks[id]
@@ -250,29 +543,57 @@ func (a *App) OpenTaskFile(id string) error {
 		return errors.New("task not found")
 	}
 	outputPath := task.OutputPath
+	outputPaths := task.outputPaths()
 	a.mu.Unlock()
 
 	if outputPath == "" {
 		return errors.New("output file not available")
 	}
 
-	info, err := os.Stat(outputPath)
-	if err != nil || info.IsDir() {
-		return errors.New("file not found")
+	if fileExists(outputPath) {
+		return openWithDefaultApp(outputPath)
+	}
+	for _, path := range outputPaths {
+		if fileExists(path) {
+			return openWithDefaultApp(path)
+		}
 	}
+	return errors.New("file not found")
+}
 
-	return openWithDefaultApp(outputPath)
+// OpenTaskOutput opens one of a task's files, such as a chapter or a
+// subtitle, with the system default app.
+func (a *App) OpenTaskOutput(id string, path string) error {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return errors.New("task not found")
+	}
+	outputPaths := task.outputPaths()
+	a.mu.Unlock()
+
+	if !slices.Contains(outputPaths, path) {
+		return errors.New("file does not belong to this task")
+	}
+	if !fileExists(path) {
+		return errors.New("file not found")
+	}
+	return openWithDefaultApp(path)
 }
 
 func (a *App) ListProfiles() ([]Profile, error) {
-	return builtinProfiles(), nil
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append(builtinProfiles(), a.customProfiles...), nil
 }
 
 func (a *App) SetActiveProfile(profileID string) error {
-	if _, ok := findProfileByID(profileID); !ok {
+	a.mu.Lock()
+	if _, ok := a.findProfileLocked(profileID); !ok {
+		a.mu.Unlock()
 		return errors.New("profile not found")
 	}
-	a.mu.Lock()
 	a.activeProfileID = profileID
 	a.mu.Unlock()
 	a.saveConfig()
@@ -284,18 +605,10 @@ func (a *App) GetActiveProfile() (Profile, error) {
 	return profile, nil
 }
 
-func (a *App) GetUseBrowserCookies() (bool, error) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	return a.useBrowserCookies, nil
-}
-
-func (a *App) SetUseBrowserCookies(enabled bool) error {
-	a.mu.Lock()
-	a.useBrowserCookies = enabled
-	a.mu.Unlock()
-	a.saveConfig()
-	return nil
+// PickOutputDirectory shows a native folder picker for choosing a per-task
+// output directory. It returns an empty string when the dialog is cancelled.
+func (a *App) PickOutputDirectory() (string, error) {
+	return a.PickDirectory("Choose output folder")
 }
 
 func (a *App) OpenPath(path string) error {
@@ -316,7 +629,7 @@ func (a *App) ExportTasks() (string, error) {
 	a.mu.Lock()
 	snapshot := make([]Task, 0, len(a.order))
 	for _, id := range a.order {
-		if task, ok := a.tasks[id]; ok {
+		if task, ok := a.tasks[id]; ok && !task.deleted() {
 			snapshot = append(snapshot, *task)
 		}
 	}
@@ -333,7 +646,7 @@ func (a *App) ExportTasksToFile() (string, error) {
 	a.mu.Lock()
 	snapshot := make([]Task, 0, len(a.order))
 	for _, id := range a.order {
-		if task, ok := a.tasks[id]; ok {
+		if task, ok := a.tasks[id]; ok && !task.deleted() {
 			snapshot = append(snapshot, *task)
 		}
 	}
@@ -375,13 +688,20 @@ func (a *App) ImportTasks(jsonText string, mode string, overwriteDownloaded bool
 			return nil, errors.New("task id is required")
 		}
 		if overwriteDownloaded && imported[i].Status == statusSuccess {
-			imported[i].Status = statusQueued
+			imported[i].setStatus(statusQueued)
 			imported[i].Progress = ""
 			imported[i].OutputPath = ""
+			imported[i].Outputs = nil
 			imported[i].MissingOutput = false
-			imported[i].ErrorMessage = ""
+			imported[i].clearError()
 		}
-		imported[i].MissingOutput = outputMissing(imported[i].OutputPath)
+		outputs, _ := refreshOutputs(imported[i].Outputs)
+		imported[i].setOutputs(outputs)
+	}
+
+	switch mode {
+	case "merge", "replace":
+		a.snapshotTasks("Before " + mode + " import")
 	}
 
 	switch mode {
@@ -406,7 +726,7 @@ func (a *App) ImportTasks(jsonText string, mode string, overwriteDownloaded bool
 		}
 		out := make([]Task, 0, len(a.order))
 		for _, id := range a.order {
-			if task, ok := a.tasks[id]; ok {
+			if task, ok := a.tasks[id]; ok && !task.deleted() {
 				out = append(out, *task)
 			}
 		}
@@ -430,7 +750,7 @@ func (a *App) ImportTasks(jsonText string, mode string, overwriteDownloaded bool
 		}
 		out := make([]Task, 0, len(a.order))
 		for _, id := range a.order {
-			if task, ok := a.tasks[id]; ok {
+			if task, ok := a.tasks[id]; ok && !task.deleted() {
 				out = append(out, *task)
 			}
 		}
@@ -445,12 +765,11 @@ func (a *App) ImportTasks(jsonText string, mode string, overwriteDownloaded bool
 
 func (a *App) getActiveProfile() (Profile, bool) {
 	a.mu.Lock()
-	activeID := a.activeProfileID
-	a.mu.Unlock()
-	if profile, ok := findProfileByID(activeID); ok {
+	defer a.mu.Unlock()
+	if profile, ok := a.findProfileLocked(a.activeProfileID); ok {
 		return profile, true
 	}
-	profile, _ := findProfileByID(defaultProfileID)
+	profile, _ := a.findProfileLocked(defaultProfileID)
 	return profile, true
 }
 
@@ -464,16 +783,18 @@ func (a *App) GetTaskFileStatus(id string) (string, error) {
 		return "", errors.New("task not found")
 	}
 	outputPath := task.OutputPath
+	outputs := make([]TaskOutput, len(task.Outputs))
+	copy(outputs, task.Outputs)
+	outputDir := task.OutputDir
 	createdAt := task.CreatedAt
 	title := task.Title
 	a.mu.Unlock()
 
 	if outputPath == "" {
-		if resolved := resolveOutputPath(createdAt, title); resolved != "" {
+		if resolved := resolveOutputPath(outputDir, createdAt, title); resolved != "" {
 			a.mu.Lock()
 			if task, ok := a.tasks[id]; ok {
-				task.OutputPath = resolved
-				task.MissingOutput = false
+				task.replaceOutputPath(outputPath, resolved)
 				task.UpdatedAt = time.Now()
 			}
 			a.mu.Unlock()
@@ -483,13 +804,11 @@ func (a *App) GetTaskFileStatus(id string) (string, error) {
 		return "pending", nil
 	}
 
-	info, err := os.Stat(outputPath)
-	if err != nil || info.IsDir() {
-		if resolved := resolveOutputPath(createdAt, title); resolved != "" {
+	if !fileExists(outputPath) {
+		if resolved := resolveOutputPath(outputDir, createdAt, title); resolved != "" {
 			a.mu.Lock()
 			if task, ok := a.tasks[id]; ok {
-				task.OutputPath = resolved
-				task.MissingOutput = false
+				task.replaceOutputPath(outputPath, resolved)
 				task.UpdatedAt = time.Now()
 			}
 			a.mu.Unlock()
@@ -499,11 +818,32 @@ func (a *App) GetTaskFileStatus(id string) (string, error) {
 		return "missing", nil
 	}
 
+	refreshed, changed := refreshOutputs(outputs)
+	missing := false
+	for _, output := range refreshed {
+		if output.Missing {
+			missing = true
+		}
+	}
+	if changed {
+		a.mu.Lock()
+		if task, ok := a.tasks[id]; ok {
+			task.setOutputs(refreshed)
+			task.UpdatedAt = time.Now()
+		}
+		a.mu.Unlock()
+		a.saveTasks()
+	}
+	if missing {
+		return "missing", nil
+	}
 	return "ok", nil
 }
 
 // GetTaskResumeStatus reports whether a task has partial output available for resuming.
-// Returns "ready" or "none".
+// Returns "ready" or "none". Only the .part file recorded while the task was
+// downloading counts, so files from other downloads with similar names are
+// never mistaken for this task's.
 func (a *App) GetTaskResumeStatus(id string) (string, error) {
 	a.mu.Lock()
 	task, ok := a.tasks[id]
@@ -511,8 +851,7 @@ func (a *App) GetTaskResumeStatus(id string) (string, error) {
 		a.mu.Unlock()
 		return "", errors.New("task not found")
 	}
-	createdAt := task.CreatedAt
-	title := strings.TrimSpace(task.Title)
+	partialPath := task.PartialPath
 	outputPath := strings.TrimSpace(task.OutputPath)
 	filesize := task.Filesize
 	status := task.Status
@@ -523,11 +862,6 @@ func (a *App) GetTaskResumeStatus(id string) (string, error) {
 		return "none", nil
 	}
 
-	outputDir, err := taskOutputDir(createdAt)
-	if err != nil {
-		return "none", nil
-	}
-
 	if outputPath != "" {
 		if info, err := os.Stat(outputPath); err == nil && !info.IsDir() && filesize > 0 {
 			if info.Size() < filesize {
@@ -536,39 +870,7 @@ func (a *App) GetTaskResumeStatus(id string) (string, error) {
 		}
 	}
 
-	if title == "" || title == "Pending title" {
-		return "none", nil
-	}
-
-	normalizedTitle := normalizeForMatch(title)
-	if normalizedTitle == "" {
-		return "none", nil
-	}
-
-	found := false
-	foundRecentPartial := false
-	_ = filepath.WalkDir(outputDir, func(path string, d os.DirEntry, err error) error {
-		if found || err != nil || d.IsDir() {
-			return nil
-		}
-		name := d.Name()
-		if !isPartialFile(name) {
-			return nil
-		}
-		if info, err := d.Info(); err == nil && info.ModTime().After(createdAt.Add(-1*time.Minute)) {
-			foundRecentPartial = true
-		}
-		normalizedName := normalizeForMatch(name)
-		if strings.Contains(normalizedName, normalizedTitle) {
-			found = true
-		}
-		return nil
-	})
-
-	if found {
-		return "ready", nil
-	}
-	if foundRecentPartial {
+	if partialPath != "" && partialExists(partialPath) {
 		return "ready", nil
 	}
 	return "none", nil
@@ -576,31 +878,58 @@ func (a *App) GetTaskResumeStatus(id string) (string, error) {
 
 // ResumeTask re-queues a task to continue an interrupted download.
 func (a *App) ResumeTask(id string) error {
+	return a.resumeTasks([]string{id}).err(id)
+}
+
+// CancelTask stops a queued or running task. The running process and its
+// children are killed; partial files are kept so the task can be resumed.
+// For a finished task, a running transcode or pipeline is stopped instead.
+func (a *App) CancelTask(id string) error {
 	a.mu.Lock()
 	task, ok := a.tasks[id]
 	if !ok {
 		a.mu.Unlock()
 		return errors.New("task not found")
 	}
-	if task.Status == statusRunning && time.Since(task.UpdatedAt) < 30*time.Second {
+	if task.Status == statusSuccess && a.pipelineTasks[id] {
+		if cmd, ok := a.running[id]; ok {
+			_ = killProcessTree(cmd)
+		}
+		delete(a.pipelineTasks, id)
+		a.mu.Unlock()
+		return nil
+	}
+	if task.Status != statusQueued && task.Status != statusRunning && task.Status != statusPaused && task.Status != statusAwaitingConfirmation {
 		a.mu.Unlock()
-		return errors.New("task is already running")
+		return errors.New("task is not active")
 	}
-	task.Status = statusQueued
-	task.Stage = "Resume"
-	task.Progress = ""
-	task.ErrorMessage = ""
-	task.Resume = true
+	if cmd, ok := a.running[id]; ok {
+		_ = killProcessTree(cmd)
+	}
+	delete(a.suspended, id)
+	task.setStatus(statusCanceled)
+	task.Speed = ""
+	task.ETA = ""
+	task.clearError()
 	task.UpdatedAt = time.Now()
+	task.closeStage(task.UpdatedAt)
 	updated := *task
 	a.mu.Unlock()
 
 	a.emitTaskUpdate(updated)
 	a.saveTasks()
-	a.enqueueTasks([]string{id})
 	return nil
 }
 
+// taskStopped reports whether the task was canceled, paused, interrupted or
+// removed, so its runner should exit without failing it.
+func (a *App) taskStopped(id string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	task, ok := a.tasks[id]
+	return !ok || task.Status == statusCanceled || task.Status == statusPaused || task.Status == statusInterrupted
+}
+
 // ForceResumeTask re-queues a task even if it appears to be running.
 func (a *App) ForceResumeTask(id string) error {
 	a.mu.Lock()
@@ -609,12 +938,11 @@ func (a *App) ForceResumeTask(id string) error {
 		a.mu.Unlock()
 		return errors.New("task not found")
 	}
-	task.Status = statusQueued
-	task.Stage = "Force Resume"
-	task.Progress = ""
-	task.ErrorMessage = ""
-	task.Resume = true
-	task.UpdatedAt = time.Now()
+	if task.deleted() {
+		a.mu.Unlock()
+		return errors.New("task is deleted")
+	}
+	requeueTaskLocked(task, stageForceResume, true)
 	updated := *task
 	a.mu.Unlock()
 
@@ -624,6 +952,19 @@ func (a *App) ForceResumeTask(id string) error {
 	return nil
 }
 
+// requeueTaskLocked puts a task back in the queued state, optionally
+// continuing from its partial files. The caller must hold a.mu and enqueue
+// the task afterwards.
+func requeueTaskLocked(task *Task, stage string, resume bool) {
+	task.setStatus(statusQueued)
+	task.Progress = ""
+	task.clearError()
+	task.Resume = resume
+	task.RetryCount = 0
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stage, nil, task.UpdatedAt)
+}
+
 func openWithDefaultApp(target string) error {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
@@ -637,7 +978,9 @@ func openWithDefaultApp(target string) error {
 	return cmd.Start()
 }
 
-func moveToTrash(target string) error {
+// trashFile moves target to the system trash. On Linux gio is tried first,
+// then the freedesktop.org trash directories directly.
+func trashFile(target string) error {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
 	case "darwin":
@@ -647,7 +990,13 @@ func moveToTrash(target string) error {
 		command := fmt.Sprintf("Add-Type -AssemblyName Microsoft.VisualBasic; [Microsoft.VisualBasic.FileIO.FileSystem]::DeleteFile(%q,'OnlyErrorDialogs','SendToRecycleBin')", target)
 		cmd = exec.Command("powershell", "-NoProfile", "-Command", command)
 	default:
-		cmd = exec.Command("gio", "trash", target)
+		if err := exec.Command("gio", "trash", target).Run(); err == nil {
+			return nil
+		}
+		if err := freedesktopTrash(target); err != nil {
+			return errors.New("failed to move file to trash: " + err.Error())
+		}
+		return nil
 	}
 	if err := cmd.Run(); err != nil {
 		return errors.New("failed to move file to trash")
@@ -655,20 +1004,171 @@ func moveToTrash(target string) error {
 	return nil
 }
 
+// worker starts pending tasks as download slots free up. The number of slots
+// follows maxConcurrency, and each source host is capped at its own limit;
+// tasks from busy hosts are skipped so other hosts can fill the free slots.
 func (a *App) worker() {
-	for i := 0; i < maxConcurrentDownloads; i++ {
+	for {
+		id, host := a.nextPendingTask()
 		go func() {
-			for id := range a.queue {
-				a.runTask(id)
-			}
+			defer a.releaseSlot(id, host)
+			a.runTask(id)
 		}()
 	}
 }
 
+// nextPendingTask blocks until a task can start, then takes its slot.
+// Callers must release the slot with releaseSlot.
+func (a *App) nextPendingTask() (string, string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		if !a.queuePaused && !a.shuttingDown && a.activeDownloads < a.maxConcurrency {
+			if index, host, ok := a.pickPendingLocked(); ok {
+				id := a.pending[index]
+				a.pending = append(a.pending[:index:index], a.pending[index+1:]...)
+				a.activeDownloads++
+				a.hostActive[host]++
+				return id, host
+			}
+		}
+		a.slotsChanged.Wait()
+	}
+}
+
+// pickPendingLocked returns the first pending task whose host has a free
+// slot. Tasks that are no longer queued, deleted or scheduled for later are
+// dropped along the way; scheduleLoop queues the last when they are due.
+func (a *App) pickPendingLocked() (int, string, bool) {
+	now := time.Now()
+	kept := a.pending[:0]
+	for _, id := range a.pending {
+		if task, ok := a.tasks[id]; ok && task.Status == statusQueued && !task.deleted() && !task.scheduledLater(now) {
+			kept = append(kept, id)
+		}
+	}
+	a.pending = kept
+	for index, id := range a.pending {
+		host := sourceHostFromURL(a.tasks[id].URL)
+		if a.hostActive[host] < a.hostLimitLocked(host) && !a.coolingDownLocked(host, now) {
+			return index, host, true
+		}
+	}
+	return 0, "", false
+}
+
+// hostLimitLocked returns how many downloads may run at once for host.
+func (a *App) hostLimitLocked(host string) int {
+	if override, ok := a.hostOverrideLocked(host); ok && override.MaxDownloads > 0 {
+		return override.MaxDownloads
+	}
+	return a.maxPerHost
+}
+
+// releaseSlot frees the slot taken for a task, unless the task already gave
+// it back when its download was suspended.
+func (a *App) releaseSlot(id, host string) {
+	a.mu.Lock()
+	if a.slotFreed[id] {
+		delete(a.slotFreed, id)
+		a.slotsChanged.Broadcast()
+	} else {
+		a.releaseSlotLocked(host)
+	}
+	a.mu.Unlock()
+}
+
+func (a *App) releaseSlotLocked(host string) {
+	a.activeDownloads--
+	if a.hostActive[host]--; a.hostActive[host] <= 0 {
+		delete(a.hostActive, host)
+	}
+	a.slotsChanged.Broadcast()
+}
+
+// GetMaxPerHost returns the default cap on simultaneous downloads per host.
+func (a *App) GetMaxPerHost() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.maxPerHost, nil
+}
+
+// SetMaxPerHost changes the default cap on simultaneous downloads per host.
+// Host overrides with MaxDownloads set take precedence.
+func (a *App) SetMaxPerHost(n int) error {
+	if n < 1 || n > maxConcurrencyLimit {
+		return fmt.Errorf("per-host limit must be between 1 and %d", maxConcurrencyLimit)
+	}
+	a.mu.Lock()
+	a.maxPerHost = n
+	a.slotsChanged.Broadcast()
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// GetMaxConcurrency returns how many downloads may run at once.
+func (a *App) GetMaxConcurrency() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.maxConcurrency, nil
+}
+
+// SetMaxConcurrency changes how many downloads may run at once. Lowering it
+// lets running downloads finish; no new ones start until below the limit.
+func (a *App) SetMaxConcurrency(n int) error {
+	if n < 1 || n > maxConcurrencyLimit {
+		return fmt.Errorf("concurrency must be between 1 and %d", maxConcurrencyLimit)
+	}
+	a.mu.Lock()
+	a.maxConcurrency = n
+	a.slotsChanged.Broadcast()
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// PauseQueue stops queued tasks from starting. Downloads already running are
+// left to finish.
+func (a *App) PauseQueue() {
+	a.setQueuePaused(true)
+}
+
+// ResumeQueue lets queued tasks start again.
+func (a *App) ResumeQueue() {
+	a.setQueuePaused(false)
+}
+
+func (a *App) setQueuePaused(paused bool) {
+	a.mu.Lock()
+	changed := a.queuePaused != paused
+	a.queuePaused = paused
+	if !paused {
+		a.slotsChanged.Broadcast()
+	}
+	a.mu.Unlock()
+	if changed {
+		a.emitQueueSummary()
+	}
+}
+
 func (a *App) enqueueTasks(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	queued := make(map[string]bool, len(a.pending))
+	for _, id := range a.pending {
+		queued[id] = true
+	}
 	for _, id := range ids {
-		a.queue <- id
+		if !queued[id] {
+			a.pending = append(a.pending, id)
+			queued[id] = true
+		}
 	}
+	a.slotsChanged.Broadcast()
 }
 
 func (a *App) runTask(id string) {
@@ -678,54 +1178,125 @@ func (a *App) runTask(id string) {
 		a.mu.Unlock()
 		return
 	}
+	if task.Status == statusCanceled || task.Status == statusPaused {
+		a.mu.Unlock()
+		return
+	}
 	resumeRequested := task.Resume
 	task.Resume = false
-	task.Status = statusRunning
-	task.Stage = "Resolve metadata"
+	if task.RetryCount == 0 {
+		task.MaxRetries = a.retryPolicy.MaxRetries
+	}
+	task.setStatus(statusRunning)
 	task.UpdatedAt = time.Now()
+	task.setStageCode(stageResolveMetadata, nil, task.UpdatedAt)
 	url := task.URL
+	taskDir := task.OutputDir
+	createdAt := task.CreatedAt
+	engine := task.Engine
+	formatID := task.FormatID
+	profileID := task.ProfileID
+	subtitles := task.Subtitles
+	taskProxy := task.Proxy
+	allowDuplicate := task.AllowDuplicate
+	clipStart, clipEnd := task.StartTime, task.EndTime
+	filenameTemplate := task.FilenameTemplate
+	extraArgs := task.ExtraArgs
+	dryRun := task.DryRun
+	recurring := task.RecurrenceID != ""
 	updated := *task
 	a.mu.Unlock()
 	a.emitTaskUpdate(updated)
 
-	metadata := a.fetchMetadata(url)
-	if metadata != nil {
-		a.mu.Lock()
-		task, ok = a.tasks[id]
-		if !ok {
-			a.mu.Unlock()
+	profile := a.taskProfile(profileID)
+	if subtitles == nil {
+		subtitles = profile.Subtitles
+	}
+	if taskDir == "" {
+		taskDir = profile.OutputDir
+	}
+
+	if engine == engineGalleryDl {
+		if taskDir == "" {
+			taskDir, _ = a.organizeTask(id, profile)
+		}
+		outputDir, err := taskOutputDir(taskDir, createdAt)
+		if err != nil {
+			a.failTask(id, taskError{Code: errorOutputDir, Message: "failed to resolve output directory"})
+			return
+		}
+		if dryRun {
+			a.finishDryRun(id, "gallery-dl "+strings.Join(redactArgs(a.galleryDlArgs(url, outputDir, taskProxy)), " "))
 			return
 		}
-		if shouldUpdateTitle(task.Title) && metadata.Title != "" {
-			task.Title = metadata.Title
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			a.failTask(id, taskError{Code: errorOutputDir, Message: "failed to create output directory"})
+			return
 		}
-		if metadata.Duration > 0 {
-			task.Duration = metadata.Duration
+		a.runGalleryTask(id, url, outputDir)
+		a.moveToLibrary(id)
+		a.runPostDownloadHook(id, profile)
+		return
+	}
+
+	if engine == engineDirect && dryRun {
+		a.finishDryRun(id, "GET "+url)
+		return
+	}
+	if engine == engineDirect {
+		if taskDir == "" {
+			taskDir, filenameTemplate = a.organizeTask(id, profile)
 		}
-		if metadata.Filesize > 0 {
-			task.Filesize = metadata.Filesize
+		outputDir, err := taskOutputDir(taskDir, createdAt)
+		if err != nil {
+			a.failTask(id, taskError{Code: errorOutputDir, Message: "failed to resolve output directory"})
+			return
 		}
-		if metadata.Width > 0 {
-			task.Width = metadata.Width
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			a.failTask(id, taskError{Code: errorOutputDir, Message: "failed to create output directory"})
+			return
 		}
-		if metadata.Height > 0 {
-			task.Height = metadata.Height
+		if a.runDirectTask(id, url, outputDir, taskProxy) {
+			a.moveToLibrary(id)
+			a.runPostDownloadHook(id, profile)
+			return
+		}
+		// The link served a web page; let yt-dlp extract the media from it.
+		a.mu.Lock()
+		if task, ok := a.tasks[id]; ok {
+			task.Engine = engineYtDlp
 		}
-		task.UpdatedAt = time.Now()
-		updated = *task
 		a.mu.Unlock()
-		a.emitTaskUpdate(updated)
-		a.saveTasks()
 	}
 
-	outputDir, err := taskOutputDir(task.CreatedAt)
+	if metadata := a.fetchMetadata(url, taskProxy); metadata != nil {
+		if _, ok := a.applyMetadata(id, metadata); !ok {
+			return
+		}
+		if duplicateOf, ok := a.skipMediaDuplicate(id); ok {
+			a.failTask(id, taskError{Code: errorDuplicate, Params: map[string]string{"task": duplicateOf}})
+			return
+		}
+		if !dryRun && a.holdForConfirmation(id) {
+			return
+		}
+	} else {
+		a.applyFallbackTitle(id, url)
+	}
+	if taskDir == "" {
+		taskDir, filenameTemplate = a.organizeTask(id, profile)
+	}
+
+	outputDir, err := taskOutputDir(taskDir, createdAt)
 	if err != nil {
-		a.failTask(id, "failed to resolve output directory")
+		a.failTask(id, taskError{Code: errorOutputDir, Message: "failed to resolve output directory"})
 		return
 	}
-	if err := os.MkdirAll(outputDir, 0o755); err != nil {
-		a.failTask(id, "failed to create output directory")
-		return
+	if !dryRun {
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			a.failTask(id, taskError{Code: errorOutputDir, Message: "failed to create output directory"})
+			return
+		}
 	}
 
 	a.mu.Lock()
@@ -734,42 +1305,178 @@ func (a *App) runTask(id string) {
 		a.mu.Unlock()
 		return
 	}
-	task.Stage = "Download"
 	task.UpdatedAt = time.Now()
+	task.setStageCode(stageDownload, nil, task.UpdatedAt)
 	updated = *task
 	a.mu.Unlock()
 	a.emitTaskUpdate(updated)
 
-	outputTemplate := filepath.Join(outputDir, "%(title)s.%(ext)s")
-	profile, _ := a.getActiveProfile()
-	args := []string{"--newline", "--progress-template", "progress:%(progress._percent_str)s|%(progress._speed_str)s|%(progress._eta_str)s"}
-	args = append(args, profile.Args...)
-	args = append(args, extraYtDlpArgs()...)
-	if a.useBrowserCookies {
-		args = append(args, "--cookies-from-browser", "chrome")
+	a.mu.Lock()
+	if filenameTemplate == "" {
+		filenameTemplate = a.filenameTemplateForLocked(profile)
 	}
-	if resumeRequested {
-		args = append(args, "--continue")
+	if recurring {
+		filenameTemplate = recurrenceFilenameTemplate(filenameTemplate, createdAt)
 	}
-	args = append(args, "-o", outputTemplate, url)
-	a.mu.Lock()
-	a.lastCommand = "yt-dlp " + strings.Join(args, " ")
-	a.mu.Unlock()
-	fmt.Println("FetchForge:", a.lastCommand)
-	cmd := a.ytDlpCommand(args...)
-	a.mu.Lock()
-	a.running[id] = cmd
+	outputTemplate := filepath.Join(outputDir, sanitizeTemplateLiterals(clipFilenameTemplate(filenameTemplate, clipStart, clipEnd), a.filenameMode))
 	a.mu.Unlock()
+	host := sourceHostFromURL(url)
 	defer func() {
 		a.mu.Lock()
 		delete(a.running, id)
+		delete(a.estimators, id)
+		delete(a.runningLimits, id)
+		delete(a.restartRequested, id)
+		delete(a.suspended, id)
 		a.mu.Unlock()
 	}()
 	startTime := time.Now()
 
-	stdoutText, stderrText, err := a.runCommandWithProgress(id, cmd)
-	if err != nil {
-		a.failTask(id, formatCommandError(err, cmd, stdoutText, stderrText))
+	tracker := &outputTracker{}
+	fallback, hasFallback := a.knownFallback(host)
+	knownFallbackID := fallback.ID
+	tried := make(map[string]bool)
+	if hasFallback {
+		tried[fallback.ID] = true
+	}
+	inArchive := false
+	renamed := false
+	collisionPolicy := a.currentCollisionPolicy()
+	if allowDuplicate && collisionPolicy == collisionSkip {
+		collisionPolicy = collisionRename
+	}
+	download := ytDlpDownload{
+		url:            url,
+		outputTemplate: outputTemplate,
+		profile:        profile,
+		formatID:       formatID,
+		subtitles:      subtitles,
+		clipStart:      clipStart,
+		clipEnd:        clipEnd,
+		extraArgs:      extraArgs,
+		taskProxy:      taskProxy,
+		allowDuplicate: allowDuplicate,
+		collision:      collisionPolicy,
+	}
+	if dryRun {
+		if hasFallback {
+			download.fallback = &fallback
+		}
+		download.rateLimit = a.rateLimitForHost(host, time.Now())
+		a.finishDryRun(id, "yt-dlp "+strings.Join(redactArgs(a.ytDlpDownloadArgs(download)), " "))
+		return
+	}
+	for {
+		download.fallback = nil
+		if hasFallback {
+			download.fallback = &fallback
+		}
+		rateLimit := a.rateLimitForHost(host, time.Now())
+		download.rateLimit = rateLimit
+		download.resume = resumeRequested
+		args := a.ytDlpDownloadArgs(download)
+		a.recordCommand(id, "yt-dlp", args)
+		cmd := a.ytDlpCommand(args...)
+		a.mu.Lock()
+		if task, ok := a.tasks[id]; !ok || task.Status == statusCanceled || task.Status == statusPaused || a.shuttingDown {
+			a.mu.Unlock()
+			return
+		}
+		a.running[id] = cmd
+		a.runningLimits[id] = rateLimit
+		a.mu.Unlock()
+
+		stdoutText, stderrText, err := a.runCommandWithProgress(id, cmd, tracker)
+		if a.taskStopped(id) {
+			return
+		}
+		if err == nil {
+			if hasFallback && fallback.ID != knownFallbackID {
+				a.rememberFallback(host, fallback.ID)
+			}
+			inArchive = len(tracker.outputs()) == 0 && strings.Contains(stdoutText+stderrText, archiveSkipMarker)
+			existing := tracker.alreadyDownloaded()
+			if existing == "" {
+				break
+			}
+			owner, collided := a.outputCollision(id, existing)
+			if !collided || renamed {
+				break
+			}
+			if collisionPolicy == collisionSkip {
+				a.mu.Lock()
+				if task, ok := a.tasks[id]; ok {
+					task.DuplicateOf = owner
+				}
+				a.mu.Unlock()
+				a.failTask(id, taskError{Code: errorDuplicate, Params: map[string]string{"task": owner}})
+				return
+			}
+			// The name is taken by another download; run again with a
+			// numbered name.
+			download.outputTemplate = numberedOutputTemplate(outputTemplate, existing, a.currentFilenameMode())
+			tracker.reset()
+			renamed = true
+			continue
+		}
+
+		a.mu.Lock()
+		restart := a.restartRequested[id]
+		delete(a.restartRequested, id)
+		a.mu.Unlock()
+		if restart {
+			a.mu.Lock()
+			task, ok = a.tasks[id]
+			if !ok {
+				a.mu.Unlock()
+				return
+			}
+			task.UpdatedAt = time.Now()
+			task.setStageCode(stageBandwidthLimit, nil, task.UpdatedAt)
+			updated = *task
+			a.mu.Unlock()
+			a.emitTaskUpdate(updated)
+			resumeRequested = true
+			continue
+		}
+
+		next, ok := nextRecoveryFallback(stdoutText+"\n"+stderrText, tried)
+		if !ok && a.manifestFallbackApplies(url, stdoutText+"\n"+stderrText) {
+			// A raw HLS/DASH link yt-dlp does not recognise; remux it with ffmpeg.
+			outputPath, failure := a.downloadManifest(id, url, outputDir, taskProxy)
+			if failure != nil {
+				a.failTask(id, *failure)
+				return
+			}
+			if outputPath == "" {
+				return
+			}
+			tracker.add(outputPath)
+			break
+		}
+		if !ok {
+			a.failTask(id, commandFailure(err, cmd, stdoutText, stderrText))
+			return
+		}
+		a.mu.Lock()
+		task, ok = a.tasks[id]
+		if !ok {
+			a.mu.Unlock()
+			return
+		}
+		task.UpdatedAt = time.Now()
+		task.setStageCode(stageRetry, map[string]string{"fallback": next.Name}, task.UpdatedAt)
+		updated = *task
+		a.mu.Unlock()
+		a.emitTaskUpdate(updated)
+
+		tried[next.ID] = true
+		fallback, hasFallback = next, true
+		resumeRequested = true
+	}
+
+	if inArchive {
+		a.failTask(id, taskError{Code: errorInArchive})
 		return
 	}
 
@@ -779,15 +1486,17 @@ func (a *App) runTask(id string) {
 		a.mu.Unlock()
 		return
 	}
-	task.Stage = "Finalize"
 	task.UpdatedAt = time.Now()
+	task.setStageCode(stageFinalize, nil, task.UpdatedAt)
 	updated = *task
 	a.mu.Unlock()
 	a.emitTaskUpdate(updated)
 
-	outputPath := newestFilePathAfter(outputDir, startTime)
-	if outputPath == "" {
-		outputPath = newestFilePath(outputDir)
+	outputs := tracker.outputs()
+	if len(outputs) == 0 {
+		if outputPath := newestFilePathAfter(outputDir, startTime); outputPath != "" {
+			outputs = []TaskOutput{statOutput(outputPath, outputKindForPath(outputPath))}
+		}
 	}
 	a.mu.Lock()
 	task, ok = a.tasks[id]
@@ -795,39 +1504,111 @@ func (a *App) runTask(id string) {
 		a.mu.Unlock()
 		return
 	}
-	task.Status = statusSuccess
-	task.Stage = "Finalize"
-	task.OutputPath = outputPath
-	task.ErrorMessage = ""
-	if outputPath != "" {
-		if shouldUpdateTitle(task.Title) {
-			task.Title = strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
-		}
-		if info, err := os.Stat(outputPath); err == nil && !info.IsDir() {
-			task.Filesize = info.Size()
-		}
+	task.setStatus(statusSuccess)
+	task.setOutputs(outputs)
+	task.PartialPath = ""
+	task.Embedded = tracker.embedded(profile)
+	task.clearError()
+	if outputPath := task.OutputPath; outputPath != "" && task.needsTitle() {
+		task.Title = strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
 	}
-	task.MissingOutput = outputMissing(outputPath)
 	task.Progress = "100%"
 	task.UpdatedAt = time.Now()
+	task.closeStage(task.UpdatedAt)
 	updated = *task
 	a.mu.Unlock()
 
 	a.emitTaskUpdate(updated)
 	a.saveTasks()
+	if profile.TranscodePreset != "" && updated.OutputPath != "" {
+		a.runProfileTranscode(id, profile)
+	}
+	if len(profile.PostProcess) > 0 && updated.OutputPath != "" {
+		a.runProfilePostProcess(id, profile)
+	}
+	a.moveToLibrary(id)
+	a.runPostDownloadHook(id, profile)
+	go a.recordChecksumsAndCheckDuplicate(id)
+}
+
+// ytDlpDownload describes one yt-dlp download command.
+type ytDlpDownload struct {
+	url            string
+	outputTemplate string
+	profile        Profile
+	formatID       string
+	subtitles      *SubtitleOptions
+	clipStart      string
+	clipEnd        string
+	extraArgs      []string
+	taskProxy      string
+	allowDuplicate bool
+	fallback       *recoveryFallback
+	rateLimit      string
+	resume         bool
+	collision      string
+}
+
+// ytDlpDownloadArgs builds the yt-dlp arguments for a download. Later
+// options win, so host fallbacks and overrides come after the profile.
+func (a *App) ytDlpDownloadArgs(d ytDlpDownload) []string {
+	host := sourceHostFromURL(d.url)
+	args := []string{"--newline", "--progress-template", progressTemplate}
+	args = append(args, d.profile.Args...)
+	if d.formatID != "" {
+		args = append(args, "-f", d.formatID)
+	}
+	args = append(args, subtitleArgs(d.subtitles)...)
+	args = append(args, clipArgs(d.clipStart, d.clipEnd)...)
+	args = append(args, embedArgs(d.profile)...)
+	args = append(args, filenameModeArgs(a.currentFilenameMode())...)
+	args = append(args, a.aria2Args(d.profile)...)
+	args = append(args, extraYtDlpArgs()...)
+	args = append(args, d.extraArgs...)
+	args = append(args, a.cookieArgs()...)
+	args = append(args, a.proxyArgs(d.taskProxy)...)
+	args = append(args, a.credentialArgs(host)...)
+	args = append(args, archiveArgs(d.profile, d.allowDuplicate || d.clipStart != "" || d.clipEnd != "")...)
+	if ffmpegPath := a.ffmpegBinary(); ffmpegPath != "" {
+		args = append(args, "--ffmpeg-location", ffmpegPath)
+	}
+	if d.fallback != nil {
+		args = append(args, d.fallback.Args...)
+	}
+	if override, ok := a.hostOverride(host); ok {
+		args = append(args, override.args()...)
+	}
+	if d.formatID == "" {
+		args = capFormatArgs(args, a.currentMaxHeight())
+	}
+	args = append(args, a.mediaPreferenceArgs(args)...)
+	args = append(args, rateLimitArgs(d.rateLimit)...)
+	args = append(args, collisionArgs(d.collision)...)
+	if d.resume {
+		args = append(args, "--continue")
+	}
+	return append(args, "-o", d.outputTemplate, d.url)
 }
 
-func (a *App) failTask(id, message string) {
+func (a *App) failTask(id string, failure taskError) {
 	a.mu.Lock()
 	task, ok := a.tasks[id]
 	if !ok {
 		a.mu.Unlock()
 		return
 	}
-	task.Status = statusFailed
-	task.Stage = "Finalize"
-	task.ErrorMessage = message
+	if a.scheduleCooldownLocked(task, failure) || a.scheduleRetryLocked(task, failure) {
+		updated := *task
+		a.mu.Unlock()
+		a.emitTaskUpdate(updated)
+		a.saveTasks()
+		return
+	}
+	task.setStatus(statusFailed)
+	task.setError(failure)
 	task.UpdatedAt = time.Now()
+	task.setStageCode(stageFinalize, nil, task.UpdatedAt)
+	task.closeStage(task.UpdatedAt)
 	updated := *task
 	a.mu.Unlock()
 
@@ -836,6 +1617,11 @@ func (a *App) failTask(id, message string) {
 }
 
 func (a *App) emitTaskUpdate(task Task) {
+	if task.deleted() {
+		return
+	}
+	a.publishTaskLifecycle(task)
+	a.remoteHub.broadcast("task:update", task)
 	if a.ctx == nil {
 		return
 	}
@@ -843,8 +1629,9 @@ func (a *App) emitTaskUpdate(task Task) {
 }
 
 func (a *App) prefetchTaskMetadata(id, url string) {
-	metadata := a.fetchMetadata(url)
+	metadata := a.fetchMetadata(url, "")
 	if metadata == nil {
+		a.applyFallbackTitle(id, url)
 		return
 	}
 	a.mu.Lock()
@@ -853,17 +1640,46 @@ func (a *App) prefetchTaskMetadata(id, url string) {
 		a.mu.Unlock()
 		return
 	}
-	if shouldUpdateTitle(task.Title) && metadata.Title != "" {
+	if task.needsTitle() && metadata.Title != "" {
 		task.Title = metadata.Title
 	}
+	task.setMediaID(metadata)
+	duplicateOf, duplicate := a.mediaDuplicateLocked(task)
 	task.UpdatedAt = time.Now()
 	updated := *task
 	a.mu.Unlock()
 	a.emitTaskUpdate(updated)
 	a.saveTasks()
+	if duplicate && a.ctx != nil {
+		wailsruntime.EventsEmit(a.ctx, "task:duplicate", map[string]string{"id": id, "duplicateOf": duplicateOf})
+	}
 }
 
-func (a *App) runCommandWithProgress(id string, cmd *exec.Cmd) (string, string, error) {
+func (a *App) runCommandWithProgress(id string, cmd *exec.Cmd, tracker *outputTracker) (string, string, error) {
+	return a.runTaskCommand(id, cmd, func(line string) {
+		if strings.HasPrefix(line, "progress:") {
+			progress := strings.TrimSpace(strings.TrimPrefix(line, "progress:"))
+			if progress != "" {
+				a.updateTaskProgress(id, progress)
+			}
+			return
+		}
+		if update, ok := parseAria2Progress(line); ok {
+			a.applyProgressUpdate(id, update)
+			return
+		}
+		if match := partialDestinationPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			a.setPartialPath(id, strings.TrimSpace(match[1])+".part")
+		}
+		if tracker != nil {
+			tracker.observe(line)
+		}
+	})
+}
+
+// runCommandWithLines starts cmd, calls onLine for every stdout/stderr line,
+// and returns the collected output once the process exits.
+func (a *App) runCommandWithLines(cmd *exec.Cmd, onLine func(string)) (string, string, error) {
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
 		return "", "", err
@@ -881,22 +1697,14 @@ func (a *App) runCommandWithProgress(id string, cmd *exec.Cmd) (string, string,
 	var stderrBuf bytes.Buffer
 	stdoutDone := make(chan struct{})
 	stderrDone := make(chan struct{})
-	parseProgress := func(line string) {
-		if strings.HasPrefix(line, "progress:") {
-			progress := strings.TrimSpace(strings.TrimPrefix(line, "progress:"))
-			if progress != "" {
-				a.updateTaskProgress(id, progress)
-			}
-		}
-	}
 
 	go func() {
-		readLines(stdoutPipe, &stdoutBuf, parseProgress)
+		readLines(stdoutPipe, &stdoutBuf, onLine)
 		close(stdoutDone)
 	}()
 
 	go func() {
-		readLines(stderrPipe, &stderrBuf, parseProgress)
+		readLines(stderrPipe, &stderrBuf, onLine)
 		close(stderrDone)
 	}()
 
@@ -908,22 +1716,18 @@ func (a *App) runCommandWithProgress(id string, cmd *exec.Cmd) (string, string,
 }
 
 func (a *App) updateTaskProgress(id, progress string) {
-	parts := strings.SplitN(progress, "|", 3)
-	percent := strings.TrimSpace(parts[0])
-	speed := ""
-	eta := ""
-	if len(parts) > 1 {
-		speed = strings.TrimSpace(parts[1])
-	}
-	if len(parts) > 2 {
-		eta = strings.TrimSpace(parts[2])
-	}
+	a.applyProgressUpdate(id, parseProgressLine(progress))
+}
+
+func (a *App) applyProgressUpdate(id string, update progressUpdate) {
+	percent := update.percent
 	a.mu.Lock()
 	task, ok := a.tasks[id]
 	if !ok {
 		a.mu.Unlock()
 		return
 	}
+	speed, eta := a.smoothProgress(id, update)
 	if task.Progress == percent && task.Speed == speed && task.ETA == eta {
 		a.mu.Unlock()
 		return
@@ -931,12 +1735,14 @@ func (a *App) updateTaskProgress(id, progress string) {
 	task.Progress = percent
 	task.Speed = speed
 	task.ETA = eta
+	task.DownloadedBytes = update.downloaded
+	task.TotalBytes = update.total
 	task.UpdatedAt = time.Now()
 	updated := *task
 	a.mu.Unlock()
 
 	a.emitTaskUpdate(updated)
-	a.saveTasks()
+	a.saveTasksSoon()
 }
 
 func readLines(reader io.Reader, buffer *bytes.Buffer, onLine func(string)) {
@@ -951,21 +1757,6 @@ func readLines(reader io.Reader, buffer *bytes.Buffer, onLine func(string)) {
 	}
 }
 
-func extractURLs(text string) []string {
-	re := regexp.MustCompile(`https?://[^\s]+`)
-	matches := re.FindAllString(text, -1)
-	out := make([]string, 0, len(matches))
-	seen := make(map[string]struct{})
-	for _, match := range matches {
-		if _, ok := seen[match]; ok {
-			continue
-		}
-		seen[match] = struct{}{}
-		out = append(out, match)
-	}
-	return out
-}
-
 func defaultTitleFromURL(rawURL string) string {
 	parsed, err := url.Parse(rawURL)
 	if err != nil {
@@ -994,23 +1785,12 @@ func sourceHostFromURL(rawURL string) string {
 	return host
 }
 
-func outputMissing(outputPath string) bool {
-	if strings.TrimSpace(outputPath) == "" {
-		return false
-	}
-	info, err := os.Stat(outputPath)
-	if err != nil || info.IsDir() {
-		return true
-	}
-	return false
-}
-
 func isPartialFile(name string) bool {
 	lower := strings.ToLower(name)
 	if strings.Contains(lower, ".part") {
 		return true
 	}
-	if strings.HasSuffix(lower, ".ytdl") {
+	if strings.HasSuffix(lower, ".ytdl") || strings.Contains(lower, ".transcoding.") {
 		return true
 	}
 	return false
@@ -1029,33 +1809,8 @@ func normalizeForMatch(value string) string {
 	return b.String()
 }
 
-func cleanupPartialFiles(createdAt time.Time, title string) {
-	outputDir, err := taskOutputDir(createdAt)
-	if err != nil {
-		return
-	}
-	normalizedTitle := normalizeForMatch(title)
-	_ = filepath.WalkDir(outputDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil || d.IsDir() {
-			return nil
-		}
-		name := d.Name()
-		if !isPartialFile(name) {
-			return nil
-		}
-		if normalizedTitle != "" {
-			normalizedName := normalizeForMatch(name)
-			if !strings.Contains(normalizedName, normalizedTitle) {
-				return nil
-			}
-		}
-		_ = os.Remove(path)
-		return nil
-	})
-}
-
-func resolveOutputPath(createdAt time.Time, title string) string {
-	outputDir, err := taskOutputDir(createdAt)
+func resolveOutputPath(taskDir string, createdAt time.Time, title string) string {
+	outputDir, err := taskOutputDir(taskDir, createdAt)
 	if err != nil {
 		return ""
 	}
@@ -1090,22 +1845,27 @@ func builtinProfiles() []Profile {
 			ID:   defaultProfileID,
 			Name: "Default",
 			Args: []string{},
+			Builtin: true,
 		},
 		{
 			ID:   "audio-only",
 			Name: "Audio Only",
 			Args: []string{"-x", "--audio-format", "mp3"},
+			Builtin: true,
 		},
 		{
 			ID:   "best-quality",
 			Name: "Best Quality",
 			Args: []string{"-f", "bv*+ba/b"},
+			Builtin: true,
 		},
 	}
 }
 
-func findProfileByID(id string) (Profile, bool) {
-	for _, profile := range builtinProfiles() {
+// findProfileLocked looks up a built-in or custom profile. The caller must
+// hold a.mu.
+func (a *App) findProfileLocked(id string) (Profile, bool) {
+	for _, profile := range append(builtinProfiles(), a.customProfiles...) {
 		if profile.ID == id {
 			return profile, true
 		}
@@ -1148,13 +1908,41 @@ func newID() string {
 	return hex.EncodeToString(buf)
 }
 
-func taskOutputDir(createdAt time.Time) (string, error) {
-	home, err := os.UserHomeDir()
+// taskOutputDir returns the folder a task downloads into: its explicit
+// override when set, otherwise the dated default folder.
+func taskOutputDir(override string, createdAt time.Time) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+	root, err := downloadRoot()
 	if err != nil {
 		return "", err
 	}
 	dateFolder := createdAt.Format("2006-01-02")
-	return filepath.Join(home, ".fetchforge", "downloads", dateFolder), nil
+	return filepath.Join(root, dateFolder), nil
+}
+
+// validateOutputDir normalizes a user supplied output folder, creating it if
+// needed and checking that it can be written to. Empty input is allowed.
+func validateOutputDir(dir string) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return "", nil
+	}
+	if !filepath.IsAbs(dir) {
+		return "", errors.New("output directory must be an absolute path")
+	}
+	dir = filepath.Clean(dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", errors.New("failed to create output directory")
+	}
+	probe, err := os.CreateTemp(dir, ".fetchforge-write-*")
+	if err != nil {
+		return "", errors.New("output directory is not writable")
+	}
+	probe.Close()
+	_ = os.Remove(probe.Name())
+	return dir, nil
 }
 
 func extraYtDlpArgs() []string {
@@ -1171,6 +1959,9 @@ func resolveYtDlpPath() string {
 			return envPath
 		}
 	}
+	if managed, err := managedToolPath("yt-dlp"); err == nil && fileExists(managed) {
+		return managed
+	}
 	if path, err := exec.LookPath("yt-dlp"); err == nil {
 		return path
 	}
@@ -1187,10 +1978,6 @@ func resolveYtDlpPath() string {
 			filepath.Join(exeDir, "..", "Resources", "yt-dlp"),
 		)
 	}
-	home, err := os.UserHomeDir()
-	if err == nil {
-		candidates = append(candidates, filepath.Join(home, ".fetchforge", "bin", "yt-dlp"))
-	}
 	for _, candidate := range candidates {
 		if fileExists(candidate) {
 			return candidate
@@ -1200,11 +1987,13 @@ func resolveYtDlpPath() string {
 }
 
 func (a *App) ytDlpCommand(args ...string) *exec.Cmd {
-	path := a.ytDlpPath
+	path := a.ytDlpBinary()
 	if path == "" {
 		path = "yt-dlp"
 	}
-	return exec.Command(path, args...)
+	cmd := exec.Command(path, args...)
+	configureProcessGroup(cmd)
+	return cmd
 }
 
 func fileExists(path string) bool {
@@ -1218,33 +2007,12 @@ func fileExists(path string) bool {
 	return true
 }
 
-func newestFilePath(root string) string {
-	var newestPath string
-	var newestTime time.Time
-
-	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
-		if err != nil || d.IsDir() {
-			return nil
-		}
-		info, err := d.Info()
-		if err != nil {
-			return nil
-		}
-		modTime := info.ModTime()
-		if newestPath == "" || modTime.After(newestTime) {
-			newestPath = path
-			newestTime = modTime
-		}
-		return nil
-	})
-
-	return newestPath
-}
-
 type ytdlpMetadata struct {
 	Title          string   `json:"title"`
 	Duration       *float64 `json:"duration"`
 	Extractor      string   `json:"extractor"`
+	ExtractorKey   string   `json:"extractor_key"`
+	ID             string   `json:"id"`
 	Resolution     string   `json:"resolution"`
 	Filesize       *float64 `json:"filesize"`
 	FilesizeApprox *float64 `json:"filesize_approx"`
@@ -1268,15 +2036,15 @@ type formatInfo struct {
 	Filesize   int64
 }
 
-func (a *App) fetchMetadata(targetURL string) *Task {
+// fetchMetadata probes a single video. proxy overrides the global proxy.
+func (a *App) fetchMetadata(targetURL string, proxy string) *Task {
 	if strings.TrimSpace(targetURL) == "" {
 		return nil
 	}
 	args := []string{"--skip-download", "--no-warnings", "--no-playlist", "-J"}
 	args = append(args, extraYtDlpArgs()...)
-	if a.useBrowserCookies {
-		args = append(args, "--cookies-from-browser", "chrome")
-	}
+	args = append(args, a.cookieArgs()...)
+	args = append(args, a.proxyArgs(proxy)...)
 	args = append(args, targetURL)
 	cmd := a.ytDlpCommand(args...)
 	output, err := cmd.Output()
@@ -1311,6 +2079,8 @@ func (a *App) fetchMetadata(targetURL string) *Task {
 		Width:      width,
 		Height:     height,
 		SourceHost: source,
+		Extractor:  mediaExtractor(info.ExtractorKey, info.Extractor),
+		VideoID:    strings.TrimSpace(info.ID),
 	}
 	return metadata
 }
@@ -1402,17 +2172,25 @@ func newestFilePathAfter(root string, after time.Time) string {
 	return newestPath
 }
 
+func commandToolName(cmd *exec.Cmd) string {
+	tool := strings.TrimSuffix(filepath.Base(cmd.Path), ".exe")
+	if tool == "" || tool == "." {
+		tool = "yt-dlp"
+	}
+	return tool
+}
+
 func formatCommandError(err error, cmd *exec.Cmd, stdoutText, stderrText string) string {
 	exitCode := ""
 	if exitErr, ok := err.(*exec.ExitError); ok {
 		exitCode = "exit code " + strconv.Itoa(exitErr.ExitCode())
 	}
 
-	commandLine := strings.Join(cmd.Args, " ")
+	commandLine := strings.Join(redactArgs(cmd.Args), " ")
 	stdoutText = strings.TrimSpace(stdoutText)
 	stderrText = strings.TrimSpace(stderrText)
 
-	parts := []string{"yt-dlp failed"}
+	parts := []string{commandToolName(cmd) + " failed"}
 	if exitCode != "" {
 		parts[0] = parts[0] + " (" + exitCode + ")"
 	}
@@ -1430,21 +2208,19 @@ func formatCommandError(err error, cmd *exec.Cmd, stdoutText, stderrText string)
 	return strings.Join(parts, "\n")
 }
 
+// loadTasks opens the task store, importing tasks.json on first run.
 func (a *App) loadTasks() {
-	path, err := tasksFilePath()
+	store, err := openTaskStore()
 	if err != nil {
 		return
 	}
-	data, err := os.ReadFile(path)
+	a.store = store
+	_ = store.migrateTasksJSON()
+	items, err := store.load()
 	if err != nil {
 		return
 	}
 
-	var items []Task
-	if err := json.Unmarshal(data, &items); err != nil {
-		return
-	}
-
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	for _, task := range items {
@@ -1454,17 +2230,19 @@ func (a *App) loadTasks() {
 	}
 }
 
+// saveTasks persists the tasks that changed since the last call. The store
+// lock is held across the snapshot so concurrent saves cannot write an older
+// snapshot over a newer one.
 func (a *App) saveTasks() {
-	path, err := tasksFilePath()
-	if err != nil {
-		return
-	}
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	store := a.store
+	if store == nil {
 		return
 	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
 
 	a.mu.Lock()
+	a.savePending = false
 	snapshot := make([]Task, 0, len(a.order))
 	for _, id := range a.order {
 		if task, ok := a.tasks[id]; ok {
@@ -1473,16 +2251,15 @@ func (a *App) saveTasks() {
 	}
 	a.mu.Unlock()
 
-	data, err := json.MarshalIndent(snapshot, "", "  ")
-	if err != nil {
-		return
-	}
+	_ = store.saveLocked(snapshot)
+}
 
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return
+func copyStringMap(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for key, value := range in {
+		out[key] = value
 	}
-	_ = os.Rename(tmpPath, path)
+	return out
 }
 
 func tasksFilePath() (string, error) {
@@ -1514,13 +2291,108 @@ func (a *App) loadConfig() {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return
 	}
-	if _, ok := findProfileByID(config.ActiveProfileID); !ok {
-		return
-	}
 	a.mu.Lock()
-	a.activeProfileID = config.ActiveProfileID
-	a.useBrowserCookies = config.UseBrowserCookies
+	// Custom profiles used to live in config.json; move them to profiles.json.
+	migrateProfiles := len(a.customProfiles) == 0 && len(config.CustomProfiles) > 0
+	if migrateProfiles {
+		a.customProfiles = config.CustomProfiles
+	}
+	if _, ok := a.findProfileLocked(config.ActiveProfileID); ok {
+		a.activeProfileID = config.ActiveProfileID
+	}
+	if isCookieBrowser(config.CookieBrowser) {
+		a.cookieBrowser = config.CookieBrowser
+	} else if config.UseBrowserCookies {
+		a.cookieBrowser = defaultCookieBrowser
+	}
+	a.cookiesFile = config.CookiesFile
+	if proxy, err := validateProxy(config.Proxy); err == nil {
+		a.proxy = proxy
+	}
+	a.siteCredentials = config.SiteCredentials
+	if hook, err := normalizeHookCommand(config.PostDownloadHook); err == nil {
+		a.postDownloadHook = hook
+	}
+	a.clipboardWatch = config.ClipboardWatch
+	if config.HostFallbacks != nil {
+		a.hostFallbacks = config.HostFallbacks
+	}
+	if config.GalleryDlRules != nil {
+		a.galleryDlRules = config.GalleryDlRules
+	}
+	a.bandwidthRules = config.BandwidthRules
+	if rateLimitPattern.MatchString(config.BandwidthLimit) {
+		a.bandwidthLimit = config.BandwidthLimit
+	}
+	a.bandwidthKeepRunning = config.BandwidthKeepRunning
+	a.noAutoResume = config.NoAutoResume
+	a.permanentDeleteFallback = config.PermanentDeleteFallback
+	if validFilenameMode(config.FilenameMode) {
+		a.filenameMode = config.FilenameMode
+	}
+	if validCollisionPolicy(config.CollisionPolicy) {
+		a.collisionPolicy = config.CollisionPolicy
+	}
+	if config.SizeConfirmThreshold > 0 {
+		a.sizeConfirmThreshold = config.SizeConfirmThreshold
+	}
+	if slices.Contains(maxHeights, config.MaxHeight) {
+		a.maxHeight = config.MaxHeight
+	}
+	if preferences, err := normalizeMediaPreferences(config.MediaPreferences); err == nil {
+		a.mediaPreferences = preferences
+	}
+	a.cleanupPolicy = config.CleanupPolicy
+	if aria2, err := normalizeAria2Settings(config.Aria2); err == nil {
+		a.aria2 = aria2
+	}
+	if rules, err := normalizeOrganizeRules(config.OrganizeRules); err == nil {
+		a.organizeRules = rules
+	}
+	if filepath.IsAbs(config.LibraryDirectory) {
+		a.libraryDir = filepath.Clean(config.LibraryDirectory)
+	}
+	if filepath.IsAbs(config.WatchDirectory) {
+		a.watchDir = filepath.Clean(config.WatchDirectory)
+	}
+	if config.RetentionPolicy.ArchiveAfterDays >= 0 && config.RetentionPolicy.DeleteAfterDays >= 0 {
+		a.retentionPolicy = config.RetentionPolicy
+	}
+	a.mqttConfig = config.MQTT
+	a.hostOverrides = config.HostOverrides
+	if config.MaxConcurrency >= 1 && config.MaxConcurrency <= maxConcurrencyLimit {
+		a.maxConcurrency = config.MaxConcurrency
+	}
+	if config.MaxPerHost >= 1 && config.MaxPerHost <= maxConcurrencyLimit {
+		a.maxPerHost = config.MaxPerHost
+	}
+	if policy := config.RetryPolicy; policy != nil && policy.MaxRetries >= 0 && policy.BaseDelaySeconds >= 1 {
+		a.retryPolicy = *policy
+	}
+	if minutes := config.RateLimitCooldown; minutes != nil && *minutes >= 0 && *minutes <= maxCooldownMinutes {
+		a.cooldownMinutes = *minutes
+	}
+	if validateFilenameTemplate(config.FilenameTemplate) == nil {
+		a.filenameTemplate = config.FilenameTemplate
+	}
+	if filepath.IsAbs(config.DownloadDirectory) {
+		setDownloadDirectory(filepath.Clean(config.DownloadDirectory))
+	}
+	if a.mqttConfig.TopicPrefix == "" {
+		a.mqttConfig.TopicPrefix = defaultMQTTTopicPrefix
+	}
+	a.remoteConfig = config.Remote
+	if a.remoteConfig.Port == 0 {
+		a.remoteConfig.Port = defaultRemotePort
+	}
+	if len(a.remoteConfig.Token) < 16 {
+		a.remoteConfig.Enabled = false
+	}
 	a.mu.Unlock()
+	if migrateProfiles {
+		a.saveProfiles()
+		a.saveConfig()
+	}
 }
 
 func (a *App) saveConfig() {
@@ -1532,19 +2404,63 @@ func (a *App) saveConfig() {
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return
 	}
-	a.mu.Lock()
-	config := appConfig{
-		ActiveProfileID: a.activeProfileID,
-		UseBrowserCookies: a.useBrowserCookies,
-	}
-	a.mu.Unlock()
+	config := a.configSnapshot()
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return
 	}
+	// config.json holds the remote access token and the MQTT password, so
+	// only the user may read it.
 	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return
+	}
+	if err := os.Chmod(tmpPath, 0o600); err != nil {
 		return
 	}
 	_ = os.Rename(tmpPath, path)
 }
+
+// configSnapshot collects the settings saved to config.json.
+func (a *App) configSnapshot() appConfig {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	retryPolicy := a.retryPolicy
+	cooldownMinutes := a.cooldownMinutes
+	return appConfig{
+		ActiveProfileID: a.activeProfileID,
+		CookieBrowser:   a.cookieBrowser,
+		CookiesFile:     a.cookiesFile,
+		Proxy:           a.proxy,
+		SiteCredentials: a.siteCredentials,
+		PostDownloadHook: a.postDownloadHook,
+		ClipboardWatch:  a.clipboardWatch,
+		HostFallbacks:   copyStringMap(a.hostFallbacks),
+		GalleryDlRules:  a.galleryDlRules,
+		BandwidthRules:  a.bandwidthRules,
+		BandwidthLimit:  a.bandwidthLimit,
+		BandwidthKeepRunning: a.bandwidthKeepRunning,
+		NoAutoResume:    a.noAutoResume,
+		PermanentDeleteFallback: a.permanentDeleteFallback,
+		FilenameMode:    a.filenameMode,
+		CollisionPolicy: a.collisionPolicy,
+		SizeConfirmThreshold: a.sizeConfirmThreshold,
+		MaxHeight:       a.maxHeight,
+		MediaPreferences: a.mediaPreferences,
+		CleanupPolicy:   a.cleanupPolicy,
+		Aria2:           a.aria2,
+		OrganizeRules:   a.organizeRules,
+		LibraryDirectory: a.libraryDir,
+		WatchDirectory:  a.watchDir,
+		RetentionPolicy: a.retentionPolicy,
+		MQTT:            a.mqttConfig,
+		Remote:          a.remoteConfig,
+		HostOverrides:   a.hostOverrides,
+		MaxConcurrency:  a.maxConcurrency,
+		MaxPerHost:      a.maxPerHost,
+		RetryPolicy:     &retryPolicy,
+		RateLimitCooldown: &cooldownMinutes,
+		DownloadDirectory: configuredDownloadDirectory(),
+		FilenameTemplate: a.filenameTemplate,
+	}
+}
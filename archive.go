@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// archiveSkipMarker is what yt-dlp prints for a video it skips because the
+// download archive already lists it.
+const archiveSkipMarker = "has already been recorded in the archive"
+
+// ArchiveEntry is one line of the download archive: the yt-dlp extractor
+// key and the video ID.
+type ArchiveEntry struct {
+	Extractor string `json:"extractor"`
+	VideoID   string `json:"videoId"`
+}
+
+func (e ArchiveEntry) line() string {
+	return strings.ToLower(e.Extractor) + " " + e.VideoID
+}
+
+func downloadArchivePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "archive.txt"), nil
+}
+
+// archiveArgs makes yt-dlp record finished videos in the app's download
+// archive and skip the ones already listed, for profiles that enable it.
+// Duplicates the user asked for and clips bypass the archive.
+func archiveArgs(profile Profile, allowDuplicate bool) []string {
+	if !profile.DownloadArchive || allowDuplicate {
+		return nil
+	}
+	path, err := downloadArchivePath()
+	if err != nil {
+		return nil
+	}
+	return []string{"--download-archive", path}
+}
+
+// ListArchiveEntries returns the videos recorded in the download archive.
+func (a *App) ListArchiveEntries() ([]ArchiveEntry, error) {
+	a.archiveMu.Lock()
+	defer a.archiveMu.Unlock()
+	return readArchive()
+}
+
+// RemoveArchiveEntries drops videos from the download archive so they can
+// be downloaded again.
+func (a *App) RemoveArchiveEntries(entries []ArchiveEntry) error {
+	remove := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		remove[entry.line()] = true
+	}
+	a.archiveMu.Lock()
+	defer a.archiveMu.Unlock()
+	current, err := readArchive()
+	if err != nil {
+		return err
+	}
+	kept := current[:0]
+	for _, entry := range current {
+		if !remove[entry.line()] {
+			kept = append(kept, entry)
+		}
+	}
+	return writeArchive(kept)
+}
+
+// ClearDownloadArchive empties the download archive.
+func (a *App) ClearDownloadArchive() error {
+	a.archiveMu.Lock()
+	defer a.archiveMu.Unlock()
+	return writeArchive(nil)
+}
+
+func readArchive() ([]ArchiveEntry, error) {
+	path, err := downloadArchivePath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []ArchiveEntry{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	entries := []ArchiveEntry{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		extractor, videoID, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if ok && extractor != "" && videoID != "" {
+			entries = append(entries, ArchiveEntry{Extractor: extractor, VideoID: videoID})
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// writeArchive replaces the archive through a temporary file so a running
+// yt-dlp never reads a half-written one.
+func writeArchive(entries []ArchiveEntry) error {
+	path, err := downloadArchivePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	var builder strings.Builder
+	for _, entry := range entries {
+		builder.WriteString(entry.line())
+		builder.WriteString("\n")
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(builder.String()), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return errors.New("failed to update the download archive: " + err.Error())
+	}
+	return nil
+}
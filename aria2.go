@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAria2Connections = 8
+	maxAria2Connections     = 16
+)
+
+// Aria2Settings hands downloads to aria2c through yt-dlp's --downloader
+// option, splitting each file over Connections connections. The global
+// setting applies unless the task's profile sets its own.
+type Aria2Settings struct {
+	Enabled     bool `json:"enabled"`
+	Connections int  `json:"connections"`
+}
+
+// aria2ProgressPattern matches aria2c's summary lines, such as
+// "[#2089b0 400KiB/33MiB(1%) CN:4 DL:115KiB ETA:4m51s]".
+var aria2ProgressPattern = regexp.MustCompile(`^\[#[0-9a-f]+ ([\d.]+[KMGT]?i?B)/([\d.]+[KMGT]?i?B)\((\d+)%\)(?: CN:\d+)?(?: SD:\d+)?(?: DL:([\d.]+[KMGT]?i?B))?(?: ETA:([\dhms]+))?\]$`)
+
+// GetAria2Settings returns the global aria2c settings.
+func (a *App) GetAria2Settings() (Aria2Settings, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.aria2, nil
+}
+
+// SetAria2Settings changes the global aria2c settings. Connections of 0 uses
+// the default.
+func (a *App) SetAria2Settings(settings Aria2Settings) error {
+	normalized, err := normalizeAria2Settings(settings)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	if normalized.Enabled && a.aria2cPath == "" {
+		a.mu.Unlock()
+		return errors.New("aria2c was not found")
+	}
+	a.aria2 = normalized
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+func normalizeAria2Settings(settings Aria2Settings) (Aria2Settings, error) {
+	if settings.Connections == 0 {
+		settings.Connections = defaultAria2Connections
+	}
+	if settings.Connections < 1 || settings.Connections > maxAria2Connections {
+		return Aria2Settings{}, fmt.Errorf("connections must be between 1 and %d", maxAria2Connections)
+	}
+	return settings, nil
+}
+
+// aria2Args returns the yt-dlp arguments that switch a download to aria2c,
+// or nil when aria2c is off for the profile or not installed.
+func (a *App) aria2Args(profile Profile) []string {
+	a.mu.Lock()
+	settings := a.aria2
+	path := a.aria2cPath
+	a.mu.Unlock()
+	if profile.Aria2 != nil {
+		settings = *profile.Aria2
+	}
+	if !settings.Enabled || path == "" {
+		return nil
+	}
+	connections := strconv.Itoa(settings.Connections)
+	return []string{
+		"--downloader", path,
+		"--downloader-args", "aria2c:-x " + connections + " -s " + connections + " -k 1M --summary-interval=1",
+	}
+}
+
+// parseAria2Progress turns an aria2c summary line into a progress update.
+func parseAria2Progress(line string) (progressUpdate, bool) {
+	match := aria2ProgressPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return progressUpdate{}, false
+	}
+	update := progressUpdate{
+		percent:    match[3] + "%",
+		downloaded: parseAria2Size(match[1]),
+		total:      parseAria2Size(match[2]),
+		rawSpeed:   float64(parseAria2Size(match[4])),
+	}
+	update.speed = formatSpeed(update.rawSpeed)
+	if eta, err := time.ParseDuration(match[5]); err == nil {
+		update.eta = formatETA(int64(eta.Seconds()))
+	}
+	return update, true
+}
+
+func parseAria2Size(value string) int64 {
+	multiplier := float64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   float64
+	}{{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSuffix(value, unit.suffix)
+			multiplier = unit.size
+			break
+		}
+	}
+	return int64(parseProgressFloat(value) * multiplier)
+}
+
+func resolveAria2cPath() string {
+	if envPath := strings.TrimSpace(os.Getenv("FETCHFORGE_ARIA2C_PATH")); envPath != "" {
+		if fileExists(envPath) {
+			return envPath
+		}
+	}
+	if path, err := exec.LookPath("aria2c"); err == nil {
+		return path
+	}
+	candidates := []string{
+		"/opt/homebrew/bin/aria2c",
+		"/usr/local/bin/aria2c",
+		"/usr/bin/aria2c",
+	}
+	if exe, err := os.Executable(); err == nil {
+		exeDir := filepath.Dir(exe)
+		candidates = append(candidates,
+			filepath.Join(exeDir, "aria2c"),
+			filepath.Join(exeDir, "..", "Resources", "aria2c"),
+		)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".fetchforge", "bin", "aria2c"))
+	}
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func (a *App) checkAria2c() DiagnosticCheck {
+	check := DiagnosticCheck{ID: "aria2c", Name: "aria2c"}
+	a.mu.Lock()
+	enabled := a.aria2.Enabled
+	a.mu.Unlock()
+	if a.aria2cPath == "" {
+		check.Status = diagnosticOK
+		check.Detail = "aria2c was not found"
+		if enabled {
+			check.Status = diagnosticWarning
+			check.Hint = "aria2c is turned on but missing; downloads use yt-dlp's own downloader."
+		}
+		return check
+	}
+	version, err := commandVersion(a.aria2cPath, "--version")
+	if err != nil {
+		check.Status = diagnosticWarning
+		check.Detail = a.aria2cPath + ": " + err.Error()
+		return check
+	}
+	check.Status = diagnosticOK
+	check.Detail = version + " (" + a.aria2cPath + ")"
+	return check
+}
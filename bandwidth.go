@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const bandwidthCheckInterval = 30 * time.Second
+
+var rateLimitPattern = regexp.MustCompile(`^(?i)\d+(\.\d+)?[KMG]?$`)
+
+// BandwidthRule limits download speed during a daily time window.
+// Start and End are "HH:MM" in local time; a window may wrap past midnight.
+// An empty Limit means unlimited.
+type BandwidthRule struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Limit string `json:"limit"`
+}
+
+// GetBandwidthRules returns the scheduled bandwidth rules.
+func (a *App) GetBandwidthRules() ([]BandwidthRule, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]BandwidthRule, len(a.bandwidthRules))
+	copy(out, a.bandwidthRules)
+	return out, nil
+}
+
+// SetBandwidthRules replaces the scheduled bandwidth rules. The first rule
+// whose window contains the current time wins.
+func (a *App) SetBandwidthRules(rules []BandwidthRule) error {
+	cleaned := make([]BandwidthRule, 0, len(rules))
+	for _, rule := range rules {
+		rule.Start = strings.TrimSpace(rule.Start)
+		rule.End = strings.TrimSpace(rule.End)
+		rule.Limit = strings.TrimSpace(rule.Limit)
+		if _, err := parseClock(rule.Start); err != nil {
+			return err
+		}
+		if _, err := parseClock(rule.End); err != nil {
+			return err
+		}
+		if rule.Limit != "" && !rateLimitPattern.MatchString(rule.Limit) {
+			return fmt.Errorf("invalid rate limit %q", rule.Limit)
+		}
+		cleaned = append(cleaned, rule)
+	}
+	a.mu.Lock()
+	a.bandwidthRules = cleaned
+	a.mu.Unlock()
+	a.saveConfig()
+	a.applyBandwidthChange()
+	return nil
+}
+
+// GetBandwidthLimit returns the global download speed cap. Empty means
+// unlimited.
+func (a *App) GetBandwidthLimit() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.bandwidthLimit, nil
+}
+
+// SetBandwidthLimit sets the global download speed cap in yt-dlp syntax
+// (e.g. "500K", "4M"). It applies whenever no scheduled rule matches.
+func (a *App) SetBandwidthLimit(rate string) error {
+	rate = strings.TrimSpace(rate)
+	if rate != "" && !rateLimitPattern.MatchString(rate) {
+		return fmt.Errorf("invalid rate limit %q", rate)
+	}
+	a.mu.Lock()
+	a.bandwidthLimit = rate
+	a.mu.Unlock()
+	a.saveConfig()
+	a.applyBandwidthChange()
+	return nil
+}
+
+// GetRestartOnBandwidthChange reports whether running downloads are restarted
+// with the new limit when a rule window starts or ends.
+func (a *App) GetRestartOnBandwidthChange() (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return !a.bandwidthKeepRunning, nil
+}
+
+// SetRestartOnBandwidthChange chooses whether running downloads pick up a
+// changed limit by restarting. When off, a new limit only applies to
+// downloads that start afterwards.
+func (a *App) SetRestartOnBandwidthChange(restart bool) error {
+	a.mu.Lock()
+	a.bandwidthKeepRunning = !restart
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// GetCurrentBandwidthLimit returns the limit in effect right now.
+func (a *App) GetCurrentBandwidthLimit() (string, error) {
+	return a.currentRateLimit(time.Now()), nil
+}
+
+func (a *App) currentRateLimit(now time.Time) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.scheduledRateLimitLocked(now)
+}
+
+// scheduledRateLimitLocked returns the limit of the first matching rule, or
+// the global limit outside every window. The caller must hold a.mu.
+func (a *App) scheduledRateLimitLocked(now time.Time) string {
+	rules := a.bandwidthRules
+	minute := now.Hour()*60 + now.Minute()
+	for _, rule := range rules {
+		start, err := parseClock(rule.Start)
+		if err != nil {
+			continue
+		}
+		end, err := parseClock(rule.End)
+		if err != nil {
+			continue
+		}
+		if clockInWindow(minute, start, end) {
+			return rule.Limit
+		}
+	}
+	return a.bandwidthLimit
+}
+
+// parseClock converts "HH:MM" into minutes after midnight.
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func clockInWindow(minute, start, end int) bool {
+	if start == end {
+		return true
+	}
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+func rateLimitArgs(limit string) []string {
+	if limit == "" {
+		return nil
+	}
+	return []string{"--limit-rate", limit}
+}
+
+// bandwidthLoop watches for rule boundaries and restarts running downloads
+// whose limit no longer matches the schedule and their host override, unless
+// restarts are turned off.
+func (a *App) bandwidthLoop() {
+	ticker := time.NewTicker(bandwidthCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.applyBandwidthChange()
+	}
+}
+
+func (a *App) applyBandwidthChange() {
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.bandwidthKeepRunning {
+		return
+	}
+	for id, applied := range a.runningLimits {
+		task, ok := a.tasks[id]
+		if !ok {
+			continue
+		}
+		if applied == a.rateLimitForHostLocked(sourceHostFromURL(task.URL), now) {
+			continue
+		}
+		cmd, ok := a.running[id]
+		if !ok || cmd.Process == nil || a.suspended[id] {
+			continue
+		}
+		a.restartRequested[id] = true
+		_ = killProcessTree(cmd)
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// BatchResult reports the outcome of a batch operation. Failed maps the ids
+// that were skipped to the reason.
+type BatchResult struct {
+	Succeeded []string          `json:"succeeded"`
+	Failed    map[string]string `json:"failed"`
+}
+
+func newBatchResult() BatchResult {
+	return BatchResult{Succeeded: []string{}, Failed: map[string]string{}}
+}
+
+func (r BatchResult) err(id string) error {
+	if message, ok := r.Failed[id]; ok {
+		return errors.New(message)
+	}
+	return nil
+}
+
+// BatchSummary is the combined state of tasks that share a BatchID: links
+// pasted together, a playlist or a subscription check. Counts is keyed by
+// status code. Percent averages the tasks' progress, counting finished tasks
+// as complete.
+type BatchSummary struct {
+	ID              string         `json:"id"`
+	TaskIDs         []string       `json:"taskIds"`
+	Total           int            `json:"total"`
+	Counts          map[string]int `json:"counts"`
+	Done            int            `json:"done"`
+	Percent         float64        `json:"percent"`
+	DownloadedBytes int64          `json:"downloadedBytes"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+}
+
+// ListBatches summarizes every batch, newest first. Batch actions go through
+// the usual batch calls, such as RetryTasks or DeleteTasks, with TaskIDs.
+func (a *App) ListBatches() ([]BatchSummary, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	index := make(map[string]int)
+	batches := []BatchSummary{}
+	progress := make(map[string]float64)
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok || task.BatchID == "" || task.DeletedAt != nil {
+			continue
+		}
+		i, ok := index[task.BatchID]
+		if !ok {
+			i = len(batches)
+			index[task.BatchID] = i
+			batches = append(batches, BatchSummary{
+				ID:        task.BatchID,
+				TaskIDs:   []string{},
+				Counts:    map[string]int{},
+				CreatedAt: task.CreatedAt,
+			})
+		}
+		batch := &batches[i]
+		batch.TaskIDs = append(batch.TaskIDs, id)
+		batch.Total++
+		batch.Counts[statusCodeFor(task.Status)]++
+		batch.DownloadedBytes += task.DownloadedBytes
+		if task.CreatedAt.Before(batch.CreatedAt) {
+			batch.CreatedAt = task.CreatedAt
+		}
+		if task.UpdatedAt.After(batch.UpdatedAt) {
+			batch.UpdatedAt = task.UpdatedAt
+		}
+		switch task.Status {
+		case statusSuccess, statusFailed, statusCanceled:
+			batch.Done++
+			progress[task.BatchID]++
+		case statusRunning, statusPaused:
+			total := task.TotalBytes
+			if total == 0 {
+				total = task.Filesize
+			}
+			if total > 0 {
+				progress[task.BatchID] += min(float64(task.DownloadedBytes)/float64(total), 1)
+			}
+		}
+	}
+	for i := range batches {
+		batches[i].Percent = math.Round(progress[batches[i].ID]/float64(batches[i].Total)*1000) / 10
+	}
+	sort.SliceStable(batches, func(i, j int) bool { return batches[i].CreatedAt.After(batches[j].CreatedAt) })
+	return batches, nil
+}
+
+// DeleteTasks removes several tasks and moves their files to the trash,
+// writing the task list once. The deletion can be undone for a short while;
+// see UndoDelete.
+func (a *App) DeleteTasks(ids []string) (BatchResult, error) {
+	return a.deleteTasks(ids, false), nil
+}
+
+// RemoveTaskEntries removes several tasks from the list, leaving their
+// downloaded files on disk.
+func (a *App) RemoveTaskEntries(ids []string) (BatchResult, error) {
+	return a.deleteTasks(ids, true), nil
+}
+
+// ResumeTasks re-queues several tasks to continue interrupted downloads.
+func (a *App) ResumeTasks(ids []string) (BatchResult, error) {
+	return a.resumeTasks(ids), nil
+}
+
+// RetryTasks restarts several failed or canceled tasks from the beginning.
+func (a *App) RetryTasks(ids []string) (BatchResult, error) {
+	result := newBatchResult()
+	var updated []Task
+	a.mu.Lock()
+	for _, id := range uniqueIDs(ids) {
+		task, ok := a.tasks[id]
+		if !ok {
+			result.Failed[id] = "task not found"
+			continue
+		}
+		if task.deleted() {
+			result.Failed[id] = "task is deleted"
+			continue
+		}
+		if task.Status != statusFailed && task.Status != statusCanceled {
+			result.Failed[id] = "task has not failed"
+			continue
+		}
+		requeueTaskLocked(task, stageRestart, false)
+		updated = append(updated, *task)
+		result.Succeeded = append(result.Succeeded, id)
+	}
+	a.mu.Unlock()
+
+	a.finishRequeue(updated, result.Succeeded)
+	return result, nil
+}
+
+func (a *App) resumeTasks(ids []string) BatchResult {
+	result := newBatchResult()
+	var updated []Task
+	a.mu.Lock()
+	for _, id := range uniqueIDs(ids) {
+		task, ok := a.tasks[id]
+		if !ok {
+			result.Failed[id] = "task not found"
+			continue
+		}
+		if task.deleted() {
+			result.Failed[id] = "task is deleted"
+			continue
+		}
+		if task.Status == statusRunning && time.Since(task.UpdatedAt) < 30*time.Second {
+			result.Failed[id] = "task is already running"
+			continue
+		}
+		requeueTaskLocked(task, stageResume, true)
+		updated = append(updated, *task)
+		result.Succeeded = append(result.Succeeded, id)
+	}
+	a.mu.Unlock()
+
+	a.finishRequeue(updated, result.Succeeded)
+	return result
+}
+
+func (a *App) finishRequeue(updated []Task, ids []string) {
+	if len(updated) == 0 {
+		return
+	}
+	for _, task := range updated {
+		a.emitTaskUpdate(task)
+	}
+	a.saveTasks()
+	a.enqueueTasks(ids)
+}
+
+// deleteTasks removes tasks. With keepFiles set they are dropped right away
+// and their files are left alone; otherwise they are marked deleted and
+// purged, files included, once the undo window has passed.
+func (a *App) deleteTasks(ids []string, keepFiles bool) BatchResult {
+	if keepFiles {
+		return a.purgeTasks(ids, true)
+	}
+	return a.markTasksDeleted(ids)
+}
+
+// purgeTasks removes tasks, stopping any that are running. Their output and
+// partial files are moved to the trash unless keepFiles is set.
+func (a *App) purgeTasks(ids []string, keepFiles bool) BatchResult {
+	type deletion struct {
+		id          string
+		outputPaths []string
+		partialPath string
+		title       string
+	}
+	result := newBatchResult()
+	var deletions []deletion
+	a.mu.Lock()
+	for _, id := range uniqueIDs(ids) {
+		task, ok := a.tasks[id]
+		if !ok {
+			result.Failed[id] = "task not found"
+			continue
+		}
+		if cmd, ok := a.running[id]; ok {
+			_ = killProcessTree(cmd)
+			delete(a.running, id)
+		}
+		deletions = append(deletions, deletion{
+			id:          id,
+			outputPaths: task.outputPaths(),
+			partialPath: task.PartialPath,
+			title:       task.Title,
+		})
+	}
+	a.mu.Unlock()
+
+	if len(deletions) == 0 {
+		return result
+	}
+	if len(deletions) == 1 {
+		a.snapshotTasks("Before deleting " + deletions[0].title)
+	} else {
+		a.snapshotTasks(fmt.Sprintf("Before deleting %d tasks", len(deletions)))
+	}
+	for _, item := range deletions {
+		if keepFiles {
+			result.Succeeded = append(result.Succeeded, item.id)
+			continue
+		}
+		var trashErr error
+		for _, outputPath := range item.outputPaths {
+			if info, err := os.Stat(outputPath); err == nil && !info.IsDir() {
+				if err := a.moveToTrash(outputPath); err != nil {
+					trashErr = err
+					break
+				}
+			}
+		}
+		if trashErr != nil {
+			result.Failed[item.id] = trashErr.Error()
+			continue
+		}
+		removeTaskPartials(item.partialPath, item.outputPaths)
+		result.Succeeded = append(result.Succeeded, item.id)
+	}
+	if len(result.Succeeded) == 0 {
+		return result
+	}
+
+	a.mu.Lock()
+	a.removeTasksLocked(result.Succeeded)
+	a.mu.Unlock()
+
+	a.emitTasksRemoved(result.Succeeded)
+	a.saveTasks()
+	a.emitQueueSummary()
+	return result
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
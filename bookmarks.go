@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// bookmarkTokenPattern walks a Netscape bookmark file, the HTML format both
+// Chrome and Firefox export: <H3> names the folder whose <DL> follows, and
+// <A HREF> is a bookmark.
+var bookmarkTokenPattern = regexp.MustCompile(`(?is)<h3\b[^>]*>(.*?)</h3>|<a\s([^>]*)>|<dl\b|</dl>`)
+
+// chromeBookmarkRoots is the order Chrome shows its top-level folders in.
+var chromeBookmarkRoots = []string{"bookmark_bar", "other", "synced"}
+
+// bookmark is one link and the "/"-separated path of folders it is in.
+type bookmark struct {
+	URL    string
+	Folder string
+}
+
+// ListBookmarkFolders returns the folder paths in a bookmark export, such as
+// "Bookmarks bar/to download", for choosing what to import.
+func (a *App) ListBookmarkFolders(path string) ([]string, error) {
+	bookmarks, folders, err := readBookmarkFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(bookmarks) == 0 && len(folders) == 0 {
+		return nil, errors.New("no bookmarks found")
+	}
+	return folders, nil
+}
+
+// ImportFromBookmarks queues the links in a Chrome or Firefox bookmark
+// export: the HTML file either browser exports, Chrome's Bookmarks JSON file
+// or a Firefox JSON backup. With folder set, only bookmarks in that folder
+// and its subfolders are imported; folder may be a full path from
+// ListBookmarkFolders or just the last part of one, e.g. "to download".
+// Links that already have a task are skipped, as in ImportURLsFromFile.
+func (a *App) ImportFromBookmarks(path string, folder string) (ImportResult, error) {
+	bookmarks, folders, err := readBookmarkFile(path)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	wanted := splitBookmarkFolder(folder)
+	if len(wanted) > 0 {
+		found := false
+		for _, candidate := range folders {
+			if inBookmarkFolder(candidate, wanted) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ImportResult{}, fmt.Errorf("bookmark folder %q not found", strings.Join(wanted, "/"))
+		}
+	}
+	var urls []string
+	for _, item := range bookmarks {
+		if len(wanted) == 0 || inBookmarkFolder(item.Folder, wanted) {
+			urls = append(urls, item.URL)
+		}
+	}
+	return a.importURLs(urls), nil
+}
+
+// readBookmarkFile returns the http(s) bookmarks in an export and every
+// folder path, in file order.
+func readBookmarkFile(path string) ([]bookmark, []string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil, errors.New("file path is required")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return nil, nil, errors.New("path is a directory")
+	}
+	if info.Size() > maxImportFileSize {
+		return nil, nil, fmt.Errorf("file is larger than %d MB", maxImportFileSize>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader := &bookmarkReader{seenFolders: make(map[string]bool)}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var root map[string]interface{}
+		if err := json.Unmarshal(trimmed, &root); err != nil {
+			return nil, nil, err
+		}
+		reader.readJSON(root)
+	} else {
+		reader.readHTML(string(data))
+	}
+	return reader.bookmarks, reader.folders, nil
+}
+
+type bookmarkReader struct {
+	bookmarks   []bookmark
+	folders     []string
+	seenFolders map[string]bool
+}
+
+func (r *bookmarkReader) addFolder(folder string) {
+	if folder != "" && !r.seenFolders[folder] {
+		r.seenFolders[folder] = true
+		r.folders = append(r.folders, folder)
+	}
+}
+
+func (r *bookmarkReader) addBookmark(rawURL, folder string) {
+	rawURL = strings.TrimSpace(rawURL)
+	if isHTTPURL(rawURL) {
+		r.bookmarks = append(r.bookmarks, bookmark{URL: rawURL, Folder: folder})
+	}
+}
+
+func (r *bookmarkReader) readHTML(markup string) {
+	var stack []string
+	pending := ""
+	for _, match := range bookmarkTokenPattern.FindAllStringSubmatch(markup, -1) {
+		token := strings.ToLower(match[0])
+		switch {
+		case strings.HasPrefix(token, "<h3"):
+			pending = cleanBookmarkName(match[1])
+		case strings.HasPrefix(token, "<a"):
+			if _, value, ok := linkAttr(" " + match[2]); ok {
+				r.addBookmark(value, joinBookmarkFolder(stack))
+			}
+		case token == "</dl>":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		default:
+			// The outermost <DL> has no heading; it holds the top-level
+			// folders.
+			stack = append(stack, pending)
+			pending = ""
+			r.addFolder(joinBookmarkFolder(stack))
+		}
+	}
+}
+
+// readJSON reads Chrome's {"roots": {...}} file and Firefox backups, where
+// folders have "children" and bookmarks a "url" or "uri".
+func (r *bookmarkReader) readJSON(root map[string]interface{}) {
+	roots, ok := root["roots"].(map[string]interface{})
+	if !ok {
+		r.readJSONNode(root, nil)
+		return
+	}
+	for _, key := range chromeBookmarkRoots {
+		if node, ok := roots[key].(map[string]interface{}); ok {
+			r.readJSONNode(node, nil)
+		}
+	}
+}
+
+func (r *bookmarkReader) readJSONNode(node map[string]interface{}, parents []string) {
+	name, _ := node["name"].(string)
+	if name == "" {
+		name, _ = node["title"].(string)
+	}
+	if children, ok := node["children"].([]interface{}); ok {
+		path := append(append([]string(nil), parents...), cleanBookmarkName(name))
+		r.addFolder(joinBookmarkFolder(path))
+		for _, child := range children {
+			if child, ok := child.(map[string]interface{}); ok {
+				r.readJSONNode(child, path)
+			}
+		}
+		return
+	}
+	rawURL, _ := node["url"].(string)
+	if rawURL == "" {
+		rawURL, _ = node["uri"].(string)
+	}
+	r.addBookmark(rawURL, joinBookmarkFolder(parents))
+}
+
+func cleanBookmarkName(name string) string {
+	name = html.UnescapeString(anyTagPattern.ReplaceAllString(name, ""))
+	return strings.ReplaceAll(strings.Join(strings.Fields(name), " "), "/", "-")
+}
+
+// joinBookmarkFolder joins folder names, leaving out unnamed ones such as
+// the root of an export.
+func joinBookmarkFolder(names []string) string {
+	var parts []string
+	for _, name := range names {
+		if name != "" {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func splitBookmarkFolder(folder string) []string {
+	var parts []string
+	for _, part := range strings.Split(folder, "/") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, strings.ToLower(part))
+		}
+	}
+	return parts
+}
+
+// inBookmarkFolder reports whether folder is the wanted folder or inside
+// it. wanted may be a full path or any run of folder names within one.
+func inBookmarkFolder(folder string, wanted []string) bool {
+	parts := splitBookmarkFolder(folder)
+	for start := 0; start+len(wanted) <= len(parts); start++ {
+		match := true
+		for i, name := range wanted {
+			if parts[start+i] != name {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
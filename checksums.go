@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+// VerifyResult reports one task's outputs after re-hashing them. Recorded
+// lists files that had no checksum yet and were hashed for the first time.
+type VerifyResult struct {
+	TaskID   string   `json:"taskId"`
+	Checked  int      `json:"checked"`
+	Missing  []string `json:"missing"`
+	Modified []string `json:"modified"`
+	Recorded []string `json:"recorded"`
+}
+
+// VerifyTask re-hashes a finished task's files and compares them with the
+// checksums recorded when it finished. Mismatches set the output's Modified
+// flag and the task's ModifiedOutput.
+func (a *App) VerifyTask(id string) (VerifyResult, error) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return VerifyResult{}, errors.New("task not found")
+	}
+	if task.Status != statusSuccess {
+		a.mu.Unlock()
+		return VerifyResult{}, errors.New("task has not finished downloading")
+	}
+	outputs := make([]TaskOutput, len(task.Outputs))
+	copy(outputs, task.Outputs)
+	a.mu.Unlock()
+
+	result := VerifyResult{TaskID: id, Missing: []string{}, Modified: []string{}, Recorded: []string{}}
+	verified := make([]TaskOutput, 0, len(outputs))
+	for _, output := range outputs {
+		next := statOutput(output.Path, output.Kind)
+		next.SHA256 = output.SHA256
+		if next.Missing {
+			next.Size = output.Size
+			next.Modified = output.Modified
+			result.Missing = append(result.Missing, output.Path)
+			verified = append(verified, next)
+			continue
+		}
+		sum, err := hashFile(output.Path)
+		if err != nil {
+			return VerifyResult{}, err
+		}
+		result.Checked++
+		switch {
+		case output.SHA256 == "":
+			next.SHA256 = sum
+			result.Recorded = append(result.Recorded, output.Path)
+		case sum != output.SHA256:
+			next.Modified = true
+			result.Modified = append(result.Modified, output.Path)
+		}
+		verified = append(verified, next)
+	}
+
+	a.mu.Lock()
+	task, ok = a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return VerifyResult{}, errors.New("task not found")
+	}
+	task.setOutputs(verified)
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return result, nil
+}
+
+// VerifyAllTasks runs VerifyTask on every finished task and returns the
+// results for tasks with missing or modified files.
+func (a *App) VerifyAllTasks() ([]VerifyResult, error) {
+	a.mu.Lock()
+	var ids []string
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok && !task.deleted() && task.Status == statusSuccess && len(task.Outputs) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	a.mu.Unlock()
+
+	problems := []VerifyResult{}
+	for _, id := range ids {
+		result, err := a.VerifyTask(id)
+		if err != nil {
+			continue
+		}
+		if len(result.Missing) > 0 || len(result.Modified) > 0 {
+			problems = append(problems, result)
+		}
+	}
+	return problems, nil
+}
+
+// recordChecksumsAndCheckDuplicate hashes a finished task's files so later
+// verification has something to compare with, then looks for duplicates
+// using those hashes.
+func (a *App) recordChecksumsAndCheckDuplicate(id string) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	var outputs []TaskOutput
+	if ok {
+		outputs = make([]TaskOutput, len(task.Outputs))
+		copy(outputs, task.Outputs)
+	}
+	a.mu.Unlock()
+	recorded := false
+	for _, output := range outputs {
+		if output.Missing || output.SHA256 != "" {
+			continue
+		}
+		if sum, err := hashFile(output.Path); err == nil {
+			a.recordOutputHash(id, output.Path, sum)
+			recorded = true
+		}
+	}
+	if recorded {
+		a.saveTasks()
+	}
+	a.checkDuplicate(id)
+}
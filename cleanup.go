@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	cleanupInterval  = 6 * time.Hour
+	orphanMinimumAge = 24 * time.Hour
+)
+
+// CleanupPolicy controls the periodic cleaner. Zero values disable a rule.
+type CleanupPolicy struct {
+	FailedTaskDays     int `json:"failedTaskDays"`
+	TrashDownloadsDays int `json:"trashDownloadsDays"`
+	// PurgeOrphans trashes files in FetchForge's dated download folders that
+	// no task references. It never runs on a timer: RunCleanupNow only trashes
+	// the orphans listed by the dry run before it.
+	PurgeOrphans bool `json:"purgeOrphans"`
+}
+
+// CleanupReport lists what a cleanup run removed, or would remove when DryRun
+// is set.
+type CleanupReport struct {
+	DryRun         bool     `json:"dryRun"`
+	FailedTasks    []string `json:"failedTasks"`
+	TrashedFiles   []string `json:"trashedFiles"`
+	OrphanedFiles  []string `json:"orphanedFiles"`
+	ReclaimedBytes int64    `json:"reclaimedBytes"`
+	Errors         []string `json:"errors"`
+}
+
+func (a *App) GetCleanupPolicy() (CleanupPolicy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cleanupPolicy, nil
+}
+
+func (a *App) SetCleanupPolicy(policy CleanupPolicy) error {
+	if policy.FailedTaskDays < 0 || policy.TrashDownloadsDays < 0 {
+		return errors.New("retention days cannot be negative")
+	}
+	a.mu.Lock()
+	a.cleanupPolicy = policy
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// RunCleanupNow applies the cleanup policy immediately. With dryRun set it
+// only reports what would be removed; orphaned files are only trashed once a
+// dry run has listed them.
+func (a *App) RunCleanupNow(dryRun bool) (CleanupReport, error) {
+	a.mu.Lock()
+	policy := a.cleanupPolicy
+	a.mu.Unlock()
+	return a.runCleanup(policy, dryRun), nil
+}
+
+// cleanupLoop runs the cleanup policy, the history retention policy and the
+// task log pruning. The last two also run once at launch.
+func (a *App) cleanupLoop() {
+	_ = a.applyRetentionPolicy()
+	a.pruneTaskLogs()
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		_ = a.applyRetentionPolicy()
+		a.pruneTaskLogs()
+		a.mu.Lock()
+		policy := a.cleanupPolicy
+		a.mu.Unlock()
+		policy.PurgeOrphans = false
+		if policy.FailedTaskDays == 0 && policy.TrashDownloadsDays == 0 {
+			continue
+		}
+		a.runCleanup(policy, false)
+	}
+}
+
+func (a *App) runCleanup(policy CleanupPolicy, dryRun bool) CleanupReport {
+	report := CleanupReport{DryRun: dryRun}
+	now := time.Now()
+
+	a.mu.Lock()
+	var failedIDs []string
+	var oldDownloads []string
+	var oldDownloadIDs []string
+	referenced := make(map[string]bool)
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok {
+			continue
+		}
+		for _, path := range task.outputPaths() {
+			referenced[filepath.Clean(path)] = true
+		}
+		if policy.FailedTaskDays > 0 && task.Status == statusFailed &&
+			now.Sub(task.UpdatedAt) > time.Duration(policy.FailedTaskDays)*24*time.Hour {
+			failedIDs = append(failedIDs, id)
+		}
+		if policy.TrashDownloadsDays > 0 && task.Status == statusSuccess &&
+			now.Sub(task.CreatedAt) > time.Duration(policy.TrashDownloadsDays)*24*time.Hour {
+			found := false
+			for _, path := range task.outputPaths() {
+				if fileExists(path) {
+					oldDownloads = append(oldDownloads, path)
+					found = true
+				}
+			}
+			if found {
+				oldDownloadIDs = append(oldDownloadIDs, id)
+			}
+		}
+	}
+	a.mu.Unlock()
+
+	report.FailedTasks = failedIDs
+	for _, path := range oldDownloads {
+		if info, err := os.Stat(path); err == nil {
+			report.ReclaimedBytes += info.Size()
+		}
+		report.TrashedFiles = append(report.TrashedFiles, path)
+	}
+
+	if policy.PurgeOrphans {
+		a.mu.Lock()
+		reviewed := a.reviewedOrphans
+		a.mu.Unlock()
+		var orphans []orphanFile
+		if err := a.addHistoryOutputs(referenced); err != nil {
+			report.Errors = append(report.Errors, "orphaned files were not checked: "+err.Error())
+		} else {
+			orphans = findOrphans(referenced, now)
+		}
+		unreviewed := 0
+		for _, orphan := range orphans {
+			if !dryRun && !reviewed[orphan.path] {
+				unreviewed++
+				continue
+			}
+			report.OrphanedFiles = append(report.OrphanedFiles, orphan.path)
+			report.ReclaimedBytes += orphan.size
+		}
+		if unreviewed > 0 {
+			report.Errors = append(report.Errors, fmt.Sprintf("%d orphaned files were skipped because no dry run listed them", unreviewed))
+		}
+		a.mu.Lock()
+		a.reviewedOrphans = nil
+		if dryRun {
+			a.reviewedOrphans = make(map[string]bool, len(report.OrphanedFiles))
+			for _, path := range report.OrphanedFiles {
+				a.reviewedOrphans[path] = true
+			}
+		}
+		a.mu.Unlock()
+	}
+
+	if dryRun {
+		return report
+	}
+	if len(failedIDs) > 0 || len(oldDownloadIDs) > 0 {
+		a.snapshotTasks("Before cleanup")
+	}
+
+	for _, path := range append(append([]string{}, report.TrashedFiles...), report.OrphanedFiles...) {
+		if err := a.moveToTrash(path); err != nil {
+			report.Errors = append(report.Errors, path+": "+err.Error())
+		}
+	}
+
+	a.mu.Lock()
+	for _, id := range oldDownloadIDs {
+		if task, ok := a.tasks[id]; ok {
+			outputs, _ := refreshOutputs(task.Outputs)
+			task.setOutputs(outputs)
+			task.UpdatedAt = now
+		}
+	}
+	a.removeTasksLocked(failedIDs)
+	a.mu.Unlock()
+
+	if len(failedIDs) > 0 || len(oldDownloadIDs) > 0 {
+		a.saveTasks()
+	}
+	return report
+}
+
+type orphanFile struct {
+	path string
+	size int64
+}
+
+// findOrphans lists files older than orphanMinimumAge that are not in
+// referenced. Only the dated folders FetchForge creates in the download root
+// are searched, never the rest of a folder such as ~/Downloads.
+func findOrphans(referenced map[string]bool, now time.Time) []orphanFile {
+	root, err := downloadRoot()
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+	var orphans []orphanFile
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", entry.Name()); err != nil {
+			continue
+		}
+		_ = filepath.WalkDir(filepath.Join(root, entry.Name()), func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || isPartialFile(d.Name()) {
+				return nil
+			}
+			if referenced[filepath.Clean(path)] {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil || now.Sub(info.ModTime()) < orphanMinimumAge {
+				return nil
+			}
+			orphans = append(orphans, orphanFile{path: path, size: info.Size()})
+			return nil
+		})
+	}
+	return orphans
+}
+
+// removeTasksLocked drops tasks from the store. Callers must hold a.mu.
+func (a *App) removeTasksLocked(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	remove := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		remove[id] = struct{}{}
+		delete(a.tasks, id)
+		delete(a.mqttLastStatus, id)
+	}
+	nextOrder := make([]string, 0, len(a.order))
+	for _, existing := range a.order {
+		if _, ok := remove[existing]; !ok {
+			nextOrder = append(nextOrder, existing)
+		}
+	}
+	a.order = nextOrder
+}
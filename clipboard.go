@@ -0,0 +1,156 @@
+package main
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const clipboardPollInterval = 1500 * time.Millisecond
+
+// mediaHosts are sites whose links are treated as downloadable when they
+// show up on the clipboard. Subdomains match too.
+var mediaHosts = []string{
+	"youtube.com", "youtu.be", "vimeo.com", "dailymotion.com", "twitch.tv",
+	"tiktok.com", "instagram.com", "twitter.com", "x.com", "facebook.com",
+	"reddit.com", "soundcloud.com", "bandcamp.com", "bilibili.com",
+	"b23.tv", "nicovideo.jp", "streamable.com", "rumble.com",
+}
+
+var mediaExtensions = map[string]bool{
+	".mp4": true, ".mkv": true, ".webm": true, ".mov": true, ".m3u8": true,
+	".mp3": true, ".m4a": true, ".flac": true, ".ogg": true, ".wav": true,
+}
+
+// ClipboardWatchSettings controls the clipboard monitor. With AutoQueue off,
+// detected links are sent to the UI as "clipboard:url-detected" events for
+// confirmation instead of being queued.
+type ClipboardWatchSettings struct {
+	Enabled   bool `json:"enabled"`
+	AutoQueue bool `json:"autoQueue"`
+}
+
+// GetClipboardWatch returns the clipboard monitor settings.
+func (a *App) GetClipboardWatch() (ClipboardWatchSettings, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.clipboardWatch, nil
+}
+
+// SetClipboardWatch turns the clipboard monitor on or off. Links already on
+// the clipboard when it is turned on are not picked up.
+func (a *App) SetClipboardWatch(settings ClipboardWatchSettings) error {
+	current := ""
+	if settings.Enabled && a.ctx != nil {
+		current, _ = wailsruntime.ClipboardGetText(a.ctx)
+	}
+	a.mu.Lock()
+	if settings.Enabled && !a.clipboardWatch.Enabled {
+		a.clipboardLast = current
+	}
+	a.clipboardWatch = settings
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// IgnoreClipboardURL stops a link from being offered again until the app
+// restarts.
+func (a *App) IgnoreClipboardURL(url string) error {
+	a.mu.Lock()
+	a.clipboardIgnored[strings.TrimSpace(url)] = true
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *App) clipboardLoop() {
+	// Whatever is on the clipboard at launch was copied before the app ran.
+	if a.ctx != nil {
+		if text, err := wailsruntime.ClipboardGetText(a.ctx); err == nil {
+			a.mu.Lock()
+			a.clipboardLast = text
+			a.mu.Unlock()
+		}
+	}
+	ticker := time.NewTicker(clipboardPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.mu.Lock()
+		settings := a.clipboardWatch
+		a.mu.Unlock()
+		if !settings.Enabled || a.ctx == nil {
+			continue
+		}
+		text, err := wailsruntime.ClipboardGetText(a.ctx)
+		if err != nil {
+			continue
+		}
+		a.mu.Lock()
+		if text == a.clipboardLast {
+			a.mu.Unlock()
+			continue
+		}
+		a.clipboardLast = text
+		var urls []string
+		for _, url := range extractURLs(text) {
+			if !a.clipboardIgnored[url] && !a.hasTaskForURLLocked(url) {
+				urls = append(urls, url)
+			}
+		}
+		a.mu.Unlock()
+
+		var media []string
+		for _, url := range urls {
+			if a.isLikelyMediaURL(url) {
+				media = append(media, url)
+			}
+		}
+		if len(media) == 0 {
+			continue
+		}
+		if settings.AutoQueue {
+			_, _ = a.CreateTasksFromText(strings.Join(media, "\n"), "")
+			continue
+		}
+		wailsruntime.EventsEmit(a.ctx, "clipboard:url-detected", media)
+	}
+}
+
+// hasTaskForURLLocked reports whether a task already exists for url. The
+// caller must hold a.mu.
+func (a *App) hasTaskForURLLocked(url string) bool {
+	for _, task := range a.tasks {
+		if task.URL == url && !task.deleted() {
+			return true
+		}
+	}
+	return false
+}
+
+// isLikelyMediaURL keeps the clipboard monitor from reacting to every link:
+// known video sites, hosts with download settings, gallery-dl rules and
+// direct media files count.
+func (a *App) isLikelyMediaURL(url string) bool {
+	if a.engineForURL(url) == engineGalleryDl {
+		return true
+	}
+	host := normalizeOverrideHost(sourceHostFromURL(url))
+	if host == "" {
+		return false
+	}
+	for _, media := range mediaHosts {
+		if host == media || strings.HasSuffix(host, "."+media) {
+			return true
+		}
+	}
+	if _, ok := a.hostOverride(host); ok {
+		return true
+	}
+	rawPath := url
+	if index := strings.IndexAny(rawPath, "?#"); index >= 0 {
+		rawPath = rawPath[:index]
+	}
+	return mediaExtensions[strings.ToLower(path.Ext(rawPath))]
+}
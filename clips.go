@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// clipTimePattern accepts seconds, mm:ss or hh:mm:ss, with optional
+// fractional seconds.
+var clipTimePattern = regexp.MustCompile(`^(?:(\d+):)?(?:(\d+):)?(\d+(?:\.\d+)?)$`)
+
+// TaskOptions describes a single task created through CreateTaskWithOptions.
+// StartTime and EndTime cut a clip out of the video; either may be empty to
+// start at the beginning or run to the end. Engine picks the download backend;
+// empty chooses one from the URL. SHA256 is the checksum a direct download
+// must match. ExtraArgs are added to this download's yt-dlp command. DryRun
+// resolves the metadata and the download command without downloading.
+type TaskOptions struct {
+	URL       string   `json:"url"`
+	OutputDir string   `json:"outputDir"`
+	ProfileID string   `json:"profileId"`
+	StartTime string   `json:"startTime"`
+	EndTime   string   `json:"endTime"`
+	Engine    string   `json:"engine"`
+	SHA256    string   `json:"sha256"`
+	ExtraArgs []string `json:"extraArgs"`
+	DryRun    bool     `json:"dryRun"`
+}
+
+// CreateTaskWithOptions queues one download with per-task options. With a
+// time range only that section is downloaded.
+func (a *App) CreateTaskWithOptions(options TaskOptions) (Task, error) {
+	urls := extractURLs(options.URL)
+	if len(urls) != 1 {
+		return Task{}, errors.New("enter exactly one URL")
+	}
+	outputDir, err := validateOutputDir(options.OutputDir)
+	if err != nil {
+		return Task{}, err
+	}
+	start, end, err := normalizeClipRange(options.StartTime, options.EndTime)
+	if err != nil {
+		return Task{}, err
+	}
+	checksum, err := normalizeSHA256(options.SHA256)
+	if err != nil {
+		return Task{}, err
+	}
+	extraArgs, err := normalizeExtraArgs(options.ExtraArgs)
+	if err != nil {
+		return Task{}, err
+	}
+	a.mu.Lock()
+	if options.ProfileID != "" {
+		if _, ok := a.findProfileLocked(options.ProfileID); !ok {
+			a.mu.Unlock()
+			return Task{}, errors.New("profile not found")
+		}
+	}
+	a.mu.Unlock()
+	engine := a.engineForURL(urls[0])
+	if options.Engine != "" {
+		if engine, err = a.checkEngine(strings.TrimSpace(options.Engine)); err != nil {
+			return Task{}, err
+		}
+	}
+	if (start != "" || end != "") && engine != engineYtDlp {
+		return Task{}, errors.New("time ranges only work for video downloads")
+	}
+	created := a.createTasks([]PreviewEntry{{URL: urls[0]}}, newTaskOptions{
+		outputDir:      outputDir,
+		profileID:      options.ProfileID,
+		startTime:      start,
+		endTime:        end,
+		engine:         engine,
+		expectedSHA256: checksum,
+		extraArgs:      extraArgs,
+		dryRun:         options.DryRun,
+	})
+	return created[0], nil
+}
+
+// normalizeClipRange validates a clip's start and end times and checks that
+// the range is not empty.
+func normalizeClipRange(start, end string) (string, string, error) {
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	startSeconds, err := parseClipTime(start)
+	if err != nil {
+		return "", "", errors.New("invalid start time: use seconds, mm:ss or hh:mm:ss")
+	}
+	endSeconds, err := parseClipTime(end)
+	if err != nil {
+		return "", "", errors.New("invalid end time: use seconds, mm:ss or hh:mm:ss")
+	}
+	if end != "" && endSeconds <= startSeconds {
+		return "", "", errors.New("end time must be after the start time")
+	}
+	return start, end, nil
+}
+
+// parseClipTime returns a clip time in seconds. Empty is zero.
+func parseClipTime(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	match := clipTimePattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, errors.New("invalid time")
+	}
+	var seconds float64
+	for _, part := range match[1:3] {
+		if part != "" {
+			n, _ := strconv.Atoi(part)
+			seconds = seconds*60 + float64(n)
+		}
+	}
+	last, _ := strconv.ParseFloat(match[3], 64)
+	return seconds*60 + last, nil
+}
+
+// clipArgs asks yt-dlp for just the task's time range.
+func clipArgs(start, end string) []string {
+	if start == "" && end == "" {
+		return nil
+	}
+	if start == "" {
+		start = "0"
+	}
+	if end == "" {
+		end = "inf"
+	}
+	return []string{"--download-sections", "*" + start + "-" + end}
+}
+
+// clipFilenameTemplate marks clip files with their range so they do not
+// overwrite a full download of the same video.
+func clipFilenameTemplate(template, start, end string) string {
+	if start == "" && end == "" {
+		return template
+	}
+	suffix := " (clip %(section_start)d-%(section_end)d)"
+	if base, ok := strings.CutSuffix(template, ".%(ext)s"); ok {
+		return base + suffix + ".%(ext)s"
+	}
+	return template + suffix
+}
@@ -0,0 +1,211 @@
+// Command fetchforge-cli controls a running FetchForge app from the terminal.
+//
+//	fetchforge-cli add <url>... [--dir <path>]
+//	fetchforge-cli list [--status <status>]
+//	fetchforge-cli resume <id>
+//
+// It talks to the app's loopback control API using the address and token the
+// app writes to ~/.fetchforge/control.json on launch.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type controlInfo struct {
+	Addr  string `json:"addr"`
+	Token string `json:"token"`
+}
+
+type task struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Stage    string `json:"stage"`
+	Progress string `json:"progress"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fetchforge-cli:", err)
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "add":
+		err = runAdd(client, os.Args[2:])
+	case "list":
+		err = runList(client, os.Args[2:])
+	case "resume":
+		err = runResume(client, os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fetchforge-cli:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  fetchforge-cli add <url>... [--dir <path>]
+  fetchforge-cli list [--status <status>]
+  fetchforge-cli resume <id>`)
+}
+
+func runAdd(c *client, args []string) error {
+	flags := flag.NewFlagSet("add", flag.ContinueOnError)
+	dir := flags.String("dir", "", "output directory for these downloads")
+	if err := flags.Parse(reorderFlags(args)); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("add needs at least one URL")
+	}
+	outputDir := *dir
+	if outputDir != "" {
+		abs, err := filepath.Abs(outputDir)
+		if err != nil {
+			return err
+		}
+		outputDir = abs
+	}
+	body := map[string]string{"text": strings.Join(flags.Args(), "\n"), "outputDir": outputDir}
+	var created []task
+	if err := c.do(http.MethodPost, "/v1/tasks", body, &created); err != nil {
+		return err
+	}
+	if len(created) == 0 {
+		return errors.New("no URLs found")
+	}
+	for _, t := range created {
+		fmt.Printf("%s\t%s\n", t.ID, t.URL)
+	}
+	return nil
+}
+
+func runList(c *client, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	status := flags.String("status", "", "only show tasks with this status")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	path := "/v1/tasks"
+	if *status != "" {
+		path += "?status=" + *status
+	}
+	var tasks []task
+	if err := c.do(http.MethodGet, path, nil, &tasks); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tPROGRESS\tTITLE")
+	for _, t := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.ID, t.Status, t.Progress, t.Title)
+	}
+	return w.Flush()
+}
+
+func runResume(c *client, args []string) error {
+	if len(args) != 1 {
+		return errors.New("resume needs exactly one task id")
+	}
+	return c.do(http.MethodPost, "/v1/tasks/"+args[0]+"/resume", nil, nil)
+}
+
+// reorderFlags moves flags in front of positional arguments so that
+// "add <url> --dir x" works as well as "add --dir x <url>".
+func reorderFlags(args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		if strings.HasPrefix(args[i], "-") {
+			flags = append(flags, args[i])
+			if !strings.Contains(args[i], "=") && i+1 < len(args) {
+				flags = append(flags, args[i+1])
+				i++
+			}
+			continue
+		}
+		positional = append(positional, args[i])
+	}
+	return append(flags, positional...)
+}
+
+type client struct {
+	info controlInfo
+	http *http.Client
+}
+
+func newClient() (*client, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".fetchforge", "control.json"))
+	if err != nil {
+		return nil, errors.New("FetchForge does not appear to be running")
+	}
+	var info controlInfo
+	if err := json.Unmarshal(data, &info); err != nil || info.Addr == "" {
+		return nil, errors.New("invalid control file")
+	}
+	return &client{info: info, http: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+func (c *client) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, "http://"+c.info.Addr+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.info.Token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.New("could not reach FetchForge; is it running?")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error == "" {
+			apiErr.Error = resp.Status
+		}
+		return errors.New(apiErr.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
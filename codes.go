@@ -0,0 +1,342 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Tasks carry machine-readable codes next to their English display strings:
+//
+//	statusCode             queued, running, success, failed, canceled,
+//	                       paused, interrupted, awaiting_confirmation
+//	stageCode/stageParams  see stageLabels
+//	errorCode/errorParams  see errorLabels; errorParams.detail holds the raw
+//	                       tool output when there is one
+//	errorHint/errorAction  see errorHints; the action names the fix the UI
+//	                       can offer, such as add_cookies or retry_later
+//
+// Status, Stage and ErrorMessage are still filled in for older clients.
+// ListTaskCodes returns the full code set with English templates so the UI
+// can localize them.
+
+const (
+	stageParseURL         = "parse_url"
+	stageResolveMetadata  = "resolve_metadata"
+	stageDownload         = "download"
+	stageFinalize         = "finalize"
+	stageResume           = "resume"
+	stageForceResume      = "force_resume"
+	stageBandwidthLimit   = "apply_bandwidth_limit"
+	stageRetry            = "retry"
+	stageRetryWait        = "retry_wait"
+	stageCooldown         = "cooldown"
+	stageRestart          = "restart"
+	stageScheduled        = "scheduled"
+	stageTranscoding      = "transcoding"
+	stagePipelineStep     = "pipeline_step"
+	stagePipelineComplete = "pipeline_complete"
+	stageDryRun           = "dry_run"
+	stageConfirmSize      = "confirm_size"
+)
+
+const (
+	errorOutputDir       = "output_dir_unavailable"
+	errorToolMissing     = "tool_missing"
+	errorLoginRequired   = "login_required"
+	errorForbidden       = "forbidden"
+	errorGeoRestricted   = "geo_restricted"
+	errorAgeRestricted   = "age_restricted"
+	errorPrivate         = "private"
+	errorDRM             = "drm_protected"
+	errorUnsupportedURL  = "unsupported_url"
+	errorUnavailable     = "unavailable"
+	errorRateLimited     = "rate_limited"
+	errorNetwork         = "network"
+	errorInvalidProxy    = "invalid_proxy"
+	errorDiskFull        = "disk_full"
+	errorNoFiles         = "no_files"
+	errorDuplicate       = "duplicate"
+	errorInArchive       = "in_archive"
+	errorPipelineFailed  = "pipeline_failed"
+	errorTranscodeFailed = "transcode_failed"
+	errorLibraryMove     = "library_move_failed"
+	errorHTTPStatus      = "http_status"
+	errorChecksum        = "checksum_mismatch"
+	errorTrashFailed     = "trash_failed"
+	errorCommandFailed   = "command_failed"
+)
+
+// stageLabels maps stage codes to English templates; {name} is replaced with
+// the matching stage param.
+var stageLabels = map[string]string{
+	stageParseURL:         "Parse URL",
+	stageResolveMetadata:  "Resolve metadata",
+	stageDownload:         "Download",
+	stageFinalize:         "Finalize",
+	stageResume:           "Resume",
+	stageForceResume:      "Force Resume",
+	stageBandwidthLimit:   "Apply bandwidth limit",
+	stageRetry:            "Retry: {fallback}",
+	stageRetryWait:        "Retry {attempt}/{max}",
+	stageCooldown:         "{host} is rate limiting, waiting until {time}",
+	stageRestart:          "Restart",
+	stageScheduled:        "Scheduled for {time}",
+	stageTranscoding:      "Transcoding: {preset}",
+	stagePipelineStep:     "{pipeline} {step}/{total}: {kind}",
+	stagePipelineComplete: "{pipeline} complete",
+	stageDryRun:           "Dry run: nothing downloaded",
+	stageConfirmSize:      "Waiting for confirmation: {size}",
+}
+
+// errorLabels maps error codes to short English templates. ErrorMessage keeps
+// the detailed text.
+var errorLabels = map[string]string{
+	errorOutputDir:       "The output folder could not be created",
+	errorToolMissing:     "{tool} is not installed",
+	errorLoginRequired:   "This video requires signing in",
+	errorForbidden:       "The site refused the download (HTTP 403)",
+	errorGeoRestricted:   "This video is not available in your region",
+	errorAgeRestricted:   "This video is age-restricted",
+	errorPrivate:         "This video is private",
+	errorDRM:             "This video is DRM protected",
+	errorUnsupportedURL:  "This URL is not supported",
+	errorUnavailable:     "This video is unavailable",
+	errorRateLimited:     "The site is rate limiting requests",
+	errorNetwork:         "Network error",
+	errorInvalidProxy:    "The proxy URL is not valid",
+	errorDiskFull:        "Not enough disk space",
+	errorNoFiles:         "Nothing was downloaded",
+	errorDuplicate:       "Already downloaded by another task",
+	errorInArchive:       "Already in the download archive",
+	errorPipelineFailed:  "{pipeline} failed at step {step} ({kind})",
+	errorTranscodeFailed: "Transcoding to {preset} failed",
+	errorLibraryMove:     "Could not move the download to the library",
+	errorHTTPStatus:      "The server responded with {status}",
+	errorChecksum:        "The download does not match the expected checksum",
+	errorTrashFailed:     "Could not move the files to the trash: {detail}",
+	errorCommandFailed:   "{tool} failed",
+}
+
+// Error actions name the fix the UI can offer for a failure.
+const (
+	actionAddCookies    = "add_cookies"
+	actionUseProxy      = "use_proxy"
+	actionRetryLater    = "retry_later"
+	actionFreeDiskSpace = "free_disk_space"
+	actionCheckURL      = "check_url"
+	actionInstallTool   = "install_tool"
+	actionCheckNetwork  = "check_network"
+)
+
+// errorHint is the advice shown with an error code.
+type errorHint struct {
+	text   string
+	action string
+}
+
+var errorHints = map[string]errorHint{
+	errorOutputDir:      {"Choose a download folder you can write to.", ""},
+	errorToolMissing:    {"Install {tool} or set its path in the environment.", actionInstallTool},
+	errorLoginRequired:  {"Add cookies from a browser where you are signed in.", actionAddCookies},
+	errorForbidden:      {"Add cookies or update yt-dlp, then retry.", actionAddCookies},
+	errorGeoRestricted:  {"Retry through a proxy in a region where the video is available.", actionUseProxy},
+	errorAgeRestricted:  {"Add cookies from an account that has confirmed its age.", actionAddCookies},
+	errorPrivate:        {"Add cookies from an account that can see the video.", actionAddCookies},
+	errorDRM:            {"DRM-protected videos cannot be downloaded.", ""},
+	errorUnsupportedURL: {"Check the URL; this site is not supported by the download tools.", actionCheckURL},
+	errorUnavailable:    {"Check that the video still exists.", actionCheckURL},
+	errorRateLimited:    {"Wait a while before retrying.", actionRetryLater},
+	errorNetwork:        {"Check your connection and retry.", actionCheckNetwork},
+	errorInvalidProxy:   {"Fix the proxy URL in settings or on the task.", ""},
+	errorDiskFull:       {"Free up disk space and retry.", actionFreeDiskSpace},
+	errorInArchive:      {"Remove it from the download archive or download it anyway.", ""},
+	errorHTTPStatus:     {"Check that the link still works in a browser.", actionCheckURL},
+	errorChecksum:       {"Check the checksum and the link, then retry.", actionCheckURL},
+}
+
+var statusCodes = []string{"queued", "running", "success", "failed", "canceled", "paused", "interrupted", "awaiting_confirmation"}
+
+// TaskCode documents one code and the params it uses. Error codes also carry
+// their hint and action.
+type TaskCode struct {
+	Code   string   `json:"code"`
+	Label  string   `json:"label"`
+	Params []string `json:"params"`
+	Hint   string   `json:"hint,omitempty"`
+	Action string   `json:"action,omitempty"`
+}
+
+// TaskCodes is the full set of codes a Task can carry.
+type TaskCodes struct {
+	Statuses []TaskCode `json:"statuses"`
+	Stages   []TaskCode `json:"stages"`
+	Errors   []TaskCode `json:"errors"`
+}
+
+// taskError is a task failure with its code, params and display message.
+type taskError struct {
+	Code    string
+	Params  map[string]string
+	Message string
+}
+
+var templateParamPattern = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// ListTaskCodes returns every status, stage and error code with its English
+// template.
+func (a *App) ListTaskCodes() (TaskCodes, error) {
+	var codes TaskCodes
+	for _, code := range statusCodes {
+		label := strings.ReplaceAll(code, "_", " ")
+		codes.Statuses = append(codes.Statuses, TaskCode{Code: code, Label: strings.ToUpper(label[:1]) + label[1:], Params: []string{}})
+	}
+	codes.Stages = describeCodes(stageLabels)
+	codes.Errors = describeCodes(errorLabels)
+	for i := range codes.Errors {
+		hint := errorHints[codes.Errors[i].Code]
+		codes.Errors[i].Hint = hint.text
+		codes.Errors[i].Action = hint.action
+	}
+	return codes, nil
+}
+
+func describeCodes(labels map[string]string) []TaskCode {
+	out := make([]TaskCode, 0, len(labels))
+	for code, label := range labels {
+		params := []string{}
+		for _, match := range templateParamPattern.FindAllStringSubmatch(label, -1) {
+			params = append(params, match[1])
+		}
+		out = append(out, TaskCode{Code: code, Label: label, Params: params})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+func renderCodeLabel(template string, params map[string]string) string {
+	return templateParamPattern.ReplaceAllStringFunc(template, func(match string) string {
+		return params[match[1:len(match)-1]]
+	})
+}
+
+func statusCodeFor(status string) string {
+	var b strings.Builder
+	for i, r := range status {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// setStatus sets the display status and its code together.
+func (t *Task) setStatus(status string) {
+	t.Status = status
+	t.StatusCode = statusCodeFor(status)
+}
+
+// setStageCode moves the task into a coded stage and records the English
+// label on Stage and the timeline.
+func (t *Task) setStageCode(code string, params map[string]string, at time.Time) {
+	t.StageCode = code
+	t.StageParams = params
+	t.setStage(renderCodeLabel(stageLabels[code], params), at)
+}
+
+func (t *Task) setError(failure taskError) {
+	t.ErrorCode = failure.Code
+	t.ErrorParams = failure.Params
+	t.ErrorMessage = failure.Message
+	if t.ErrorMessage == "" {
+		t.ErrorMessage = renderCodeLabel(errorLabels[failure.Code], failure.Params)
+	}
+	t.setErrorHint()
+}
+
+// setErrorHint fills the hint and action for the current error code.
+func (t *Task) setErrorHint() {
+	hint := errorHints[t.ErrorCode]
+	t.ErrorHint = renderCodeLabel(hint.text, t.ErrorParams)
+	t.ErrorAction = hint.action
+}
+
+func (t *Task) clearError() {
+	t.ErrorCode = ""
+	t.ErrorParams = nil
+	t.ErrorMessage = ""
+	t.ErrorHint = ""
+	t.ErrorAction = ""
+}
+
+// fillLegacyCodes derives codes for task records saved before they existed.
+func (t *Task) fillLegacyCodes() {
+	if t.StatusCode == "" && t.Status != "" {
+		t.StatusCode = statusCodeFor(t.Status)
+	}
+	if t.StageCode == "" && t.Stage != "" {
+		for code, label := range stageLabels {
+			if label == t.Stage {
+				t.StageCode = code
+				break
+			}
+		}
+	}
+	if t.ErrorCode == "" && t.ErrorMessage != "" {
+		t.ErrorCode, t.ErrorParams = classifyErrorText(t.ErrorMessage)
+		t.ErrorParams["detail"] = t.ErrorMessage
+	}
+	if t.ErrorCode != "" && t.ErrorHint == "" {
+		t.setErrorHint()
+	}
+}
+
+var errorSignatures = []struct {
+	code    string
+	pattern *regexp.Regexp
+}{
+	{errorUnsupportedURL, regexp.MustCompile(`(?i)unsupported url|is not a valid url|no suitable infoextractor`)},
+	{errorDRM, regexp.MustCompile(`(?i)\bDRM\b`)},
+	{errorAgeRestricted, regexp.MustCompile(`(?i)confirm your age|age.?restricted|age.?gated|inappropriate for some users`)},
+	{errorPrivate, regexp.MustCompile(`(?i)private video|video is private`)},
+	{errorLoginRequired, regexp.MustCompile(`(?i)sign in to confirm|login required|requires authentication|use --cookies|members-only`)},
+	{errorGeoRestricted, regexp.MustCompile(`(?i)not available in your country|geo.?restrict|blocked it in your country`)},
+	{errorRateLimited, regexp.MustCompile(`(?i)HTTP Error 429|too many requests|rate.?limit`)},
+	{errorForbidden, regexp.MustCompile(`(?i)HTTP Error 403|forbidden`)},
+	{errorUnavailable, regexp.MustCompile(`(?i)video unavailable|has been removed|does not exist|HTTP Error 404|no video formats found`)},
+	{errorDiskFull, regexp.MustCompile(`(?i)no space left on device|disk full|not enough space`)},
+	{errorNetwork, regexp.MustCompile(`(?i)timed out|connection (?:reset|refused|aborted)|network is unreachable|name or service not known|getaddrinfo failed|temporary failure in name resolution|unable to download webpage`)},
+}
+
+// classifyErrorText picks the most specific error code for tool output.
+func classifyErrorText(text string) (string, map[string]string) {
+	for _, signature := range errorSignatures {
+		if signature.pattern.MatchString(text) {
+			return signature.code, map[string]string{}
+		}
+	}
+	return errorCommandFailed, map[string]string{}
+}
+
+// commandFailure builds a coded error for a failed external command.
+func commandFailure(err error, cmd *exec.Cmd, stdoutText, stderrText string) taskError {
+	message := formatCommandError(err, cmd, stdoutText, stderrText)
+	tool := commandToolName(cmd)
+	if errors.Is(err, exec.ErrNotFound) {
+		return taskError{Code: errorToolMissing, Params: map[string]string{"tool": tool, "detail": message}, Message: message}
+	}
+	code, params := classifyErrorText(stderrText + "\n" + stdoutText)
+	params["tool"] = tool
+	params["detail"] = message
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		params["exitCode"] = strconv.Itoa(exitErr.ExitCode())
+	}
+	return taskError{Code: code, Params: params, Message: message}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Collision policies decide what happens when a download's file name is
+// already taken. "rename" (the default) downloads to "Title (2).ext",
+// "overwrite" replaces the existing file, and "skip" fails the task as a
+// duplicate of whichever task owns the file.
+const (
+	collisionRename    = "rename"
+	collisionOverwrite = "overwrite"
+	collisionSkip      = "skip"
+)
+
+// GetCollisionPolicy returns the file name collision policy.
+func (a *App) GetCollisionPolicy() (string, error) {
+	return a.currentCollisionPolicy(), nil
+}
+
+// SetCollisionPolicy sets what happens when a download's target file already
+// exists: "rename", "overwrite" or "skip".
+func (a *App) SetCollisionPolicy(policy string) error {
+	policy = strings.ToLower(strings.TrimSpace(policy))
+	if !validCollisionPolicy(policy) {
+		return errors.New("collision policy must be rename, overwrite or skip")
+	}
+	a.mu.Lock()
+	a.collisionPolicy = policy
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+func (a *App) currentCollisionPolicy() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.collisionPolicy == "" {
+		return collisionRename
+	}
+	return a.collisionPolicy
+}
+
+func validCollisionPolicy(policy string) bool {
+	return policy == collisionRename || policy == collisionOverwrite || policy == collisionSkip
+}
+
+func collisionArgs(policy string) []string {
+	if policy == collisionOverwrite {
+		return []string{"--force-overwrites"}
+	}
+	return nil
+}
+
+// outputCollision reports whether path, a file yt-dlp found already on disk,
+// belongs to someone other than task id, and which task owns it if any.
+func (a *App) outputCollision(id, path string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	owner := a.outputOwnerLocked(filepath.Clean(path))
+	return owner, owner != id
+}
+
+// numberedOutputTemplate returns template with the first free " (n)" suffix
+// for existing, the file the unnumbered template produced.
+func numberedOutputTemplate(template, existing, mode string) string {
+	ext := filepath.Ext(existing)
+	base := strings.TrimSuffix(existing, ext)
+	n := 2
+	for {
+		if _, err := os.Stat(base + numberSuffix(n, mode) + ext); err != nil {
+			break
+		}
+		n++
+	}
+	suffix := numberSuffix(n, mode)
+	if stem, ok := strings.CutSuffix(template, ".%(ext)s"); ok {
+		return stem + suffix + ".%(ext)s"
+	}
+	return template + suffix
+}
+
+func numberSuffix(n int, mode string) string {
+	return sanitizeTemplateLiterals(" ("+strconv.Itoa(n)+")", mode)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// SizeConfirmation is sent with the "task:confirm" event when a download is
+// larger than the confirmation threshold.
+type SizeConfirmation struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Filesize  int64  `json:"filesize"`
+	Threshold int64  `json:"threshold"`
+}
+
+// GetSizeConfirmThreshold returns the size in bytes above which downloads
+// wait for confirmation. 0 means never.
+func (a *App) GetSizeConfirmThreshold() (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.sizeConfirmThreshold, nil
+}
+
+// SetSizeConfirmThreshold sets the size in bytes above which a download
+// stops in AwaitingConfirmation once its metadata is known, until
+// ConfirmTask or ConfirmAll lets it continue. 0 turns the check off.
+func (a *App) SetSizeConfirmThreshold(bytes int64) error {
+	if bytes < 0 {
+		return errors.New("threshold must not be negative")
+	}
+	a.mu.Lock()
+	a.sizeConfirmThreshold = bytes
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// ConfirmTask lets a download that is waiting for size confirmation
+// continue. To decline, cancel or delete the task instead.
+func (a *App) ConfirmTask(id string) error {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return errors.New("task is deleted")
+	}
+	if task.Status != statusAwaitingConfirmation {
+		a.mu.Unlock()
+		return errors.New("task is not awaiting confirmation")
+	}
+	task.SizeConfirmed = true
+	task.setStatus(statusQueued)
+	task.UpdatedAt = time.Now()
+	task.closeStage(task.UpdatedAt)
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	a.enqueueTasks([]string{id})
+	return nil
+}
+
+// ConfirmAll continues every download that is waiting for size confirmation.
+func (a *App) ConfirmAll() error {
+	a.mu.Lock()
+	var ids []string
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok && task.Status == statusAwaitingConfirmation {
+			ids = append(ids, id)
+		}
+	}
+	a.mu.Unlock()
+	for _, id := range ids {
+		_ = a.ConfirmTask(id)
+	}
+	return nil
+}
+
+// holdForConfirmation stops a task whose resolved size is above the
+// threshold and asks the UI to confirm it. It reports whether the task was
+// held, in which case the runner must return.
+func (a *App) holdForConfirmation(id string) bool {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	threshold := a.sizeConfirmThreshold
+	if !ok || threshold <= 0 || task.SizeConfirmed || task.Filesize <= threshold {
+		a.mu.Unlock()
+		return false
+	}
+	if task.Status != statusRunning {
+		a.mu.Unlock()
+		return true
+	}
+	task.setStatus(statusAwaitingConfirmation)
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stageConfirmSize, map[string]string{"size": formatBytes(task.Filesize)}, task.UpdatedAt)
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	if a.ctx != nil {
+		wailsruntime.EventsEmit(a.ctx, "task:confirm", SizeConfirmation{
+			ID:        id,
+			Title:     updated.Title,
+			Filesize:  updated.Filesize,
+			Threshold: threshold,
+		})
+	}
+	return true
+}
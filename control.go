@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// controlInfo is written to ~/.fetchforge/control.json so local tools such as
+// fetchforge-cli can find the running app and authenticate to it.
+type controlInfo struct {
+	Addr  string `json:"addr"`
+	Token string `json:"token"`
+	PID   int    `json:"pid"`
+}
+
+type controlCreateRequest struct {
+	Text      string `json:"text"`
+	OutputDir string `json:"outputDir"`
+}
+
+type controlError struct {
+	Error string `json:"error"`
+}
+
+func controlFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "control.json"), nil
+}
+
+// startControlServer serves the local control API on a random loopback port.
+// A fresh token is generated on every launch.
+func (a *App) startControlServer() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return
+	}
+	token := newID() + newID()
+	path, err := controlFilePath()
+	if err != nil {
+		listener.Close()
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		listener.Close()
+		return
+	}
+	data, err := json.MarshalIndent(controlInfo{Addr: listener.Addr().String(), Token: token, PID: os.Getpid()}, "", "  ")
+	if err != nil {
+		listener.Close()
+		return
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		listener.Close()
+		return
+	}
+
+	server := &http.Server{
+		Handler:           requireToken(token, a.controlRoutes(false)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	a.mu.Lock()
+	a.controlServer = server
+	a.mu.Unlock()
+	go func() {
+		_ = server.Serve(listener)
+	}()
+}
+
+func (a *App) stopControlServer() {
+	a.mu.Lock()
+	server := a.controlServer
+	a.controlServer = nil
+	a.mu.Unlock()
+	if server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = server.Shutdown(ctx)
+	if path, err := controlFilePath(); err == nil {
+		_ = os.Remove(path)
+	}
+}
+
+// requireToken checks the bearer token. Browsers cannot set headers on a
+// WebSocket, so a token query parameter is accepted too.
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if given == "" {
+			given = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			writeControlJSON(w, http.StatusUnauthorized, controlError{Error: "unauthorized"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// controlRoutes serves the control API. With remote set it is reachable from
+// the network, so an outputDir in POST /v1/tasks must be inside the download
+// folder.
+func (a *App) controlRoutes(remote bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/tasks", func(w http.ResponseWriter, r *http.Request) {
+		status := strings.TrimSpace(r.URL.Query().Get("status"))
+		if tasks, ok := a.tasksWithStatus(status); ok {
+			writeControlJSON(w, http.StatusOK, tasks)
+			return
+		}
+		tasks, _ := a.ListTasks()
+		if status != "" {
+			filtered := make([]Task, 0, len(tasks))
+			for _, task := range tasks {
+				if strings.EqualFold(task.Status, status) {
+					filtered = append(filtered, task)
+				}
+			}
+			tasks = filtered
+		}
+		writeControlJSON(w, http.StatusOK, tasks)
+	})
+	mux.HandleFunc("POST /v1/tasks", func(w http.ResponseWriter, r *http.Request) {
+		var req controlCreateRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			writeControlJSON(w, http.StatusBadRequest, controlError{Error: "invalid JSON"})
+			return
+		}
+		if remote {
+			dir, err := remoteOutputDir(req.OutputDir)
+			if err != nil {
+				writeControlJSON(w, http.StatusBadRequest, controlError{Error: err.Error()})
+				return
+			}
+			req.OutputDir = dir
+		}
+		created, err := a.CreateTasksFromText(req.Text, req.OutputDir)
+		if err != nil {
+			writeControlJSON(w, http.StatusBadRequest, controlError{Error: err.Error()})
+			return
+		}
+		writeControlJSON(w, http.StatusCreated, created)
+	})
+	mux.HandleFunc("GET /v1/queue", func(w http.ResponseWriter, r *http.Request) {
+		writeControlJSON(w, http.StatusOK, a.queueSummary())
+	})
+	for action, run := range map[string]func(string) error{
+		"resume":  a.ResumeTask,
+		"cancel":  a.CancelTask,
+		"pause":   a.PauseTask,
+		"unpause": a.UnpauseTask,
+		"confirm": a.ConfirmTask,
+	} {
+		mux.HandleFunc("POST /v1/tasks/{id}/"+action, func(w http.ResponseWriter, r *http.Request) {
+			if err := run(r.PathValue("id")); err != nil {
+				writeControlError(w, err)
+				return
+			}
+			writeControlJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		})
+	}
+	return mux
+}
+
+func writeControlError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if err.Error() == "task not found" {
+		status = http.StatusNotFound
+	}
+	writeControlJSON(w, status, controlError{Error: err.Error()})
+}
+
+// remoteOutputDir checks that an output folder sent to the remote listener is
+// the download folder or inside it.
+func remoteOutputDir(dir string) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return "", nil
+	}
+	root, err := downloadRoot()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Clean(dir)
+	rel, err := filepath.Rel(filepath.Clean(root), dir)
+	if !filepath.IsAbs(dir) || err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.New("output folder must be inside the download folder")
+	}
+	return dir, nil
+}
+
+func writeControlJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultCookieBrowser = "chrome"
+
+// cookieBrowsers are the browsers offered for --cookies-from-browser.
+var cookieBrowsers = []string{"chrome", "firefox", "edge", "safari", "brave", "chromium", "opera", "vivaldi"}
+
+// CookieSettings selects where yt-dlp and gallery-dl read cookies from.
+// Browser reads them from an installed browser; CookiesFile points at a
+// Netscape-format cookies.txt. Both empty disables cookies.
+type CookieSettings struct {
+	Browser     string   `json:"browser"`
+	CookiesFile string   `json:"cookiesFile"`
+	Browsers    []string `json:"browsers"`
+}
+
+// GetCookieSettings returns the cookie source and the supported browsers.
+func (a *App) GetCookieSettings() (CookieSettings, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return CookieSettings{
+		Browser:     a.cookieBrowser,
+		CookiesFile: a.cookiesFile,
+		Browsers:    append([]string{}, cookieBrowsers...),
+	}, nil
+}
+
+// SetCookieSettings changes the cookie source. A cookies file takes
+// precedence over the browser when both are set.
+func (a *App) SetCookieSettings(settings CookieSettings) error {
+	browser := strings.ToLower(strings.TrimSpace(settings.Browser))
+	if browser != "" && !isCookieBrowser(browser) {
+		return fmt.Errorf("unsupported browser %q", settings.Browser)
+	}
+	cookiesFile, err := validateCookiesFile(settings.CookiesFile)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.cookieBrowser = browser
+	a.cookiesFile = cookiesFile
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+func (a *App) GetUseBrowserCookies() (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cookieBrowser != "", nil
+}
+
+// SetUseBrowserCookies turns browser cookies on with the previously chosen
+// browser, or Chrome when none was chosen, or turns them off.
+func (a *App) SetUseBrowserCookies(enabled bool) error {
+	a.mu.Lock()
+	switch {
+	case !enabled:
+		a.cookieBrowser = ""
+	case a.cookieBrowser == "":
+		a.cookieBrowser = defaultCookieBrowser
+	}
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// cookieArgs returns the cookie arguments shared by yt-dlp and gallery-dl.
+func (a *App) cookieArgs() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cookiesFile != "" {
+		return []string{"--cookies", a.cookiesFile}
+	}
+	if a.cookieBrowser != "" {
+		return []string{"--cookies-from-browser", a.cookieBrowser}
+	}
+	return nil
+}
+
+func isCookieBrowser(browser string) bool {
+	for _, candidate := range cookieBrowsers {
+		if candidate == browser {
+			return true
+		}
+	}
+	return false
+}
+
+func validateCookiesFile(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", nil
+	}
+	if !filepath.IsAbs(path) {
+		return "", errors.New("cookies file must be an absolute path")
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", errors.New("cookies file not found")
+	}
+	return filepath.Clean(path), nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+const (
+	defaultCooldownMinutes = 10
+	maxCooldownMinutes     = 24 * 60
+)
+
+// HostCooldown is a host the scheduler is leaving alone after it rate
+// limited a download.
+type HostCooldown struct {
+	Host  string    `json:"host"`
+	Until time.Time `json:"until"`
+}
+
+// GetRateLimitCooldown returns how many minutes a host is left alone after
+// it answers with HTTP 429. Zero means no cool-down.
+func (a *App) GetRateLimitCooldown() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cooldownMinutes, nil
+}
+
+// SetRateLimitCooldown changes the cool-down after a rate-limited download.
+// Zero turns it off, so rate limiting is handled by the retry policy alone.
+func (a *App) SetRateLimitCooldown(minutes int) error {
+	if minutes < 0 || minutes > maxCooldownMinutes {
+		return errors.New("cool-down must be between 0 and 1440 minutes")
+	}
+	a.mu.Lock()
+	a.cooldownMinutes = minutes
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// GetHostCooldowns lists the hosts that are cooling down, soonest first.
+func (a *App) GetHostCooldowns() ([]HostCooldown, error) {
+	now := time.Now()
+	a.mu.Lock()
+	out := make([]HostCooldown, 0, len(a.hostCooldowns))
+	for host, until := range a.hostCooldowns {
+		if until.After(now) {
+			out = append(out, HostCooldown{Host: host, Until: until})
+		}
+	}
+	a.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Until.Before(out[j].Until) })
+	return out, nil
+}
+
+// coolingDownLocked reports whether new downloads from host must wait. The
+// caller must hold a.mu.
+func (a *App) coolingDownLocked(host string, now time.Time) bool {
+	until, ok := a.hostCooldowns[host]
+	if ok && !until.After(now) {
+		delete(a.hostCooldowns, host)
+		return false
+	}
+	return ok
+}
+
+// scheduleCooldownLocked handles a rate-limited failure. The task's host
+// cools down, so no download from it starts for the cool-down interval, and
+// the task is queued again once the interval is over. The retry counts
+// against the task's retries like any other. The caller must hold a.mu.
+func (a *App) scheduleCooldownLocked(task *Task, failure taskError) bool {
+	if failure.Code != errorRateLimited || a.cooldownMinutes == 0 || task.RetryCount >= task.MaxRetries {
+		return false
+	}
+	now := time.Now()
+	host := sourceHostFromURL(task.URL)
+	until := now.Add(time.Duration(a.cooldownMinutes) * time.Minute)
+	if current, ok := a.hostCooldowns[host]; !ok || current.Before(until) {
+		a.hostCooldowns[host] = until
+	}
+	until = a.hostCooldowns[host]
+
+	task.RetryCount++
+	task.setStatus(statusQueued)
+	task.setError(failure)
+	task.Resume = true
+	task.Speed = ""
+	task.ETA = ""
+	task.UpdatedAt = now
+	task.setStageCode(stageCooldown, map[string]string{
+		"host": host,
+		"time": until.Local().Format("15:04"),
+	}, task.UpdatedAt)
+
+	id := task.ID
+	time.AfterFunc(until.Sub(now), func() {
+		a.mu.Lock()
+		current, ok := a.tasks[id]
+		waiting := ok && current.Status == statusQueued && current.StageCode == stageCooldown
+		// Wake the worker for other tasks from the host, too.
+		a.slotsChanged.Broadcast()
+		a.mu.Unlock()
+		if waiting {
+			a.enqueueTasks([]string{id})
+		}
+	})
+	return true
+}
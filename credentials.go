@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const keychainService = "FetchForge"
+
+var errKeychainUnavailable = errors.New("no system keychain is available")
+
+// credentialsFileMu guards credentials.json, the fallback secret store used
+// when the platform has no keychain.
+var credentialsFileMu sync.Mutex
+
+// SiteCredential is a stored login for a source host. The password itself is
+// kept in the system keychain, or in a private file when there is none, and
+// is never returned to the UI.
+type SiteCredential struct {
+	Host       string `json:"host"`
+	Username   string `json:"username"`
+	InKeychain bool   `json:"inKeychain"`
+}
+
+// ListSiteCredentials returns the hosts with stored logins.
+func (a *App) ListSiteCredentials() ([]SiteCredential, error) {
+	a.mu.Lock()
+	out := make([]SiteCredential, len(a.siteCredentials))
+	copy(out, a.siteCredentials)
+	a.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Host < out[j].Host })
+	return out, nil
+}
+
+// SetSiteCredentials stores a login that is passed to yt-dlp as
+// --username/--password for downloads from host and its subdomains.
+func (a *App) SetSiteCredentials(host string, username string, password string) (SiteCredential, error) {
+	host = normalizeOverrideHost(host)
+	username = strings.TrimSpace(username)
+	if host == "" {
+		return SiteCredential{}, errors.New("host is required")
+	}
+	if username == "" || password == "" {
+		return SiteCredential{}, errors.New("username and password are required")
+	}
+	credential := SiteCredential{Host: host, Username: username, InKeychain: true}
+	if err := keychainSet(host, password); err != nil {
+		if err := writeFileSecret(host, password); err != nil {
+			return SiteCredential{}, err
+		}
+		credential.InKeychain = false
+	} else {
+		_ = deleteFileSecret(host)
+	}
+
+	a.mu.Lock()
+	replaced := false
+	for i := range a.siteCredentials {
+		if a.siteCredentials[i].Host == host {
+			a.siteCredentials[i] = credential
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		a.siteCredentials = append(a.siteCredentials, credential)
+	}
+	a.mu.Unlock()
+	a.saveConfig()
+	return credential, nil
+}
+
+// DeleteSiteCredentials removes the login stored for host.
+func (a *App) DeleteSiteCredentials(host string) error {
+	host = normalizeOverrideHost(host)
+	a.mu.Lock()
+	index := -1
+	for i := range a.siteCredentials {
+		if a.siteCredentials[i].Host == host {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		a.mu.Unlock()
+		return errors.New("credentials not found")
+	}
+	credential := a.siteCredentials[index]
+	a.siteCredentials = append(a.siteCredentials[:index:index], a.siteCredentials[index+1:]...)
+	a.mu.Unlock()
+	a.saveConfig()
+
+	if credential.InKeychain {
+		return keychainDelete(host)
+	}
+	return deleteFileSecret(host)
+}
+
+// credentialArgs returns --username/--password for the most specific stored
+// login matching host, or nothing when there is none or the secret is gone.
+func (a *App) credentialArgs(host string) []string {
+	host = normalizeOverrideHost(host)
+	a.mu.Lock()
+	var best SiteCredential
+	found := false
+	for _, credential := range a.siteCredentials {
+		if host != credential.Host && !strings.HasSuffix(host, "."+credential.Host) {
+			continue
+		}
+		if !found || len(credential.Host) > len(best.Host) {
+			best = credential
+			found = true
+		}
+	}
+	a.mu.Unlock()
+	if !found {
+		return nil
+	}
+	var password string
+	var err error
+	if best.InKeychain {
+		password, err = keychainGet(best.Host)
+	} else {
+		password, err = readFileSecret(best.Host)
+	}
+	if err != nil || password == "" {
+		return nil
+	}
+	return []string{"--username", best.Username, "--password", password}
+}
+
+// redactArgs hides passwords in a command line before it is logged or shown.
+func redactArgs(args []string) []string {
+	out := make([]string, len(args))
+	copy(out, args)
+	for i := 0; i < len(out); i++ {
+		switch out[i] {
+		case "--password", "-p", "--video-password", "--ap-password":
+			if i+1 < len(out) {
+				out[i+1] = "********"
+				i++
+			}
+		case "--proxy":
+			if i+1 < len(out) {
+				if parsed, err := url.Parse(out[i+1]); err == nil && parsed.User != nil {
+					if _, ok := parsed.User.Password(); ok {
+						parsed.User = url.UserPassword(parsed.User.Username(), "********")
+						out[i+1] = parsed.String()
+					}
+				}
+				i++
+			}
+		}
+	}
+	return out
+}
+
+func credentialsFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "credentials.json"), nil
+}
+
+func readFileSecrets() (map[string]string, string, error) {
+	path, err := credentialsFilePath()
+	if err != nil {
+		return nil, "", err
+	}
+	secrets := map[string]string{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return secrets, path, nil
+		}
+		return nil, "", err
+	}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, "", err
+	}
+	return secrets, path, nil
+}
+
+func writeFileSecrets(path string, secrets map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(secrets, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func readFileSecret(host string) (string, error) {
+	credentialsFileMu.Lock()
+	defer credentialsFileMu.Unlock()
+	secrets, _, err := readFileSecrets()
+	if err != nil {
+		return "", err
+	}
+	return secrets[host], nil
+}
+
+func writeFileSecret(host, password string) error {
+	credentialsFileMu.Lock()
+	defer credentialsFileMu.Unlock()
+	secrets, path, err := readFileSecrets()
+	if err != nil {
+		return err
+	}
+	secrets[host] = password
+	return writeFileSecrets(path, secrets)
+}
+
+func deleteFileSecret(host string) error {
+	credentialsFileMu.Lock()
+	defer credentialsFileMu.Unlock()
+	secrets, path, err := readFileSecrets()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[host]; !ok {
+		return nil
+	}
+	delete(secrets, host)
+	return writeFileSecrets(path, secrets)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var taskCSVHeader = []string{
+	"id", "url", "title", "source_host", "status", "filesize_bytes",
+	"resolution", "duration_seconds", "output_path", "created_at", "updated_at",
+}
+
+// ExportTasksCSV returns the task list as CSV, one row per task.
+func (a *App) ExportTasksCSV() (string, error) {
+	data, err := a.tasksCSV()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ExportTasksCSVToFile writes the CSV export to the Downloads folder and
+// returns its path. The file starts with a UTF-8 byte order mark so
+// spreadsheet apps read non-ASCII titles correctly.
+func (a *App) ExportTasksCSVToFile() (string, error) {
+	data, err := a.tasksCSV()
+	if err != nil {
+		return "", err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	downloadDir := filepath.Join(home, "Downloads")
+	if err := os.MkdirAll(downloadDir, 0o755); err != nil {
+		return "", err
+	}
+	filename := fmt.Sprintf("fetchforge-tasks-%s.csv", time.Now().Format("2006-01-02"))
+	path := filepath.Join(downloadDir, filename)
+	if err := os.WriteFile(path, append([]byte("\ufeff"), data...), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (a *App) tasksCSV() ([]byte, error) {
+	a.mu.Lock()
+	snapshot := make([]Task, 0, len(a.order))
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok && !task.deleted() {
+			snapshot = append(snapshot, *task)
+		}
+	}
+	a.mu.Unlock()
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(taskCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, task := range snapshot {
+		resolution := ""
+		if task.Width > 0 && task.Height > 0 {
+			resolution = fmt.Sprintf("%dx%d", task.Width, task.Height)
+		}
+		record := []string{
+			task.ID,
+			csvCell(task.URL),
+			csvCell(task.Title),
+			csvCell(task.SourceHost),
+			task.Status,
+			strconv.FormatInt(taskSize(task), 10),
+			resolution,
+			strconv.Itoa(task.Duration),
+			csvCell(task.OutputPath),
+			task.CreatedAt.Format(time.RFC3339),
+			task.UpdatedAt.Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// csvCell keeps spreadsheet apps from treating a title such as "=1+1" as a
+// formula.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/options"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const deepLinkScheme = "fetchforge"
+
+// Deep links look like fetchforge://add?url=<encoded url>; url may repeat.
+// macOS delivers them through OnUrlOpen, Windows and Linux start the app with
+// the link as an argument, which the single-instance lock forwards to the
+// running window.
+
+// handleDeepLink queues the downloads named by a fetchforge:// link. Links
+// that arrive before startup are held until the task list is loaded.
+func (a *App) handleDeepLink(link string) {
+	a.mu.Lock()
+	if !a.started {
+		a.pendingDeepLinks = append(a.pendingDeepLinks, link)
+		a.mu.Unlock()
+		return
+	}
+	a.mu.Unlock()
+
+	urls, err := parseDeepLink(link)
+	if err != nil {
+		return
+	}
+	created, err := a.CreateTasksFromText(strings.Join(urls, "\n"), "")
+	if err != nil || a.ctx == nil {
+		return
+	}
+	wailsruntime.WindowUnminimise(a.ctx)
+	wailsruntime.WindowShow(a.ctx)
+	wailsruntime.EventsEmit(a.ctx, "deeplink:added", created)
+}
+
+// onSecondInstanceLaunch receives the arguments of a second launch, which is
+// how Windows and Linux hand over a clicked fetchforge:// link.
+func (a *App) onSecondInstanceLaunch(data options.SecondInstanceData) {
+	a.handleLaunchArgs(data.Args)
+}
+
+func (a *App) handleLaunchArgs(args []string) {
+	for _, arg := range args {
+		if strings.HasPrefix(strings.ToLower(arg), deepLinkScheme+"://") {
+			a.handleDeepLink(arg)
+		}
+	}
+}
+
+// startDeepLinks marks the app ready and handles links received so far,
+// including one passed on the command line.
+func (a *App) startDeepLinks() {
+	a.mu.Lock()
+	a.started = true
+	pending := a.pendingDeepLinks
+	a.pendingDeepLinks = nil
+	a.mu.Unlock()
+	a.handleLaunchArgs(os.Args[1:])
+	for _, link := range pending {
+		a.handleDeepLink(link)
+	}
+}
+
+func parseDeepLink(link string) ([]string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || !strings.EqualFold(parsed.Scheme, deepLinkScheme) {
+		return nil, errors.New("not a fetchforge link")
+	}
+	action := parsed.Host
+	if action == "" {
+		action = strings.Trim(parsed.Opaque+parsed.Path, "/")
+	}
+	if !strings.EqualFold(strings.Trim(action, "/"), "add") {
+		return nil, errors.New("unsupported fetchforge action")
+	}
+	var urls []string
+	for _, target := range parsed.Query()["url"] {
+		target = strings.TrimSpace(target)
+		if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+			urls = append(urls, target)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, errors.New("link has no url to add")
+	}
+	return urls, nil
+}
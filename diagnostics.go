@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	diagnosticOK      = "ok"
+	diagnosticWarning = "warning"
+	diagnosticError   = "error"
+
+	diagnosticTimeout = 10 * time.Second
+	lowDiskSpaceBytes = 5 << 30
+	networkProbeURL   = "https://www.youtube.com/generate_204"
+)
+
+// DiagnosticCheck is the result of one environment check.
+type DiagnosticCheck struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Hint   string `json:"hint"`
+}
+
+// DiagnosticsReport bundles all checks run by RunDiagnostics.
+type DiagnosticsReport struct {
+	OK     bool              `json:"ok"`
+	Checks []DiagnosticCheck `json:"checks"`
+	RanAt  time.Time         `json:"ranAt"`
+}
+
+// RunDiagnostics checks the tools and environment downloads depend on.
+// OK is false when any check reports an error; warnings do not block.
+func (a *App) RunDiagnostics() (DiagnosticsReport, error) {
+	checks := []DiagnosticCheck{
+		a.checkYtDlp(),
+		a.checkFfmpeg(),
+		a.checkGalleryDl(),
+		a.checkAria2c(),
+	}
+	checks = append(checks, checkDownloadDir()...)
+	checks = append(checks, checkNetwork())
+
+	report := DiagnosticsReport{OK: true, Checks: checks, RanAt: time.Now()}
+	for _, check := range checks {
+		if check.Status == diagnosticError {
+			report.OK = false
+		}
+	}
+	return report, nil
+}
+
+func (a *App) checkYtDlp() DiagnosticCheck {
+	check := DiagnosticCheck{ID: "yt-dlp", Name: "yt-dlp"}
+	path := a.ytDlpBinary()
+	if path == "" {
+		check.Status = diagnosticError
+		check.Detail = "yt-dlp was not found"
+		check.Hint = "Install yt-dlp or set FETCHFORGE_YTDLP_PATH to its location."
+		return check
+	}
+	version, err := commandVersion(path, "--version")
+	if err != nil {
+		check.Status = diagnosticError
+		check.Detail = path + ": " + err.Error()
+		check.Hint = "The yt-dlp binary could not be run; reinstall it."
+		return check
+	}
+	check.Status = diagnosticOK
+	check.Detail = version + " (" + path + ")"
+	return check
+}
+
+func (a *App) checkFfmpeg() DiagnosticCheck {
+	check := DiagnosticCheck{ID: "ffmpeg", Name: "ffmpeg"}
+	path := a.ffmpegBinary()
+	if path == "" {
+		check.Status = diagnosticWarning
+		check.Detail = "ffmpeg was not found"
+		check.Hint = "Install ffmpeg, or let FetchForge download it, to merge separate video/audio streams and run transcodes."
+		return check
+	}
+	version, err := commandVersion(path, "-version")
+	if err != nil {
+		check.Status = diagnosticWarning
+		check.Detail = path + ": " + err.Error()
+		return check
+	}
+	check.Status = diagnosticOK
+	check.Detail = version
+	return check
+}
+
+func (a *App) checkGalleryDl() DiagnosticCheck {
+	check := DiagnosticCheck{ID: "gallery-dl", Name: "gallery-dl"}
+	if a.galleryDlPath == "" {
+		check.Status = diagnosticWarning
+		check.Detail = "gallery-dl was not found"
+		check.Hint = "Optional: install gallery-dl to download image galleries."
+		return check
+	}
+	version, err := commandVersion(a.galleryDlPath, "--version")
+	if err != nil {
+		check.Status = diagnosticWarning
+		check.Detail = a.galleryDlPath + ": " + err.Error()
+		return check
+	}
+	check.Status = diagnosticOK
+	check.Detail = version + " (" + a.galleryDlPath + ")"
+	return check
+}
+
+func checkDownloadDir() []DiagnosticCheck {
+	write := DiagnosticCheck{ID: "download-dir", Name: "Download folder"}
+	space := DiagnosticCheck{ID: "disk-space", Name: "Free disk space"}
+
+	root, err := downloadRoot()
+	if err != nil {
+		write.Status = diagnosticError
+		write.Detail = err.Error()
+		return []DiagnosticCheck{write}
+	}
+	if _, err := validateOutputDir(root); err != nil {
+		write.Status = diagnosticError
+		write.Detail = root + ": " + err.Error()
+		write.Hint = "Check the folder permissions or choose another download folder."
+	} else {
+		write.Status = diagnosticOK
+		write.Detail = root
+	}
+
+	free, err := freeDiskSpace(root)
+	switch {
+	case err != nil:
+		space.Status = diagnosticWarning
+		space.Detail = err.Error()
+	case free < lowDiskSpaceBytes:
+		space.Status = diagnosticWarning
+		space.Detail = formatBytes(int64(free)) + " available"
+		space.Hint = "Less than 5 GiB free; large downloads may fail."
+	default:
+		space.Status = diagnosticOK
+		space.Detail = formatBytes(int64(free)) + " available"
+	}
+	return []DiagnosticCheck{write, space}
+}
+
+func checkNetwork() DiagnosticCheck {
+	check := DiagnosticCheck{ID: "network", Name: "Network"}
+	client := &http.Client{Timeout: diagnosticTimeout}
+	resp, err := client.Get(networkProbeURL)
+	if err != nil {
+		check.Status = diagnosticError
+		check.Detail = err.Error()
+		check.Hint = "Check your internet connection, proxy, or firewall."
+		return check
+	}
+	resp.Body.Close()
+	check.Status = diagnosticOK
+	check.Detail = fmt.Sprintf("%s responded %d", networkProbeURL, resp.StatusCode)
+	return check
+}
+
+// commandVersion runs a binary's version flag and returns the first line.
+func commandVersion(path string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), diagnosticTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, args...).Output()
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// FileFilter narrows the files shown by PickFile, e.g.
+// {DisplayName: "Cookies (*.txt)", Pattern: "*.txt"}.
+type FileFilter struct {
+	DisplayName string `json:"displayName"`
+	Pattern     string `json:"pattern"`
+}
+
+// PickDirectory shows a native folder picker. It returns an empty string when
+// the dialog is cancelled.
+func (a *App) PickDirectory(title string) (string, error) {
+	if a.ctx == nil {
+		return "", errors.New("app not ready")
+	}
+	return wailsruntime.OpenDirectoryDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title:                title,
+		CanCreateDirectories: true,
+	})
+}
+
+// PickFile shows a native file picker. It returns an empty string when the
+// dialog is cancelled.
+func (a *App) PickFile(title string, filters []FileFilter) (string, error) {
+	if a.ctx == nil {
+		return "", errors.New("app not ready")
+	}
+	options := wailsruntime.OpenDialogOptions{Title: title}
+	for _, filter := range filters {
+		options.Filters = append(options.Filters, wailsruntime.FileFilter{
+			DisplayName: filter.DisplayName,
+			Pattern:     filter.Pattern,
+		})
+	}
+	return wailsruntime.OpenFileDialog(a.ctx, options)
+}
+
+// RevealInFileManager opens the system file manager with path selected.
+// Folders are opened directly.
+func (a *App) RevealInFileManager(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return errors.New("path is required")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.New("path not found")
+	}
+	if info.IsDir() {
+		return openWithDefaultApp(path)
+	}
+	return revealFile(path)
+}
+
+func revealFile(path string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", "-R", path).Start()
+	case "windows":
+		return exec.Command("explorer", "/select,", path).Start()
+	default:
+		fileURL := (&url.URL{Scheme: "file", Path: path}).String()
+		cmd := exec.Command("dbus-send", "--session", "--print-reply",
+			"--dest=org.freedesktop.FileManager1", "--type=method_call",
+			"/org/freedesktop/FileManager1", "org.freedesktop.FileManager1.ShowItems",
+			"array:string:"+fileURL, "string:")
+		if err := cmd.Run(); err == nil {
+			return nil
+		}
+		return openWithDefaultApp(filepath.Dir(path))
+	}
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	engineDirect           = "direct"
+	directProgressInterval = 500 * time.Millisecond
+	directStallTimeout     = time.Minute
+)
+
+// directExtensions are file types downloaded with the built-in HTTP engine
+// instead of yt-dlp when a URL points straight at them. Streaming playlists
+// such as .m3u8 stay with yt-dlp.
+var directExtensions = map[string]bool{
+	".pdf": true, ".zip": true, ".rar": true, ".7z": true, ".tar": true,
+	".gz": true, ".tgz": true, ".bz2": true, ".xz": true, ".iso": true,
+	".dmg": true, ".exe": true, ".msi": true, ".apk": true, ".deb": true,
+	".rpm": true, ".epub": true, ".mp3": true, ".m4a": true, ".flac": true,
+	".wav": true, ".ogg": true, ".opus": true, ".mp4": true, ".mkv": true,
+	".webm": true, ".mov": true, ".avi": true,
+}
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// isDirectFileURL reports whether a URL's path ends in a file extension the
+// direct engine handles.
+func isDirectFileURL(rawURL string) bool {
+	return directExtensions[urlFileExt(rawURL)]
+}
+
+// urlFileExt returns the lower-case extension of an http(s) URL's path.
+func urlFileExt(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ""
+	}
+	return strings.ToLower(path.Ext(parsed.Path))
+}
+
+// normalizeSHA256 validates an expected checksum, accepting an optional
+// "sha256:" prefix.
+func normalizeSHA256(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimPrefix(value, "sha256:")
+	if value == "" {
+		return "", nil
+	}
+	if !sha256Pattern.MatchString(value) {
+		return "", errors.New("checksum must be a SHA-256 hex digest")
+	}
+	return value, nil
+}
+
+// runDirectTask downloads a plain file over HTTP into outputDir. The file is
+// written to a .part file whose path is kept on the task, so a retry or
+// resume continues it with a Range request. It returns false, without
+// touching the task, when the server answers with a web page rather than a
+// file; the caller then hands the URL to yt-dlp.
+func (a *App) runDirectTask(id, targetURL, outputDir, taskProxy string) bool {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return true
+	}
+	partPath := task.PartialPath
+	expected := task.ExpectedSHA256
+	if taskProxy == "" {
+		taskProxy = a.proxy
+	}
+	a.mu.Unlock()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = directStallTimeout
+	if taskProxy != "" {
+		proxyURL, err := url.Parse(taskProxy)
+		if err != nil {
+			a.failTask(id, taskError{Code: errorInvalidProxy, Params: map[string]string{"detail": err.Error()}})
+			return true
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	client := &http.Client{Transport: transport}
+
+	var offset int64
+	if partPath != "" {
+		if info, err := os.Stat(partPath); err == nil {
+			offset = info.Size()
+		} else {
+			partPath = ""
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		a.failTask(id, taskError{Code: errorUnsupportedURL, Message: err.Error()})
+		return true
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; FetchForge)")
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		a.failTask(id, taskError{Code: errorNetwork, Params: map[string]string{"detail": err.Error()}, Message: err.Error()})
+		return true
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+	case resp.StatusCode == http.StatusOK:
+		offset = 0
+		mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+			return false
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The .part file no longer matches the remote file; start over.
+		_ = os.Remove(partPath)
+		a.setPartialPath(id, "")
+		a.failTask(id, taskError{Code: errorNetwork, Message: "the partial download could not be resumed"})
+		return true
+	default:
+		a.failTask(id, httpStatusFailure(resp))
+		return true
+	}
+
+	if partPath == "" {
+		name := directFilename(resp, targetURL)
+		if mode := a.currentFilenameMode(); mode == filenameModeRestrict {
+			name = safeFilename(name, mode)
+		}
+		partPath = uniquePath(filepath.Join(outputDir, name)) + ".part"
+		a.setPartialPath(id, partPath)
+	}
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+
+	a.mu.Lock()
+	if task, ok := a.tasks[id]; ok {
+		task.setOutputs(nil)
+		if task.needsTitle() {
+			task.Title = strings.TrimSuffix(filepath.Base(partPath), ".part")
+		}
+		task.UpdatedAt = time.Now()
+		task.setStageCode(stageDownload, nil, task.UpdatedAt)
+		updated := *task
+		a.mu.Unlock()
+		a.emitTaskUpdate(updated)
+	} else {
+		a.mu.Unlock()
+		return true
+	}
+
+	hasher := sha256.New()
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 {
+		if err := hashExisting(hasher, partPath); err != nil {
+			a.failTask(id, taskError{Code: errorOutputDir, Message: err.Error()})
+			return true
+		}
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		a.failTask(id, taskError{Code: errorOutputDir, Message: "failed to create output file"})
+		return true
+	}
+
+	downloaded, err := a.copyDirect(id, file, io.TeeReader(resp.Body, hasher), offset, total, cancel)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if a.taskStopped(id) {
+		return true
+	}
+	if err != nil {
+		code, params := classifyErrorText(err.Error())
+		if code == errorCommandFailed {
+			code = errorNetwork
+		}
+		params["detail"] = err.Error()
+		a.failTask(id, taskError{Code: code, Params: params, Message: err.Error()})
+		return true
+	}
+	if total >= 0 && downloaded != total {
+		a.failTask(id, taskError{Code: errorNetwork, Message: "the download ended early"})
+		return true
+	}
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if expected != "" && sum != expected {
+		_ = os.Remove(partPath)
+		a.setPartialPath(id, "")
+		a.failTask(id, taskError{Code: errorChecksum, Params: map[string]string{"expected": expected, "actual": sum}})
+		return true
+	}
+	finalPath := uniquePath(strings.TrimSuffix(partPath, ".part"))
+	if err := os.Rename(partPath, finalPath); err != nil {
+		a.failTask(id, taskError{Code: errorOutputDir, Message: err.Error()})
+		return true
+	}
+
+	a.mu.Lock()
+	task, ok = a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return true
+	}
+	output := statOutput(finalPath, outputKindForPath(finalPath))
+	output.SHA256 = sum
+	task.PartialPath = ""
+	task.setStatus(statusSuccess)
+	task.setOutputs([]TaskOutput{output})
+	task.clearError()
+	task.Progress = "100%"
+	task.Speed = ""
+	task.ETA = ""
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stageFinalize, nil, task.UpdatedAt)
+	task.closeStage(task.UpdatedAt)
+	updated := *task
+	delete(a.estimators, id)
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	go a.recordChecksumsAndCheckDuplicate(id)
+	return true
+}
+
+// copyDirect copies the response body to file and reports progress. A
+// watcher cancels the request as soon as the task is canceled, paused or
+// interrupted, or when no data arrives for directStallTimeout. It returns
+// the total size written, including offset.
+func (a *App) copyDirect(id string, file *os.File, body io.Reader, offset, total int64, cancel context.CancelFunc) (int64, error) {
+	var progressed atomic.Int64
+	progressed.Store(time.Now().UnixNano())
+	var stalled atomic.Bool
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(directProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if a.taskStopped(id) {
+					cancel()
+					return
+				}
+				if time.Since(time.Unix(0, progressed.Load())) > directStallTimeout {
+					stalled.Store(true)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	buffer := make([]byte, 64*1024)
+	written := offset
+	lastReport := time.Now()
+	lastWritten := written
+	for {
+		n, readErr := body.Read(buffer)
+		if n > 0 {
+			if _, err := file.Write(buffer[:n]); err != nil {
+				return written, err
+			}
+			written += int64(n)
+			progressed.Store(time.Now().UnixNano())
+		}
+		if readErr == io.EOF {
+			return written, nil
+		}
+		if readErr != nil {
+			if stalled.Load() {
+				return written, errors.New("the download stalled")
+			}
+			return written, readErr
+		}
+		if elapsed := time.Since(lastReport); elapsed >= directProgressInterval {
+			update := progressUpdate{downloaded: written, rawSpeed: float64(written-lastWritten) / elapsed.Seconds()}
+			if total > 0 {
+				update.total = total
+				update.percent = fmt.Sprintf("%.1f%%", float64(written)/float64(total)*100)
+			} else {
+				update.percent = formatBytes(written)
+			}
+			a.applyProgressUpdate(id, update)
+			a.saveTasksSoon()
+			lastReport = time.Now()
+			lastWritten = written
+		}
+	}
+}
+
+// directFilename picks the saved name from Content-Disposition, falling back
+// to the last path segment of the final (post-redirect) URL.
+func directFilename(resp *http.Response, targetURL string) string {
+	name := ""
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		name = params["filename"]
+	}
+	if name == "" && resp.Request != nil {
+		name, _ = url.PathUnescape(path.Base(resp.Request.URL.Path))
+	}
+	if name == "" {
+		if parsed, err := url.Parse(targetURL); err == nil {
+			name, _ = url.PathUnescape(path.Base(parsed.Path))
+		}
+	}
+	name = sanitizeFilename(filepath.Base(name))
+	if name == "" || name == "/" {
+		name = "download"
+	}
+	return name
+}
+
+func hashExisting(hasher hash.Hash, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(hasher, file)
+	return err
+}
+
+// setPartialPath records where a direct download keeps its .part file.
+func (a *App) setPartialPath(id, partPath string) {
+	a.mu.Lock()
+	if task, ok := a.tasks[id]; ok {
+		task.PartialPath = partPath
+	}
+	a.mu.Unlock()
+	a.saveTasksSoon()
+}
+
+// httpStatusFailure maps an HTTP error status to a coded task error.
+func httpStatusFailure(resp *http.Response) taskError {
+	params := map[string]string{"status": resp.Status}
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return taskError{Code: errorLoginRequired, Params: params}
+	case http.StatusForbidden:
+		return taskError{Code: errorForbidden, Params: params}
+	case http.StatusNotFound, http.StatusGone:
+		return taskError{Code: errorUnavailable, Params: params}
+	case http.StatusTooManyRequests:
+		return taskError{Code: errorRateLimited, Params: params}
+	}
+	return taskError{Code: errorHTTPStatus, Params: params}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// downloadDirectory overrides the default download root when set. It is
+// package state because downloadRoot is used outside of App methods.
+var (
+	downloadDirMu     sync.RWMutex
+	downloadDirectory string
+)
+
+// GetDownloadDirectory returns the folder that holds the dated download
+// folders.
+func (a *App) GetDownloadDirectory() (string, error) {
+	return downloadRoot()
+}
+
+// SetDownloadDirectory changes where new downloads are stored. Empty input
+// restores the default ~/.fetchforge/downloads. Existing tasks keep their
+// files: tasks that used the old root are pinned to their old dated folder.
+func (a *App) SetDownloadDirectory(path string) (string, error) {
+	dir, err := validateOutputDir(path)
+	if err != nil {
+		return "", err
+	}
+	oldRoot, err := downloadRoot()
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	setDownloadDirectory(dir)
+	newRoot, _ := downloadRoot()
+	now := time.Now()
+	var updated []Task
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok || task.OutputDir != "" || newRoot == oldRoot {
+			continue
+		}
+		task.OutputDir = filepath.Join(oldRoot, task.CreatedAt.Format("2006-01-02"))
+		task.UpdatedAt = now
+		updated = append(updated, *task)
+	}
+	a.mu.Unlock()
+
+	for _, task := range updated {
+		a.emitTaskUpdate(task)
+	}
+	if len(updated) > 0 {
+		a.saveTasks()
+	}
+	a.saveConfig()
+	return newRoot, nil
+}
+
+func setDownloadDirectory(dir string) {
+	downloadDirMu.Lock()
+	downloadDirectory = dir
+	downloadDirMu.Unlock()
+}
+
+func configuredDownloadDirectory() string {
+	downloadDirMu.RLock()
+	defer downloadDirMu.RUnlock()
+	return downloadDirectory
+}
+
+// downloadRoot is the folder that holds the dated download folders.
+func downloadRoot() (string, error) {
+	if dir := configuredDownloadDirectory(); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "downloads"), nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CommandPreview is the command a download would run. Argv starts with the
+// resolved binary and has passwords masked; it is empty for the built-in
+// direct engine. OutputDir is the folder before organize rules, which need
+// the video's metadata, are applied.
+type CommandPreview struct {
+	Engine    string   `json:"engine"`
+	Argv      []string `json:"argv"`
+	Command   string   `json:"command"`
+	OutputDir string   `json:"outputDir"`
+}
+
+// PreviewCommand returns the command a new task for url would run, using
+// profileID (or the active profile) and the per-task options in options. Its
+// URL and ProfileID fields are ignored in favour of the arguments.
+func (a *App) PreviewCommand(url string, profileID string, options TaskOptions) (CommandPreview, error) {
+	urls := extractURLs(url)
+	if len(urls) != 1 {
+		return CommandPreview{}, errors.New("enter exactly one URL")
+	}
+	url = urls[0]
+	outputDir, err := validateOutputDir(options.OutputDir)
+	if err != nil {
+		return CommandPreview{}, err
+	}
+	clipStart, clipEnd, err := normalizeClipRange(options.StartTime, options.EndTime)
+	if err != nil {
+		return CommandPreview{}, err
+	}
+	extraArgs, err := normalizeExtraArgs(options.ExtraArgs)
+	if err != nil {
+		return CommandPreview{}, err
+	}
+	if profileID != "" {
+		a.mu.Lock()
+		_, ok := a.findProfileLocked(profileID)
+		a.mu.Unlock()
+		if !ok {
+			return CommandPreview{}, errors.New("profile not found")
+		}
+	}
+	engine := a.engineForURL(url)
+	if options.Engine != "" {
+		if engine, err = a.checkEngine(strings.TrimSpace(options.Engine)); err != nil {
+			return CommandPreview{}, err
+		}
+	}
+	profile := a.taskProfile(profileID)
+	if outputDir == "" {
+		outputDir = profile.OutputDir
+	}
+	if outputDir, err = taskOutputDir(outputDir, time.Now()); err != nil {
+		return CommandPreview{}, err
+	}
+
+	preview := CommandPreview{Engine: engine, Argv: []string{}, OutputDir: outputDir}
+	switch engine {
+	case engineDirect:
+		preview.Command = "GET " + url
+		return preview, nil
+	case engineGalleryDl:
+		preview.Argv = append([]string{a.galleryDlPath}, redactArgs(a.galleryDlArgs(url, outputDir, ""))...)
+	default:
+		a.mu.Lock()
+		filenameTemplate := sanitizeTemplateLiterals(clipFilenameTemplate(a.filenameTemplateForLocked(profile), clipStart, clipEnd), a.filenameMode)
+		a.mu.Unlock()
+		fallback, hasFallback := a.knownFallback(sourceHostFromURL(url))
+		download := ytDlpDownload{
+			url:            url,
+			outputTemplate: filepath.Join(outputDir, filenameTemplate),
+			profile:        profile,
+			subtitles:      profile.Subtitles,
+			clipStart:      clipStart,
+			clipEnd:        clipEnd,
+			extraArgs:      extraArgs,
+			rateLimit:      a.rateLimitForHost(sourceHostFromURL(url), time.Now()),
+			collision:      a.currentCollisionPolicy(),
+		}
+		if hasFallback {
+			download.fallback = &fallback
+		}
+		preview.Argv = append([]string{a.ytDlpBinary()}, redactArgs(a.ytDlpDownloadArgs(download))...)
+	}
+	preview.Command = strings.Join(preview.Argv, " ")
+	return preview, nil
+}
+
+// recordCommand keeps the command a task is about to run on the task and as
+// the app's last command.
+func (a *App) recordCommand(id, tool string, args []string) {
+	command := tool + " " + strings.Join(redactArgs(args), " ")
+	a.mu.Lock()
+	a.lastCommand = command
+	if task, ok := a.tasks[id]; ok {
+		task.Command = command
+	}
+	a.mu.Unlock()
+	fmt.Println("FetchForge:", command)
+}
+
+// finishDryRun completes a dry-run task once its metadata and command are
+// resolved, without downloading anything.
+func (a *App) finishDryRun(id, command string) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	task.Command = command
+	task.setStatus(statusSuccess)
+	task.clearError()
+	task.Progress = ""
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stageDryRun, nil, task.UpdatedAt)
+	task.closeStage(task.UpdatedAt)
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DuplicateFile is one copy within a DuplicateGroup.
+type DuplicateFile struct {
+	Path    string    `json:"path"`
+	TaskID  string    `json:"taskId"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// DuplicateGroup is a set of files with identical content. Keep is the copy
+// proposed to keep (the oldest one owned by a task); Trash lists the rest.
+type DuplicateGroup struct {
+	SHA256 string          `json:"sha256"`
+	Size   int64           `json:"size"`
+	Files  []DuplicateFile `json:"files"`
+	Keep   string          `json:"keep"`
+	Trash  []string        `json:"trash"`
+}
+
+// mediaExtractor picks the stable extractor name from yt-dlp metadata.
+// extractor_key ("Youtube") is the same for every URL form of a site.
+func mediaExtractor(key, name string) string {
+	if key = strings.TrimSpace(key); key != "" {
+		return strings.ToLower(key)
+	}
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// setMediaID copies the extractor and video ID from resolved metadata.
+func (t *Task) setMediaID(metadata *Task) {
+	if metadata.Extractor != "" && metadata.VideoID != "" {
+		t.Extractor = metadata.Extractor
+		t.VideoID = metadata.VideoID
+	}
+}
+
+// mediaDuplicateLocked returns a finished task that downloaded the same
+// extractor and video ID, so youtu.be and youtube.com links to one video
+// match. Clips only match clips of the same range. The caller must hold a.mu.
+func (a *App) mediaDuplicateLocked(task *Task) (string, bool) {
+	if task.Extractor == "" || task.VideoID == "" {
+		return "", false
+	}
+	for _, id := range a.order {
+		other, ok := a.tasks[id]
+		if !ok || id == task.ID || other.deleted() || other.Status != statusSuccess {
+			continue
+		}
+		if other.Extractor == task.Extractor && other.VideoID == task.VideoID &&
+			other.StartTime == task.StartTime && other.EndTime == task.EndTime {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// skipMediaDuplicate reports whether a task about to download is a video that
+// was already downloaded, unless the task allows duplicates.
+func (a *App) skipMediaDuplicate(id string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	task, ok := a.tasks[id]
+	if !ok || task.AllowDuplicate {
+		return "", false
+	}
+	duplicateOf, duplicate := a.mediaDuplicateLocked(task)
+	if duplicate {
+		task.DuplicateOf = duplicateOf
+	}
+	return duplicateOf, duplicate
+}
+
+// DownloadDuplicate downloads a task even though the same video was already
+// downloaded by another task or is listed in the download archive.
+func (a *App) DownloadDuplicate(id string) error {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return errors.New("task not found")
+	}
+	task.AllowDuplicate = true
+	requeue := task.Status == statusFailed && (task.ErrorCode == errorDuplicate || task.ErrorCode == errorInArchive)
+	if requeue {
+		requeueTaskLocked(task, stageRestart, false)
+	}
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	if requeue {
+		a.enqueueTasks([]string{id})
+	}
+	return nil
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// checkDuplicate compares a finished task's primary output with the outputs
+// of earlier tasks. Only same-sized files are hashed.
+func (a *App) checkDuplicate(id string) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	primary, ok := primaryOutput(task.Outputs)
+	if !ok || primary.Missing || primary.Size == 0 {
+		a.mu.Unlock()
+		return
+	}
+	type candidate struct {
+		taskID string
+		output TaskOutput
+	}
+	var candidates []candidate
+	for _, otherID := range a.order {
+		other, ok := a.tasks[otherID]
+		if !ok || otherID == id || other.Status != statusSuccess {
+			continue
+		}
+		for _, output := range other.Outputs {
+			if output.Size == primary.Size && output.Path != primary.Path && !output.Missing {
+				candidates = append(candidates, candidate{taskID: otherID, output: output})
+			}
+		}
+	}
+	a.mu.Unlock()
+	if len(candidates) == 0 {
+		return
+	}
+
+	sum := primary.SHA256
+	if sum == "" || primary.Modified {
+		var err error
+		if sum, err = hashFile(primary.Path); err != nil {
+			return
+		}
+		if primary.SHA256 == "" {
+			a.recordOutputHash(id, primary.Path, sum)
+		}
+	}
+	for _, c := range candidates {
+		otherSum := c.output.SHA256
+		if otherSum == "" || c.output.Modified {
+			var err error
+			if otherSum, err = hashFile(c.output.Path); err != nil {
+				continue
+			}
+			if c.output.SHA256 == "" {
+				a.recordOutputHash(c.taskID, c.output.Path, otherSum)
+			}
+		}
+		if otherSum != sum {
+			continue
+		}
+		a.mu.Lock()
+		task, ok := a.tasks[id]
+		if !ok {
+			a.mu.Unlock()
+			return
+		}
+		task.DuplicateOf = c.taskID
+		task.UpdatedAt = time.Now()
+		updated := *task
+		a.mu.Unlock()
+		a.emitTaskUpdate(updated)
+		a.saveTasks()
+		return
+	}
+}
+
+func (a *App) recordOutputHash(id, path, sum string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	task, ok := a.tasks[id]
+	if !ok {
+		return
+	}
+	outputs := make([]TaskOutput, len(task.Outputs))
+	copy(outputs, task.Outputs)
+	for i := range outputs {
+		if outputs[i].Path == path {
+			outputs[i].SHA256 = sum
+		}
+	}
+	task.Outputs = outputs
+}
+
+// FindDuplicateFiles scans task outputs and the download folder for files
+// with identical content and proposes which copies to trash.
+func (a *App) FindDuplicateFiles() ([]DuplicateGroup, error) {
+	owners := make(map[string]string)
+	cachedHashes := make(map[string]string)
+	a.mu.Lock()
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok {
+			continue
+		}
+		for _, output := range task.Outputs {
+			path := filepath.Clean(output.Path)
+			owners[path] = id
+			if output.SHA256 != "" && !output.Modified {
+				cachedHashes[path] = output.SHA256
+			}
+		}
+	}
+	a.mu.Unlock()
+
+	files := make(map[string]DuplicateFile)
+	addFile := func(path string) {
+		path = filepath.Clean(path)
+		if _, ok := files[path]; ok {
+			return
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.Size() == 0 || isPartialFile(info.Name()) {
+			return
+		}
+		files[path] = DuplicateFile{Path: path, TaskID: owners[path], Size: info.Size(), ModTime: info.ModTime()}
+	}
+	for path := range owners {
+		addFile(path)
+	}
+	if root, err := downloadRoot(); err == nil {
+		_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				addFile(path)
+			}
+			return nil
+		})
+	}
+
+	bySize := make(map[int64][]DuplicateFile)
+	for _, file := range files {
+		bySize[file.Size] = append(bySize[file.Size], file)
+	}
+
+	var groups []DuplicateGroup
+	for size, sameSize := range bySize {
+		if len(sameSize) < 2 {
+			continue
+		}
+		byHash := make(map[string][]DuplicateFile)
+		for _, file := range sameSize {
+			sum := cachedHashes[file.Path]
+			if sum == "" {
+				var err error
+				if sum, err = hashFile(file.Path); err != nil {
+					continue
+				}
+			}
+			byHash[sum] = append(byHash[sum], file)
+		}
+		for sum, copies := range byHash {
+			if len(copies) < 2 {
+				continue
+			}
+			sort.Slice(copies, func(i, j int) bool {
+				if (copies[i].TaskID != "") != (copies[j].TaskID != "") {
+					return copies[i].TaskID != ""
+				}
+				return copies[i].ModTime.Before(copies[j].ModTime)
+			})
+			group := DuplicateGroup{SHA256: sum, Size: size, Files: copies, Keep: copies[0].Path}
+			for _, file := range copies[1:] {
+				group.Trash = append(group.Trash, file.Path)
+			}
+			groups = append(groups, group)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Size*int64(len(groups[i].Trash)) > groups[j].Size*int64(len(groups[j].Trash))
+	})
+	return groups, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import "regexp"
+
+const (
+	embedMetadata  = "metadata"
+	embedChapters  = "chapters"
+	embedThumbnail = "thumbnail"
+)
+
+// yt-dlp writes metadata and chapters in one post-processor step, and the
+// thumbnail in another. These lines mark a step that finished writing.
+var (
+	embedMetadataPattern  = regexp.MustCompile(`^\[Metadata\] Adding .+ to "`)
+	embedThumbnailPattern = regexp.MustCompile(`^\[EmbedThumbnail\] .*Adding thumbnail to "`)
+)
+
+// embedArgs adds the embedding options a profile turns on.
+func embedArgs(profile Profile) []string {
+	var args []string
+	if profile.EmbedMetadata {
+		args = append(args, "--embed-metadata")
+	}
+	if profile.EmbedChapters {
+		args = append(args, "--embed-chapters")
+	}
+	if profile.EmbedThumbnail {
+		args = append(args, "--embed-thumbnail")
+	}
+	return args
+}
+
+// observeEmbed records a finished embedding step and reports whether the
+// line was one.
+func (o *outputTracker) observeEmbed(line string) bool {
+	var step string
+	switch {
+	case embedMetadataPattern.MatchString(line):
+		step = embedMetadata
+	case embedThumbnailPattern.MatchString(line):
+		step = embedThumbnail
+	default:
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.embedSteps == nil {
+		o.embedSteps = make(map[string]bool)
+	}
+	o.embedSteps[step] = true
+	return true
+}
+
+// embedded lists the embeds the profile asked for whose step succeeded.
+func (o *outputTracker) embedded(profile Profile) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := []string{}
+	if profile.EmbedMetadata && o.embedSteps[embedMetadata] {
+		out = append(out, embedMetadata)
+	}
+	if profile.EmbedChapters && o.embedSteps[embedMetadata] {
+		out = append(out, embedChapters)
+	}
+	if profile.EmbedThumbnail && o.embedSteps[embedThumbnail] {
+		out = append(out, embedThumbnail)
+	}
+	return out
+}
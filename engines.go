@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// DownloadEngine describes one downloader backend. Tasks record the engine
+// they run with in Task.Engine; it is picked from the gallery-dl routing
+// rules when the task is created and can be changed with SetTaskEngine.
+type DownloadEngine struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Path        string `json:"path"`
+	Available   bool   `json:"available"`
+}
+
+// downloadEngines lists the backends in the order the UI shows them. path
+// returns the resolved executable, or "" when it is not installed; built-in
+// engines have no path and are always available.
+var downloadEngines = []struct {
+	id          string
+	name        string
+	description string
+	path        func(a *App) string
+}{
+	{engineYtDlp, "yt-dlp", "Video and audio sites", func(a *App) string { return a.ytDlpPath }},
+	{engineGalleryDl, "gallery-dl", "Image galleries and art sites", func(a *App) string { return a.galleryDlPath }},
+	{engineDirect, "Direct", "Plain file links, with resume and checksums", nil},
+}
+
+func (a *App) enginePath(index int) (string, bool) {
+	engine := downloadEngines[index]
+	if engine.path == nil {
+		return "", true
+	}
+	path := engine.path(a)
+	return path, path != ""
+}
+
+// ListDownloadEngines returns the downloader backends and whether each one is
+// installed.
+func (a *App) ListDownloadEngines() ([]DownloadEngine, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	engines := make([]DownloadEngine, 0, len(downloadEngines))
+	for i, engine := range downloadEngines {
+		path, available := a.enginePath(i)
+		engines = append(engines, DownloadEngine{
+			ID:          engine.id,
+			Name:        engine.name,
+			Description: engine.description,
+			Path:        path,
+			Available:   available,
+		})
+	}
+	return engines, nil
+}
+
+// SetTaskEngine switches the backend a task downloads with. An empty engine
+// goes back to picking one from the URL. The task must not be running.
+func (a *App) SetTaskEngine(id string, engine string) (Task, error) {
+	engine, err := a.resolveTaskEngine(id, engine)
+	if err != nil {
+		return Task{}, err
+	}
+
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is deleted")
+	}
+	if task.Status == statusRunning {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is running")
+	}
+	if engine != engineYtDlp && (task.StartTime != "" || task.EndTime != "") {
+		a.mu.Unlock()
+		return Task{}, errors.New("time ranges only work for video downloads")
+	}
+	task.Engine = engine
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
+
+// resolveTaskEngine checks a requested engine, falling back to the routing
+// rules for the task's URL when it is empty.
+func (a *App) resolveTaskEngine(id string, engine string) (string, error) {
+	engine = strings.TrimSpace(engine)
+	if engine == "" {
+		a.mu.Lock()
+		task, ok := a.tasks[id]
+		url := ""
+		if ok {
+			url = task.URL
+		}
+		a.mu.Unlock()
+		if !ok {
+			return "", errors.New("task not found")
+		}
+		return a.engineForURL(url), nil
+	}
+	return a.checkEngine(engine)
+}
+
+// checkEngine returns engine when it is a known, installed backend.
+func (a *App) checkEngine(engine string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, candidate := range downloadEngines {
+		if candidate.id != engine {
+			continue
+		}
+		if _, available := a.enginePath(i); !available {
+			return "", errors.New(candidate.name + " is not installed")
+		}
+		return engine, nil
+	}
+	return "", errors.New("unknown download engine")
+}
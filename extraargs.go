@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const maxExtraArgs = 64
+
+// blockedYtDlpArgs are options per-task arguments may not use: ones that run
+// commands or load code, pass arguments to other programs, define new
+// options, read or write arbitrary local files, replace the binaries
+// FetchForge manages, or move output where FetchForge cannot track it.
+var blockedYtDlpArgs = map[string]bool{
+	"--exec": true, "--exec-before-download": true, "--no-exec": true,
+	"--netrc-cmd": true, "--plugin-dirs": true, "--use-postprocessor": true,
+	"--downloader": true, "--external-downloader": true,
+	"--downloader-args": true, "--external-downloader-args": true,
+	"--postprocessor-args": true, "--ppa": true,
+	"--ffmpeg-location": true,
+	"--config-location": true, "--config-locations": true, "--alias": true,
+	"-a": true, "--batch-file": true, "--load-info-json": true,
+	"--cookies": true, "--print-to-file": true, "--download-archive": true,
+	"--netrc-location": true, "--cache-dir": true,
+	"--client-certificate": true, "--client-certificate-key": true,
+	"-o": true, "--output": true, "-P": true, "--paths": true,
+	"-U": true, "--update": true, "--update-to": true,
+}
+
+// prefixSafeYtDlpArgs are complete long options that are also the start of
+// a blocked one, such as --print and --print-to-file.
+var prefixSafeYtDlpArgs = map[string]bool{"--print": true, "--netrc": true}
+
+// ytDlpShortValueFlags are the short options that take a value. In a cluster
+// such as -xo, the rest of the argument after one of them is its value.
+const ytDlpShortValueFlags = "afIoNpPrRStu"
+
+// normalizeExtraArgs trims per-task yt-dlp arguments and rejects blocked
+// options, including their --option=value form, the abbreviations yt-dlp
+// accepts for long options and short options combined into one argument.
+func normalizeExtraArgs(args []string) ([]string, error) {
+	normalized := []string{}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		for _, name := range optionNames(arg) {
+			if blockedOption(name) {
+				return nil, errors.New(name + " is not allowed in extra arguments")
+			}
+		}
+		normalized = append(normalized, arg)
+	}
+	if len(normalized) > maxExtraArgs {
+		return nil, errors.New("too many extra arguments")
+	}
+	return normalized, nil
+}
+
+// blockedOption reports whether name is a blocked option or, for a long
+// option, an abbreviation yt-dlp could expand to one, such as --exec-b.
+func blockedOption(name string) bool {
+	if blockedYtDlpArgs[name] {
+		return true
+	}
+	if !strings.HasPrefix(name, "--") || len(name) <= 2 || prefixSafeYtDlpArgs[name] {
+		return false
+	}
+	for blocked := range blockedYtDlpArgs {
+		if strings.HasPrefix(blocked, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// optionNames returns the options in one argument: the name of a long option,
+// or each short option in a cluster like -xo up to the first that takes a
+// value, as in -ofile.
+func optionNames(arg string) []string {
+	if strings.HasPrefix(arg, "--") {
+		name, _, _ := strings.Cut(arg, "=")
+		return []string{name}
+	}
+	if !strings.HasPrefix(arg, "-") {
+		return nil
+	}
+	var names []string
+	for _, flag := range arg[1:] {
+		names = append(names, "-"+string(flag))
+		if strings.ContainsRune(ytDlpShortValueFlags, flag) {
+			break
+		}
+	}
+	return names
+}
+
+// SetTaskExtraArgs replaces the extra yt-dlp arguments passed to one task's
+// download, after the profile's own. They take effect the next time the
+// task runs.
+func (a *App) SetTaskExtraArgs(id string, args []string) (Task, error) {
+	normalized, err := normalizeExtraArgs(args)
+	if err != nil {
+		return Task{}, err
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is deleted")
+	}
+	if task.Status == statusRunning {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is running")
+	}
+	task.ExtraArgs = normalized
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
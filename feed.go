@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	feedTimeout  = 30 * time.Second
+	feedMaxBytes = 16 << 20
+)
+
+var feedClient = &http.Client{Timeout: feedTimeout}
+
+// feedItem is one RSS item or Atom entry. URL is the enclosure when there is
+// one (podcast episodes), otherwise the entry's link.
+type feedItem struct {
+	GUID  string
+	Title string
+	URL   string
+}
+
+// feedDocument decodes RSS 2.0, RSS 1.0 (items at the root) and Atom.
+type feedDocument struct {
+	Title   string `xml:"title"`
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items   []rssItem   `xml:"item"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title     string `xml:"title"`
+	Link      string `xml:"link"`
+	GUID      string `xml:"guid"`
+	Enclosure struct {
+		URL string `xml:"url,attr"`
+	} `xml:"enclosure"`
+}
+
+type atomEntry struct {
+	Title string `xml:"title"`
+	ID    string `xml:"id"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+}
+
+// AddFeedSubscription starts watching an RSS or Atom feed, such as a podcast.
+// New items are queued by their enclosure URL, or their link when they have
+// none. interval is in minutes; an empty profileID uses the active profile
+// when tasks run.
+func (a *App) AddFeedSubscription(url string, interval int, profileID string) (Subscription, error) {
+	url = strings.TrimSpace(url)
+	if err := a.validateSubscription(url, interval, profileID); err != nil {
+		return Subscription{}, err
+	}
+	if !isHTTPURL(url) {
+		return Subscription{}, errors.New("feed url must start with http:// or https://")
+	}
+	title, items, err := fetchFeed(url)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	now := time.Now()
+	subscription := Subscription{
+		ID:              newID(),
+		Kind:            subscriptionKindFeed,
+		URL:             url,
+		Title:           title,
+		IntervalMinutes: interval,
+		ProfileID:       profileID,
+		Seen:            []string{},
+		LastCheckedAt:   now,
+		CreatedAt:       now,
+	}
+	if subscription.Title == "" {
+		subscription.Title = defaultTitleFromURL(url)
+	}
+	for _, item := range items {
+		subscription.Seen = append(subscription.Seen, item.GUID)
+	}
+	return a.addSubscription(subscription)
+}
+
+// fetchFeed downloads and parses a feed, returning its title and items in
+// feed order.
+func fetchFeed(feedURL string) (string, []feedItem, error) {
+	req, err := http.NewRequest(http.MethodGet, feedURL, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; FetchForge)")
+	resp, err := feedClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, errors.New(resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, feedMaxBytes))
+	if err != nil {
+		return "", nil, err
+	}
+	return parseFeed(data)
+}
+
+func parseFeed(data []byte) (string, []feedItem, error) {
+	var doc feedDocument
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = feedCharsetReader
+	if err := decoder.Decode(&doc); err != nil {
+		return "", nil, errors.New("not an RSS or Atom feed")
+	}
+	title := strings.TrimSpace(doc.Channel.Title)
+	if title == "" {
+		title = strings.TrimSpace(doc.Title)
+	}
+	var items []feedItem
+	for _, item := range append(doc.Channel.Items, doc.Items...) {
+		entry := feedItem{Title: strings.TrimSpace(item.Title), URL: strings.TrimSpace(item.Enclosure.URL)}
+		if entry.URL == "" {
+			entry.URL = strings.TrimSpace(item.Link)
+		}
+		entry.GUID = strings.TrimSpace(item.GUID)
+		items = appendFeedItem(items, entry)
+	}
+	for _, item := range doc.Entries {
+		entry := feedItem{GUID: strings.TrimSpace(item.ID), Title: strings.TrimSpace(item.Title)}
+		for _, link := range item.Links {
+			switch {
+			case link.Rel == "enclosure":
+				entry.URL = strings.TrimSpace(link.Href)
+			case (link.Rel == "" || link.Rel == "alternate") && entry.URL == "":
+				entry.URL = strings.TrimSpace(link.Href)
+			}
+		}
+		items = appendFeedItem(items, entry)
+	}
+	if title == "" && len(items) == 0 {
+		return "", nil, errors.New("not an RSS or Atom feed")
+	}
+	return title, items, nil
+}
+
+// appendFeedItem keeps items with a usable URL, using the URL as the GUID
+// when the feed has none.
+func appendFeedItem(items []feedItem, item feedItem) []feedItem {
+	if !isHTTPURL(item.URL) {
+		return items
+	}
+	if item.GUID == "" {
+		item.GUID = item.URL
+	}
+	return append(items, item)
+}
+
+// feedCharsetReader handles the Latin-1 family besides UTF-8, which covers
+// nearly every feed that is not UTF-8.
+func feedCharsetReader(label string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(label) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "latin-1", "windows-1252", "cp1252":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, 0, len(data))
+		for _, b := range data {
+			out = utf8.AppendRune(out, rune(b))
+		}
+		return bytes.NewReader(out), nil
+	}
+	return nil, errors.New("unsupported feed encoding " + label)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ffmpegReleaseURL points at static single-file ffmpeg builds published as
+// gzipped release assets.
+const ffmpegReleaseURL = "https://api.github.com/repos/eugeneware/ffmpeg-static/releases/latest"
+
+// DependencyStatus reports one external tool FetchForge relies on. Managed is
+// true when the tool was installed into ~/.fetchforge/bin by FetchForge.
+type DependencyStatus struct {
+	Name     string `json:"name"`
+	Found    bool   `json:"found"`
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Managed  bool   `json:"managed"`
+	Required bool   `json:"required"`
+}
+
+// GetDependencyStatus reports whether yt-dlp and ffmpeg are installed and
+// which versions are in use.
+func (a *App) GetDependencyStatus() ([]DependencyStatus, error) {
+	ytDlp := DependencyStatus{Name: "yt-dlp", Required: true}
+	info := a.ytDlpInstallInfo()
+	ytDlp.Path = info.Path
+	ytDlp.Version = info.CurrentVersion
+	ytDlp.Managed = info.Managed
+	ytDlp.Found = info.Path != "" && info.CurrentVersion != ""
+
+	ffmpeg := DependencyStatus{Name: "ffmpeg"}
+	ffmpeg.Path = a.ffmpegBinary()
+	if ffmpeg.Path != "" {
+		ffmpeg.Version, _ = commandVersion(ffmpeg.Path, "-version")
+		ffmpeg.Found = ffmpeg.Version != ""
+		if managed, err := managedToolPath("ffmpeg"); err == nil {
+			ffmpeg.Managed = filepath.Clean(ffmpeg.Path) == managed
+		}
+	}
+	return []DependencyStatus{ytDlp, ffmpeg}, nil
+}
+
+// InstallFfmpeg downloads a static ffmpeg build for this platform into
+// ~/.fetchforge/bin and switches to it.
+func (a *App) InstallFfmpeg() (DependencyStatus, error) {
+	toolInstallMu.Lock()
+	defer toolInstallMu.Unlock()
+
+	asset := ffmpegAssetName(runtime.GOOS, runtime.GOARCH)
+	if asset == "" {
+		return DependencyStatus{}, fmt.Errorf("no ffmpeg build for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+	release, err := fetchGitHubRelease(ffmpegReleaseURL)
+	if err != nil {
+		return DependencyStatus{}, err
+	}
+	var binaryURL, expected string
+	for _, candidate := range release.Assets {
+		if candidate.Name == asset {
+			binaryURL = candidate.URL
+			expected = strings.ToLower(strings.TrimPrefix(candidate.Digest, "sha256:"))
+			break
+		}
+	}
+	if binaryURL == "" {
+		return DependencyStatus{}, fmt.Errorf("release %s has no %s", release.TagName, asset)
+	}
+	if expected == "" {
+		return DependencyStatus{}, fmt.Errorf("release %s publishes no checksum for %s", release.TagName, asset)
+	}
+	target, err := managedToolPath("ffmpeg")
+	if err != nil {
+		return DependencyStatus{}, err
+	}
+	if err := installReleaseBinary("ffmpeg", binaryURL, expected, target, true); err != nil {
+		return DependencyStatus{}, err
+	}
+
+	a.mu.Lock()
+	a.ffmpegPath = target
+	a.mu.Unlock()
+	statuses, _ := a.GetDependencyStatus()
+	for _, status := range statuses {
+		if status.Name == "ffmpeg" {
+			if !status.Found {
+				return status, errors.New("installed ffmpeg could not be run")
+			}
+			return status, nil
+		}
+	}
+	return DependencyStatus{}, errors.New("ffmpeg status unavailable")
+}
+
+func (a *App) ffmpegBinary() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.ffmpegPath
+}
+
+// ffmpegCommand builds an ffmpeg command using the resolved binary.
+func (a *App) ffmpegCommand(args ...string) *exec.Cmd {
+	path := a.ffmpegBinary()
+	if path == "" {
+		path = "ffmpeg"
+	}
+	return exec.Command(path, args...)
+}
+
+// resolveFfmpegPath finds ffmpeg the same way resolveYtDlpPath finds yt-dlp:
+// the FETCHFORGE_FFMPEG_PATH override, the managed copy, PATH, then common
+// install and bundle locations.
+func resolveFfmpegPath() string {
+	if envPath := strings.TrimSpace(os.Getenv("FETCHFORGE_FFMPEG_PATH")); envPath != "" {
+		if fileExists(envPath) {
+			return envPath
+		}
+	}
+	if managed, err := managedToolPath("ffmpeg"); err == nil && fileExists(managed) {
+		return managed
+	}
+	if path, err := exec.LookPath("ffmpeg"); err == nil {
+		return path
+	}
+	candidates := []string{
+		"/opt/homebrew/bin/ffmpeg",
+		"/usr/local/bin/ffmpeg",
+		"/usr/bin/ffmpeg",
+	}
+	if exe, err := os.Executable(); err == nil {
+		exeDir := filepath.Dir(exe)
+		candidates = append(candidates,
+			filepath.Join(exeDir, "ffmpeg"),
+			filepath.Join(exeDir, "..", "Resources", "ffmpeg"),
+		)
+	}
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// managedToolPath is where FetchForge installs its own copy of a tool.
+func managedToolPath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(home, ".fetchforge", "bin", name), nil
+}
+
+func ffmpegAssetName(goos, goarch string) string {
+	arch := map[string]string{"amd64": "x64", "arm64": "arm64", "386": "ia32", "arm": "arm"}[goarch]
+	if arch == "" {
+		return ""
+	}
+	switch goos {
+	case "darwin":
+		if arch != "x64" && arch != "arm64" {
+			return ""
+		}
+		return "ffmpeg-darwin-" + arch + ".gz"
+	case "linux":
+		return "ffmpeg-linux-" + arch + ".gz"
+	case "windows":
+		if arch != "x64" && arch != "ia32" {
+			return ""
+		}
+		return "ffmpeg-win32-" + arch + ".gz"
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Filename modes control how file names are made safe. The default keeps
+// yt-dlp's behaviour; "windows" also avoids names Windows and SMB shares
+// reject, and "restrict" limits names to ASCII without spaces.
+const (
+	filenameModeDefault  = ""
+	filenameModeWindows  = "windows"
+	filenameModeRestrict = "restrict"
+)
+
+// GetFilenameMode returns the file name safety mode: "", "windows" or
+// "restrict".
+func (a *App) GetFilenameMode() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.filenameMode, nil
+}
+
+// SetFilenameMode chooses how file names are sanitized. "windows" maps to
+// yt-dlp's --windows-filenames and "restrict" to --restrict-filenames; both
+// also clean the literal text of the filename template and the names picked
+// by gallery-dl and the direct engine.
+func (a *App) SetFilenameMode(mode string) error {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if !validFilenameMode(mode) {
+		return errors.New("filename mode must be empty, windows or restrict")
+	}
+	a.mu.Lock()
+	a.filenameMode = mode
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+func (a *App) currentFilenameMode() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.filenameMode
+}
+
+func validFilenameMode(mode string) bool {
+	return mode == filenameModeDefault || mode == filenameModeWindows || mode == filenameModeRestrict
+}
+
+func filenameModeArgs(mode string) []string {
+	switch mode {
+	case filenameModeWindows:
+		return []string{"--windows-filenames"}
+	case filenameModeRestrict:
+		return []string{"--restrict-filenames"}
+	}
+	return nil
+}
+
+func galleryDlFilenameArgs(mode string) []string {
+	switch mode {
+	case filenameModeWindows:
+		return []string{"-o", "path-restrict=windows"}
+	case filenameModeRestrict:
+		return []string{"-o", "path-restrict=ascii"}
+	}
+	return nil
+}
+
+// sanitizeTemplateLiterals cleans the literal text of a yt-dlp output
+// template, such as a folder name typed into an organize rule, leaving
+// %(field)s references and %% escapes for yt-dlp to fill in. Path separators
+// are kept so templates can still create subfolders.
+func sanitizeTemplateLiterals(template, mode string) string {
+	if mode == filenameModeDefault {
+		return template
+	}
+	var b strings.Builder
+	for i := 0; i < len(template); {
+		if template[i] == '%' {
+			if strings.HasPrefix(template[i:], "%%") {
+				b.WriteString("%%")
+				i += 2
+				continue
+			}
+			if field := templateFieldPattern.FindString(template[i:]); field != "" {
+				b.WriteString(field)
+				i += len(field)
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(template[i:])
+		b.WriteString(safeFilenameRune(r, mode))
+		i += size
+	}
+	return b.String()
+}
+
+// safeFilename applies the filename mode to a name chosen by the app.
+func safeFilename(name, mode string) string {
+	name = sanitizeFilename(name)
+	if mode != filenameModeRestrict {
+		return name
+	}
+	var b strings.Builder
+	for _, r := range name {
+		b.WriteString(safeFilenameRune(r, mode))
+	}
+	return strings.Trim(b.String(), "._")
+}
+
+func safeFilenameRune(r rune, mode string) string {
+	switch {
+	case r == '/' || r == '\\':
+		return string(r)
+	case r < 0x20 || r == 0x7f || strings.ContainsRune(`:*?"<>|`, r):
+		return "_"
+	case mode == filenameModeRestrict && (r > 0x7e || r == ' ' || strings.ContainsRune("&'!#$;`", r)):
+		return "_"
+	}
+	return string(r)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FormatOption is one downloadable format reported by yt-dlp. ID is the
+// format_id (the itag on YouTube) that can be passed to SetTaskFormat.
+type FormatOption struct {
+	ID         string  `json:"id"`
+	Ext        string  `json:"ext"`
+	Resolution string  `json:"resolution"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	FPS        float64 `json:"fps"`
+	VCodec     string  `json:"vcodec"`
+	ACodec     string  `json:"acodec"`
+	Filesize   int64   `json:"filesize"`
+	TBR        float64 `json:"tbr"`
+	Note       string  `json:"note"`
+}
+
+type analyzedFormat struct {
+	probeFormat
+	FormatID   string   `json:"format_id"`
+	Ext        string   `json:"ext"`
+	FPS        *float64 `json:"fps"`
+	FormatNote string   `json:"format_note"`
+}
+
+// formatSelectorPattern accepts format ids and simple yt-dlp selectors such
+// as "137+140" or "bv*[height<=720]+ba/b".
+var formatSelectorPattern = regexp.MustCompile(`^[A-Za-z0-9_.*+/\-\[\]<>=!?:^$~]+$`)
+
+// AnalyzeFormats lists every format yt-dlp offers for a single video.
+func (a *App) AnalyzeFormats(url string) ([]FormatOption, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return nil, errors.New("url is required")
+	}
+	args := []string{"--skip-download", "--no-warnings", "--no-playlist", "-J"}
+	args = append(args, extraYtDlpArgs()...)
+	args = append(args, a.cookieArgs()...)
+	args = append(args, a.proxyArgs("")...)
+	args = append(args, url)
+	cmd := a.ytDlpCommand(args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.New(formatCommandError(err, cmd, stdout.String(), stderr.String()))
+	}
+	var info struct {
+		Formats []analyzedFormat `json:"formats"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		return nil, errors.New("could not read format list")
+	}
+
+	out := make([]FormatOption, 0, len(info.Formats))
+	for _, format := range info.Formats {
+		option := FormatOption{
+			ID:         format.FormatID,
+			Ext:        format.Ext,
+			Resolution: format.Resolution,
+			Width:      floatToInt(format.Width),
+			Height:     floatToInt(format.Height),
+			VCodec:     format.VCodec,
+			ACodec:     format.ACodec,
+			Filesize:   pickFilesize(format.Filesize, format.FilesizeApprox),
+			Note:       format.FormatNote,
+		}
+		if format.FPS != nil {
+			option.FPS = *format.FPS
+		}
+		if format.TBR != nil {
+			option.TBR = *format.TBR
+		}
+		out = append(out, option)
+	}
+	return out, nil
+}
+
+// validFormatID reports whether formatID is empty or a yt-dlp format
+// selector that cannot be mistaken for an option.
+func validFormatID(formatID string) bool {
+	return formatID == "" || (!strings.HasPrefix(formatID, "-") && formatSelectorPattern.MatchString(formatID))
+}
+
+// SetTaskFormat picks the yt-dlp format for a task that has not started
+// downloading. Empty input goes back to the profile's format choice.
+func (a *App) SetTaskFormat(id string, formatID string) (Task, error) {
+	formatID = strings.TrimSpace(formatID)
+	if !validFormatID(formatID) {
+		return Task{}, errors.New("invalid format id")
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.Status == statusRunning || task.Status == statusPaused || task.Status == statusSuccess {
+		a.mu.Unlock()
+		return Task{}, errors.New("task has already started downloading")
+	}
+	task.FormatID = formatID
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
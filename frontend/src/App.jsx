@@ -306,10 +306,19 @@ function App() {
             });
         };
 
+        const removedHandler = (ids) => {
+            const removed = new Set(ids || []);
+            setTasks((prev) => prev.filter((item) => !removed.has(item.id)));
+        };
+
         EventsOn("task:update", handler);
+        EventsOn("tasks:removed", removedHandler);
+        EventsOn("tasks:deleted", removedHandler);
         return () => {
             mounted = false;
             EventsOff("task:update", handler);
+            EventsOff("tasks:removed", removedHandler);
+            EventsOff("tasks:deleted", removedHandler);
         };
     }, []);
 
@@ -331,7 +340,7 @@ function App() {
         const textToSubmit = inputText;
         setInputText('');
         try {
-            const created = await CreateTasksFromText(textToSubmit);
+            const created = await CreateTasksFromText(textToSubmit, "");
             if (created && created.length) {
                 setTasks((prev) => {
                     const known = new Set(prev.map((task) => task.id));
@@ -1,39 +1,394 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
+import {time} from '../models';
 
-export function CreateTasksFromText(arg1:string):Promise<Array<main.Task>>;
+export function AddFeedSubscription(arg1:string,arg2:number,arg3:string):Promise<main.Subscription>;
+
+export function AddSubscription(arg1:string,arg2:number,arg3:string):Promise<main.Subscription>;
+
+export function AdoptOutputFile(arg1:string):Promise<main.Task>;
+
+export function AnalyzeFormats(arg1:string):Promise<Array<main.FormatOption>>;
+
+export function CancelTask(arg1:string):Promise<void>;
+
+export function CheckForYtDlpUpdate():Promise<main.YtDlpUpdateInfo>;
+
+export function CheckURLSupport(arg1:Array<string>):Promise<Array<main.URLSupport>>;
+
+export function CleanPartialFiles(arg1:Array<string>):Promise<main.PartialCleanupReport>;
+
+export function ClearDownloadArchive():Promise<void>;
+
+export function ClearHostFallback(arg1:string):Promise<void>;
+
+export function ClearSchedule(arg1:string):Promise<main.Task>;
+
+export function ConfirmAll():Promise<void>;
+
+export function ConfirmTask(arg1:string):Promise<void>;
+
+export function CreatePlaylistTasks(arg1:string,arg2:string):Promise<Array<main.Task>>;
+
+export function CreateProfile(arg1:main.Profile):Promise<main.Profile>;
+
+export function CreateTaskWithOptions(arg1:main.TaskOptions):Promise<main.Task>;
+
+export function CreateTasksFromTemplate(arg1:string,arg2:string):Promise<Array<main.Task>>;
+
+export function CreateTasksFromText(arg1:string,arg2:string):Promise<Array<main.Task>>;
+
+export function DeleteHostOverride(arg1:string):Promise<void>;
+
+export function DeletePipeline(arg1:string):Promise<void>;
+
+export function DeleteProfile(arg1:string):Promise<void>;
+
+export function DeleteRecurrence(arg1:string):Promise<void>;
+
+export function DeleteSiteCredentials(arg1:string):Promise<void>;
 
 export function DeleteTask(arg1:string):Promise<void>;
 
+export function DeleteTaskTemplate(arg1:string):Promise<void>;
+
+export function DeleteTasks(arg1:Array<string>):Promise<main.BatchResult>;
+
+export function DeleteUnreferencedFile(arg1:string):Promise<void>;
+
+export function DownloadDuplicate(arg1:string):Promise<void>;
+
+export function DuplicateProfile(arg1:string):Promise<main.Profile>;
+
+export function ExpandPlaylist(arg1:string):Promise<Array<main.PreviewEntry>>;
+
+export function ExportSettings():Promise<string>;
+
 export function ExportTasks():Promise<string>;
 
+export function ExportTasksCSV():Promise<string>;
+
+export function ExportTasksCSVToFile():Promise<string>;
+
 export function ExportTasksToFile():Promise<string>;
 
+export function ExtractLinks(arg1:string,arg2:boolean):Promise<Array<string>>;
+
+export function FindDuplicateFiles():Promise<Array<main.DuplicateGroup>>;
+
 export function ForceResumeTask(arg1:string):Promise<void>;
 
 export function GetActiveProfile():Promise<main.Profile>;
 
+export function GetAria2Settings():Promise<main.Aria2Settings>;
+
+export function GetAutoResumeOnLaunch():Promise<boolean>;
+
+export function GetBandwidthLimit():Promise<string>;
+
+export function GetBandwidthRules():Promise<Array<main.BandwidthRule>>;
+
+export function GetCleanupPolicy():Promise<main.CleanupPolicy>;
+
+export function GetClipboardWatch():Promise<main.ClipboardWatchSettings>;
+
+export function GetCollisionPolicy():Promise<string>;
+
+export function GetCookieSettings():Promise<main.CookieSettings>;
+
+export function GetCurrentBandwidthLimit():Promise<string>;
+
+export function GetDependencyStatus():Promise<Array<main.DependencyStatus>>;
+
+export function GetDownloadDirectory():Promise<string>;
+
+export function GetFilenameMode():Promise<string>;
+
+export function GetFilenameTemplate():Promise<string>;
+
+export function GetGalleryDlRules():Promise<Array<string>>;
+
+export function GetHostCooldowns():Promise<Array<main.HostCooldown>>;
+
+export function GetLibraryDirectory():Promise<string>;
+
+export function GetMQTTConfig():Promise<main.MQTTConfig>;
+
+export function GetMaxConcurrency():Promise<number>;
+
+export function GetMaxHeight():Promise<number>;
+
+export function GetMaxPerHost():Promise<number>;
+
+export function GetMediaPreferences():Promise<main.MediaPreferences>;
+
+export function GetOrganizeRules():Promise<Array<main.OrganizeRule>>;
+
+export function GetPermanentDeleteFallback():Promise<boolean>;
+
+export function GetPostDownloadHook():Promise<Array<string>>;
+
+export function GetProxy():Promise<string>;
+
+export function GetQueueSummary():Promise<main.QueueSummary>;
+
+export function GetRateLimitCooldown():Promise<number>;
+
+export function GetRemoteConfig():Promise<main.RemoteConfig>;
+
+export function GetRemoteStatus():Promise<main.RemoteStatus>;
+
+export function GetRestartOnBandwidthChange():Promise<boolean>;
+
+export function GetRetentionPolicy():Promise<main.RetentionPolicy>;
+
+export function GetRetryPolicy():Promise<main.RetryPolicy>;
+
+export function GetSizeConfirmThreshold():Promise<number>;
+
+export function GetStageStats():Promise<Array<main.StageStat>>;
+
+export function GetStatistics():Promise<main.DownloadStatistics>;
+
 export function GetTaskFileStatus(arg1:string):Promise<string>;
 
+export function GetTaskLog(arg1:string,arg2:number):Promise<string>;
+
 export function GetTaskResumeStatus(arg1:string):Promise<string>;
 
+export function GetTaskTimeline(arg1:string):Promise<Array<main.StageSpan>>;
+
 export function GetUseBrowserCookies():Promise<boolean>;
 
+export function GetWatchDirectory():Promise<string>;
+
+export function GetYtDlpVersion():Promise<string>;
+
+export function IgnoreClipboardURL(arg1:string):Promise<void>;
+
+export function ImportFromBookmarks(arg1:string,arg2:string):Promise<main.ImportResult>;
+
+export function ImportSettings(arg1:string):Promise<void>;
+
 export function ImportTasks(arg1:string,arg2:string,arg3:boolean):Promise<Array<main.Task>>;
 
+export function ImportURLsFromFile(arg1:string):Promise<main.ImportResult>;
+
+export function InstallFfmpeg():Promise<main.DependencyStatus>;
+
+export function ListArchiveEntries():Promise<Array<main.ArchiveEntry>>;
+
+export function ListBatches():Promise<Array<main.BatchSummary>>;
+
+export function ListBookmarkFolders(arg1:string):Promise<Array<string>>;
+
+export function ListDownloadEngines():Promise<Array<main.DownloadEngine>>;
+
+export function ListHistory(arg1:number,arg2:main.HistoryFilter):Promise<main.HistoryPage>;
+
+export function ListHostFallbacks():Promise<Array<main.HostFallback>>;
+
+export function ListHostOverrides():Promise<Array<main.HostOverride>>;
+
+export function ListPipelines():Promise<Array<main.Pipeline>>;
+
 export function ListProfiles():Promise<Array<main.Profile>>;
 
+export function ListRecurrences():Promise<Array<main.Recurrence>>;
+
+export function ListSiteCredentials():Promise<Array<main.SiteCredential>>;
+
+export function ListSnapshots():Promise<Array<main.Snapshot>>;
+
+export function ListSubscriptions():Promise<Array<main.Subscription>>;
+
+export function ListTaskCodes():Promise<main.TaskCodes>;
+
+export function ListTaskOutputs(arg1:string):Promise<Array<main.TaskOutput>>;
+
+export function ListTaskTemplates():Promise<Array<main.TaskTemplate>>;
+
 export function ListTasks():Promise<Array<main.Task>>;
 
+export function ListTranscodePresets():Promise<Array<main.TranscodePreset>>;
+
+export function MoveTaskOutput(arg1:string,arg2:string):Promise<main.Task>;
+
 export function OpenPath(arg1:string):Promise<void>;
 
 export function OpenTaskFile(arg1:string):Promise<void>;
 
 export function OpenTaskFolder(arg1:string):Promise<void>;
 
+export function OpenTaskOutput(arg1:string,arg2:string):Promise<void>;
+
+export function PauseAll():Promise<void>;
+
+export function PauseQueue():Promise<void>;
+
+export function PauseTask(arg1:string):Promise<void>;
+
+export function PickDirectory(arg1:string):Promise<string>;
+
+export function PickFile(arg1:string,arg2:Array<main.FileFilter>):Promise<string>;
+
+export function PickOutputDirectory():Promise<string>;
+
+export function PreviewCommand(arg1:string,arg2:string,arg3:main.TaskOptions):Promise<main.CommandPreview>;
+
+export function PreviewOrganizeRules(arg1:Array<main.OrganizeRule>):Promise<Array<main.OrganizePreview>>;
+
+export function PreviewTask(arg1:string):Promise<main.TaskPreview>;
+
+export function QueryTasks(arg1:main.TaskFilter):Promise<main.TaskQueryResult>;
+
+export function ReconcileOutputs():Promise<main.ReconcileReport>;
+
+export function RecurrenceHistory(arg1:string):Promise<Array<main.Task>>;
+
+export function RefreshMetadata(arg1:string):Promise<main.Task>;
+
+export function RelinkMissingOutput(arg1:string,arg2:string,arg3:string):Promise<main.Task>;
+
+export function RelinkTaskOutput(arg1:string,arg2:string):Promise<main.Task>;
+
+export function RemoveArchiveEntries(arg1:Array<main.ArchiveEntry>):Promise<void>;
+
+export function RemoveSubscription(arg1:string):Promise<void>;
+
+export function RemoveTaskEntries(arg1:Array<string>):Promise<main.BatchResult>;
+
+export function RemoveTaskEntry(arg1:string):Promise<void>;
+
+export function RenameTask(arg1:string,arg2:string,arg3:boolean):Promise<main.Task>;
+
+export function RenameTaskOutput(arg1:string,arg2:string):Promise<main.Task>;
+
+export function RestoreSnapshot(arg1:string):Promise<Array<main.Task>>;
+
+export function ResumeAll():Promise<void>;
+
+export function ResumeQueue():Promise<void>;
+
 export function ResumeTask(arg1:string):Promise<void>;
 
+export function ResumeTasks(arg1:Array<string>):Promise<main.BatchResult>;
+
+export function RetryTasks(arg1:Array<string>):Promise<main.BatchResult>;
+
+export function RevealInFileManager(arg1:string):Promise<void>;
+
+export function RunCleanupNow(arg1:boolean):Promise<main.CleanupReport>;
+
+export function RunDiagnostics():Promise<main.DiagnosticsReport>;
+
+export function RunPipeline(arg1:string,arg2:string):Promise<void>;
+
+export function SaveHostOverride(arg1:main.HostOverride):Promise<main.HostOverride>;
+
+export function SavePipeline(arg1:main.Pipeline):Promise<main.Pipeline>;
+
+export function SaveRecurrence(arg1:main.Recurrence):Promise<main.Recurrence>;
+
+export function SaveTaskTemplate(arg1:main.TaskTemplate):Promise<main.TaskTemplate>;
+
+export function ScanPartialFiles():Promise<Array<main.PartialFile>>;
+
+export function ScheduleTask(arg1:string,arg2:time.Time):Promise<main.Task>;
+
 export function SetActiveProfile(arg1:string):Promise<void>;
 
+export function SetAria2Settings(arg1:main.Aria2Settings):Promise<void>;
+
+export function SetAutoResumeOnLaunch(arg1:boolean):Promise<void>;
+
+export function SetBandwidthLimit(arg1:string):Promise<void>;
+
+export function SetBandwidthRules(arg1:Array<main.BandwidthRule>):Promise<void>;
+
+export function SetCleanupPolicy(arg1:main.CleanupPolicy):Promise<void>;
+
+export function SetClipboardWatch(arg1:main.ClipboardWatchSettings):Promise<void>;
+
+export function SetCollisionPolicy(arg1:string):Promise<void>;
+
+export function SetCookieSettings(arg1:main.CookieSettings):Promise<void>;
+
+export function SetDownloadDirectory(arg1:string):Promise<string>;
+
+export function SetFilenameMode(arg1:string):Promise<void>;
+
+export function SetFilenameTemplate(arg1:string):Promise<void>;
+
+export function SetGalleryDlRules(arg1:Array<string>):Promise<void>;
+
+export function SetLibraryDirectory(arg1:string):Promise<string>;
+
+export function SetMQTTConfig(arg1:main.MQTTConfig):Promise<void>;
+
+export function SetMaxConcurrency(arg1:number):Promise<void>;
+
+export function SetMaxHeight(arg1:number):Promise<void>;
+
+export function SetMaxPerHost(arg1:number):Promise<void>;
+
+export function SetMediaPreferences(arg1:main.MediaPreferences):Promise<main.MediaPreferences>;
+
+export function SetOrganizeRules(arg1:Array<main.OrganizeRule>):Promise<Array<main.OrganizeRule>>;
+
+export function SetPermanentDeleteFallback(arg1:boolean):Promise<void>;
+
+export function SetPostDownloadHook(arg1:Array<string>):Promise<void>;
+
+export function SetProxy(arg1:string):Promise<void>;
+
+export function SetRateLimitCooldown(arg1:number):Promise<void>;
+
+export function SetRemoteConfig(arg1:main.RemoteConfig):Promise<main.RemoteConfig>;
+
+export function SetRestartOnBandwidthChange(arg1:boolean):Promise<void>;
+
+export function SetRetentionPolicy(arg1:main.RetentionPolicy):Promise<void>;
+
+export function SetRetryPolicy(arg1:main.RetryPolicy):Promise<void>;
+
+export function SetSiteCredentials(arg1:string,arg2:string,arg3:string):Promise<main.SiteCredential>;
+
+export function SetSizeConfirmThreshold(arg1:number):Promise<void>;
+
+export function SetTaskEngine(arg1:string,arg2:string):Promise<main.Task>;
+
+export function SetTaskExtraArgs(arg1:string,arg2:Array<string>):Promise<main.Task>;
+
+export function SetTaskFormat(arg1:string,arg2:string):Promise<main.Task>;
+
+export function SetTaskNotes(arg1:string,arg2:string):Promise<void>;
+
+export function SetTaskOutputDir(arg1:string,arg2:string):Promise<main.Task>;
+
+export function SetTaskProxy(arg1:string,arg2:string):Promise<main.Task>;
+
+export function SetTaskSubtitles(arg1:string,arg2:main.SubtitleOptions):Promise<main.Task>;
+
+export function SetTaskTags(arg1:string,arg2:Array<string>):Promise<main.Task>;
+
 export function SetUseBrowserCookies(arg1:boolean):Promise<void>;
+
+export function SetWatchDirectory(arg1:string):Promise<string>;
+
+export function TranscodeTask(arg1:string,arg2:string):Promise<void>;
+
+export function UndoDelete(arg1:string):Promise<main.Task>;
+
+export function UnpauseTask(arg1:string):Promise<void>;
+
+export function UpdateProfile(arg1:main.Profile):Promise<main.Profile>;
+
+export function UpdateTaskDetails(arg1:string,arg2:string,arg3:string):Promise<main.Task>;
+
+export function UpdateYtDlp():Promise<main.YtDlpUpdateInfo>;
+
+export function VerifyAllTasks():Promise<Array<main.VerifyResult>>;
+
+export function VerifyTask(arg1:string):Promise<main.VerifyResult>;
@@ -2,22 +2,154 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function CreateTasksFromText(arg1) {
-  return window['go']['main']['App']['CreateTasksFromText'](arg1);
+export function AddFeedSubscription(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddFeedSubscription'](arg1, arg2, arg3);
+}
+
+export function AddSubscription(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddSubscription'](arg1, arg2, arg3);
+}
+
+export function AdoptOutputFile(arg1) {
+  return window['go']['main']['App']['AdoptOutputFile'](arg1);
+}
+
+export function AnalyzeFormats(arg1) {
+  return window['go']['main']['App']['AnalyzeFormats'](arg1);
+}
+
+export function CancelTask(arg1) {
+  return window['go']['main']['App']['CancelTask'](arg1);
+}
+
+export function CheckForYtDlpUpdate() {
+  return window['go']['main']['App']['CheckForYtDlpUpdate']();
+}
+
+export function CheckURLSupport(arg1) {
+  return window['go']['main']['App']['CheckURLSupport'](arg1);
+}
+
+export function CleanPartialFiles(arg1) {
+  return window['go']['main']['App']['CleanPartialFiles'](arg1);
+}
+
+export function ClearDownloadArchive() {
+  return window['go']['main']['App']['ClearDownloadArchive']();
+}
+
+export function ClearHostFallback(arg1) {
+  return window['go']['main']['App']['ClearHostFallback'](arg1);
+}
+
+export function ClearSchedule(arg1) {
+  return window['go']['main']['App']['ClearSchedule'](arg1);
+}
+
+export function ConfirmAll() {
+  return window['go']['main']['App']['ConfirmAll']();
+}
+
+export function ConfirmTask(arg1) {
+  return window['go']['main']['App']['ConfirmTask'](arg1);
+}
+
+export function CreatePlaylistTasks(arg1, arg2) {
+  return window['go']['main']['App']['CreatePlaylistTasks'](arg1, arg2);
+}
+
+export function CreateProfile(arg1) {
+  return window['go']['main']['App']['CreateProfile'](arg1);
+}
+
+export function CreateTaskWithOptions(arg1) {
+  return window['go']['main']['App']['CreateTaskWithOptions'](arg1);
+}
+
+export function CreateTasksFromTemplate(arg1, arg2) {
+  return window['go']['main']['App']['CreateTasksFromTemplate'](arg1, arg2);
+}
+
+export function CreateTasksFromText(arg1, arg2) {
+  return window['go']['main']['App']['CreateTasksFromText'](arg1, arg2);
+}
+
+export function DeleteHostOverride(arg1) {
+  return window['go']['main']['App']['DeleteHostOverride'](arg1);
+}
+
+export function DeletePipeline(arg1) {
+  return window['go']['main']['App']['DeletePipeline'](arg1);
+}
+
+export function DeleteProfile(arg1) {
+  return window['go']['main']['App']['DeleteProfile'](arg1);
+}
+
+export function DeleteRecurrence(arg1) {
+  return window['go']['main']['App']['DeleteRecurrence'](arg1);
+}
+
+export function DeleteSiteCredentials(arg1) {
+  return window['go']['main']['App']['DeleteSiteCredentials'](arg1);
 }
 
 export function DeleteTask(arg1) {
   return window['go']['main']['App']['DeleteTask'](arg1);
 }
 
+export function DeleteTaskTemplate(arg1) {
+  return window['go']['main']['App']['DeleteTaskTemplate'](arg1);
+}
+
+export function DeleteTasks(arg1) {
+  return window['go']['main']['App']['DeleteTasks'](arg1);
+}
+
+export function DeleteUnreferencedFile(arg1) {
+  return window['go']['main']['App']['DeleteUnreferencedFile'](arg1);
+}
+
+export function DownloadDuplicate(arg1) {
+  return window['go']['main']['App']['DownloadDuplicate'](arg1);
+}
+
+export function DuplicateProfile(arg1) {
+  return window['go']['main']['App']['DuplicateProfile'](arg1);
+}
+
+export function ExpandPlaylist(arg1) {
+  return window['go']['main']['App']['ExpandPlaylist'](arg1);
+}
+
+export function ExportSettings() {
+  return window['go']['main']['App']['ExportSettings']();
+}
+
 export function ExportTasks() {
   return window['go']['main']['App']['ExportTasks']();
 }
 
+export function ExportTasksCSV() {
+  return window['go']['main']['App']['ExportTasksCSV']();
+}
+
+export function ExportTasksCSVToFile() {
+  return window['go']['main']['App']['ExportTasksCSVToFile']();
+}
+
 export function ExportTasksToFile() {
   return window['go']['main']['App']['ExportTasksToFile']();
 }
 
+export function ExtractLinks(arg1, arg2) {
+  return window['go']['main']['App']['ExtractLinks'](arg1, arg2);
+}
+
+export function FindDuplicateFiles() {
+  return window['go']['main']['App']['FindDuplicateFiles']();
+}
+
 export function ForceResumeTask(arg1) {
   return window['go']['main']['App']['ForceResumeTask'](arg1);
 }
@@ -26,30 +158,274 @@ export function GetActiveProfile() {
   return window['go']['main']['App']['GetActiveProfile']();
 }
 
+export function GetAria2Settings() {
+  return window['go']['main']['App']['GetAria2Settings']();
+}
+
+export function GetAutoResumeOnLaunch() {
+  return window['go']['main']['App']['GetAutoResumeOnLaunch']();
+}
+
+export function GetBandwidthLimit() {
+  return window['go']['main']['App']['GetBandwidthLimit']();
+}
+
+export function GetBandwidthRules() {
+  return window['go']['main']['App']['GetBandwidthRules']();
+}
+
+export function GetCleanupPolicy() {
+  return window['go']['main']['App']['GetCleanupPolicy']();
+}
+
+export function GetClipboardWatch() {
+  return window['go']['main']['App']['GetClipboardWatch']();
+}
+
+export function GetCollisionPolicy() {
+  return window['go']['main']['App']['GetCollisionPolicy']();
+}
+
+export function GetCookieSettings() {
+  return window['go']['main']['App']['GetCookieSettings']();
+}
+
+export function GetCurrentBandwidthLimit() {
+  return window['go']['main']['App']['GetCurrentBandwidthLimit']();
+}
+
+export function GetDependencyStatus() {
+  return window['go']['main']['App']['GetDependencyStatus']();
+}
+
+export function GetDownloadDirectory() {
+  return window['go']['main']['App']['GetDownloadDirectory']();
+}
+
+export function GetFilenameMode() {
+  return window['go']['main']['App']['GetFilenameMode']();
+}
+
+export function GetFilenameTemplate() {
+  return window['go']['main']['App']['GetFilenameTemplate']();
+}
+
+export function GetGalleryDlRules() {
+  return window['go']['main']['App']['GetGalleryDlRules']();
+}
+
+export function GetHostCooldowns() {
+  return window['go']['main']['App']['GetHostCooldowns']();
+}
+
+export function GetLibraryDirectory() {
+  return window['go']['main']['App']['GetLibraryDirectory']();
+}
+
+export function GetMQTTConfig() {
+  return window['go']['main']['App']['GetMQTTConfig']();
+}
+
+export function GetMaxConcurrency() {
+  return window['go']['main']['App']['GetMaxConcurrency']();
+}
+
+export function GetMaxHeight() {
+  return window['go']['main']['App']['GetMaxHeight']();
+}
+
+export function GetMaxPerHost() {
+  return window['go']['main']['App']['GetMaxPerHost']();
+}
+
+export function GetMediaPreferences() {
+  return window['go']['main']['App']['GetMediaPreferences']();
+}
+
+export function GetOrganizeRules() {
+  return window['go']['main']['App']['GetOrganizeRules']();
+}
+
+export function GetPermanentDeleteFallback() {
+  return window['go']['main']['App']['GetPermanentDeleteFallback']();
+}
+
+export function GetPostDownloadHook() {
+  return window['go']['main']['App']['GetPostDownloadHook']();
+}
+
+export function GetProxy() {
+  return window['go']['main']['App']['GetProxy']();
+}
+
+export function GetQueueSummary() {
+  return window['go']['main']['App']['GetQueueSummary']();
+}
+
+export function GetRateLimitCooldown() {
+  return window['go']['main']['App']['GetRateLimitCooldown']();
+}
+
+export function GetRemoteConfig() {
+  return window['go']['main']['App']['GetRemoteConfig']();
+}
+
+export function GetRemoteStatus() {
+  return window['go']['main']['App']['GetRemoteStatus']();
+}
+
+export function GetRestartOnBandwidthChange() {
+  return window['go']['main']['App']['GetRestartOnBandwidthChange']();
+}
+
+export function GetRetentionPolicy() {
+  return window['go']['main']['App']['GetRetentionPolicy']();
+}
+
+export function GetRetryPolicy() {
+  return window['go']['main']['App']['GetRetryPolicy']();
+}
+
+export function GetSizeConfirmThreshold() {
+  return window['go']['main']['App']['GetSizeConfirmThreshold']();
+}
+
+export function GetStageStats() {
+  return window['go']['main']['App']['GetStageStats']();
+}
+
+export function GetStatistics() {
+  return window['go']['main']['App']['GetStatistics']();
+}
+
 export function GetTaskFileStatus(arg1) {
   return window['go']['main']['App']['GetTaskFileStatus'](arg1);
 }
 
+export function GetTaskLog(arg1, arg2) {
+  return window['go']['main']['App']['GetTaskLog'](arg1, arg2);
+}
+
 export function GetTaskResumeStatus(arg1) {
   return window['go']['main']['App']['GetTaskResumeStatus'](arg1);
 }
 
+export function GetTaskTimeline(arg1) {
+  return window['go']['main']['App']['GetTaskTimeline'](arg1);
+}
+
 export function GetUseBrowserCookies() {
   return window['go']['main']['App']['GetUseBrowserCookies']();
 }
 
+export function GetWatchDirectory() {
+  return window['go']['main']['App']['GetWatchDirectory']();
+}
+
+export function GetYtDlpVersion() {
+  return window['go']['main']['App']['GetYtDlpVersion']();
+}
+
+export function IgnoreClipboardURL(arg1) {
+  return window['go']['main']['App']['IgnoreClipboardURL'](arg1);
+}
+
+export function ImportFromBookmarks(arg1, arg2) {
+  return window['go']['main']['App']['ImportFromBookmarks'](arg1, arg2);
+}
+
+export function ImportSettings(arg1) {
+  return window['go']['main']['App']['ImportSettings'](arg1);
+}
+
 export function ImportTasks(arg1, arg2, arg3) {
   return window['go']['main']['App']['ImportTasks'](arg1, arg2, arg3);
 }
 
+export function ImportURLsFromFile(arg1) {
+  return window['go']['main']['App']['ImportURLsFromFile'](arg1);
+}
+
+export function InstallFfmpeg() {
+  return window['go']['main']['App']['InstallFfmpeg']();
+}
+
+export function ListArchiveEntries() {
+  return window['go']['main']['App']['ListArchiveEntries']();
+}
+
+export function ListBatches() {
+  return window['go']['main']['App']['ListBatches']();
+}
+
+export function ListBookmarkFolders(arg1) {
+  return window['go']['main']['App']['ListBookmarkFolders'](arg1);
+}
+
+export function ListDownloadEngines() {
+  return window['go']['main']['App']['ListDownloadEngines']();
+}
+
+export function ListHistory(arg1, arg2) {
+  return window['go']['main']['App']['ListHistory'](arg1, arg2);
+}
+
+export function ListHostFallbacks() {
+  return window['go']['main']['App']['ListHostFallbacks']();
+}
+
+export function ListHostOverrides() {
+  return window['go']['main']['App']['ListHostOverrides']();
+}
+
+export function ListPipelines() {
+  return window['go']['main']['App']['ListPipelines']();
+}
+
 export function ListProfiles() {
   return window['go']['main']['App']['ListProfiles']();
 }
 
+export function ListRecurrences() {
+  return window['go']['main']['App']['ListRecurrences']();
+}
+
+export function ListSiteCredentials() {
+  return window['go']['main']['App']['ListSiteCredentials']();
+}
+
+export function ListSnapshots() {
+  return window['go']['main']['App']['ListSnapshots']();
+}
+
+export function ListSubscriptions() {
+  return window['go']['main']['App']['ListSubscriptions']();
+}
+
+export function ListTaskCodes() {
+  return window['go']['main']['App']['ListTaskCodes']();
+}
+
+export function ListTaskOutputs(arg1) {
+  return window['go']['main']['App']['ListTaskOutputs'](arg1);
+}
+
+export function ListTaskTemplates() {
+  return window['go']['main']['App']['ListTaskTemplates']();
+}
+
 export function ListTasks() {
   return window['go']['main']['App']['ListTasks']();
 }
 
+export function ListTranscodePresets() {
+  return window['go']['main']['App']['ListTranscodePresets']();
+}
+
+export function MoveTaskOutput(arg1, arg2) {
+  return window['go']['main']['App']['MoveTaskOutput'](arg1, arg2);
+}
+
 export function OpenPath(arg1) {
   return window['go']['main']['App']['OpenPath'](arg1);
 }
@@ -62,14 +438,346 @@ export function OpenTaskFolder(arg1) {
   return window['go']['main']['App']['OpenTaskFolder'](arg1);
 }
 
+export function OpenTaskOutput(arg1, arg2) {
+  return window['go']['main']['App']['OpenTaskOutput'](arg1, arg2);
+}
+
+export function PauseAll() {
+  return window['go']['main']['App']['PauseAll']();
+}
+
+export function PauseQueue() {
+  return window['go']['main']['App']['PauseQueue']();
+}
+
+export function PauseTask(arg1) {
+  return window['go']['main']['App']['PauseTask'](arg1);
+}
+
+export function PickDirectory(arg1) {
+  return window['go']['main']['App']['PickDirectory'](arg1);
+}
+
+export function PickFile(arg1, arg2) {
+  return window['go']['main']['App']['PickFile'](arg1, arg2);
+}
+
+export function PickOutputDirectory() {
+  return window['go']['main']['App']['PickOutputDirectory']();
+}
+
+export function PreviewCommand(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewCommand'](arg1, arg2, arg3);
+}
+
+export function PreviewOrganizeRules(arg1) {
+  return window['go']['main']['App']['PreviewOrganizeRules'](arg1);
+}
+
+export function PreviewTask(arg1) {
+  return window['go']['main']['App']['PreviewTask'](arg1);
+}
+
+export function QueryTasks(arg1) {
+  return window['go']['main']['App']['QueryTasks'](arg1);
+}
+
+export function ReconcileOutputs() {
+  return window['go']['main']['App']['ReconcileOutputs']();
+}
+
+export function RecurrenceHistory(arg1) {
+  return window['go']['main']['App']['RecurrenceHistory'](arg1);
+}
+
+export function RefreshMetadata(arg1) {
+  return window['go']['main']['App']['RefreshMetadata'](arg1);
+}
+
+export function RelinkMissingOutput(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RelinkMissingOutput'](arg1, arg2, arg3);
+}
+
+export function RelinkTaskOutput(arg1, arg2) {
+  return window['go']['main']['App']['RelinkTaskOutput'](arg1, arg2);
+}
+
+export function RemoveArchiveEntries(arg1) {
+  return window['go']['main']['App']['RemoveArchiveEntries'](arg1);
+}
+
+export function RemoveSubscription(arg1) {
+  return window['go']['main']['App']['RemoveSubscription'](arg1);
+}
+
+export function RemoveTaskEntries(arg1) {
+  return window['go']['main']['App']['RemoveTaskEntries'](arg1);
+}
+
+export function RemoveTaskEntry(arg1) {
+  return window['go']['main']['App']['RemoveTaskEntry'](arg1);
+}
+
+export function RenameTask(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RenameTask'](arg1, arg2, arg3);
+}
+
+export function RenameTaskOutput(arg1, arg2) {
+  return window['go']['main']['App']['RenameTaskOutput'](arg1, arg2);
+}
+
+export function RestoreSnapshot(arg1) {
+  return window['go']['main']['App']['RestoreSnapshot'](arg1);
+}
+
+export function ResumeAll() {
+  return window['go']['main']['App']['ResumeAll']();
+}
+
+export function ResumeQueue() {
+  return window['go']['main']['App']['ResumeQueue']();
+}
+
 export function ResumeTask(arg1) {
   return window['go']['main']['App']['ResumeTask'](arg1);
 }
 
+export function ResumeTasks(arg1) {
+  return window['go']['main']['App']['ResumeTasks'](arg1);
+}
+
+export function RetryTasks(arg1) {
+  return window['go']['main']['App']['RetryTasks'](arg1);
+}
+
+export function RevealInFileManager(arg1) {
+  return window['go']['main']['App']['RevealInFileManager'](arg1);
+}
+
+export function RunCleanupNow(arg1) {
+  return window['go']['main']['App']['RunCleanupNow'](arg1);
+}
+
+export function RunDiagnostics() {
+  return window['go']['main']['App']['RunDiagnostics']();
+}
+
+export function RunPipeline(arg1, arg2) {
+  return window['go']['main']['App']['RunPipeline'](arg1, arg2);
+}
+
+export function SaveHostOverride(arg1) {
+  return window['go']['main']['App']['SaveHostOverride'](arg1);
+}
+
+export function SavePipeline(arg1) {
+  return window['go']['main']['App']['SavePipeline'](arg1);
+}
+
+export function SaveRecurrence(arg1) {
+  return window['go']['main']['App']['SaveRecurrence'](arg1);
+}
+
+export function SaveTaskTemplate(arg1) {
+  return window['go']['main']['App']['SaveTaskTemplate'](arg1);
+}
+
+export function ScanPartialFiles() {
+  return window['go']['main']['App']['ScanPartialFiles']();
+}
+
+export function ScheduleTask(arg1, arg2) {
+  return window['go']['main']['App']['ScheduleTask'](arg1, arg2);
+}
+
 export function SetActiveProfile(arg1) {
   return window['go']['main']['App']['SetActiveProfile'](arg1);
 }
 
+export function SetAria2Settings(arg1) {
+  return window['go']['main']['App']['SetAria2Settings'](arg1);
+}
+
+export function SetAutoResumeOnLaunch(arg1) {
+  return window['go']['main']['App']['SetAutoResumeOnLaunch'](arg1);
+}
+
+export function SetBandwidthLimit(arg1) {
+  return window['go']['main']['App']['SetBandwidthLimit'](arg1);
+}
+
+export function SetBandwidthRules(arg1) {
+  return window['go']['main']['App']['SetBandwidthRules'](arg1);
+}
+
+export function SetCleanupPolicy(arg1) {
+  return window['go']['main']['App']['SetCleanupPolicy'](arg1);
+}
+
+export function SetClipboardWatch(arg1) {
+  return window['go']['main']['App']['SetClipboardWatch'](arg1);
+}
+
+export function SetCollisionPolicy(arg1) {
+  return window['go']['main']['App']['SetCollisionPolicy'](arg1);
+}
+
+export function SetCookieSettings(arg1) {
+  return window['go']['main']['App']['SetCookieSettings'](arg1);
+}
+
+export function SetDownloadDirectory(arg1) {
+  return window['go']['main']['App']['SetDownloadDirectory'](arg1);
+}
+
+export function SetFilenameMode(arg1) {
+  return window['go']['main']['App']['SetFilenameMode'](arg1);
+}
+
+export function SetFilenameTemplate(arg1) {
+  return window['go']['main']['App']['SetFilenameTemplate'](arg1);
+}
+
+export function SetGalleryDlRules(arg1) {
+  return window['go']['main']['App']['SetGalleryDlRules'](arg1);
+}
+
+export function SetLibraryDirectory(arg1) {
+  return window['go']['main']['App']['SetLibraryDirectory'](arg1);
+}
+
+export function SetMQTTConfig(arg1) {
+  return window['go']['main']['App']['SetMQTTConfig'](arg1);
+}
+
+export function SetMaxConcurrency(arg1) {
+  return window['go']['main']['App']['SetMaxConcurrency'](arg1);
+}
+
+export function SetMaxHeight(arg1) {
+  return window['go']['main']['App']['SetMaxHeight'](arg1);
+}
+
+export function SetMaxPerHost(arg1) {
+  return window['go']['main']['App']['SetMaxPerHost'](arg1);
+}
+
+export function SetMediaPreferences(arg1) {
+  return window['go']['main']['App']['SetMediaPreferences'](arg1);
+}
+
+export function SetOrganizeRules(arg1) {
+  return window['go']['main']['App']['SetOrganizeRules'](arg1);
+}
+
+export function SetPermanentDeleteFallback(arg1) {
+  return window['go']['main']['App']['SetPermanentDeleteFallback'](arg1);
+}
+
+export function SetPostDownloadHook(arg1) {
+  return window['go']['main']['App']['SetPostDownloadHook'](arg1);
+}
+
+export function SetProxy(arg1) {
+  return window['go']['main']['App']['SetProxy'](arg1);
+}
+
+export function SetRateLimitCooldown(arg1) {
+  return window['go']['main']['App']['SetRateLimitCooldown'](arg1);
+}
+
+export function SetRemoteConfig(arg1) {
+  return window['go']['main']['App']['SetRemoteConfig'](arg1);
+}
+
+export function SetRestartOnBandwidthChange(arg1) {
+  return window['go']['main']['App']['SetRestartOnBandwidthChange'](arg1);
+}
+
+export function SetRetentionPolicy(arg1) {
+  return window['go']['main']['App']['SetRetentionPolicy'](arg1);
+}
+
+export function SetRetryPolicy(arg1) {
+  return window['go']['main']['App']['SetRetryPolicy'](arg1);
+}
+
+export function SetSiteCredentials(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetSiteCredentials'](arg1, arg2, arg3);
+}
+
+export function SetSizeConfirmThreshold(arg1) {
+  return window['go']['main']['App']['SetSizeConfirmThreshold'](arg1);
+}
+
+export function SetTaskEngine(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskEngine'](arg1, arg2);
+}
+
+export function SetTaskExtraArgs(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskExtraArgs'](arg1, arg2);
+}
+
+export function SetTaskFormat(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskFormat'](arg1, arg2);
+}
+
+export function SetTaskNotes(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskNotes'](arg1, arg2);
+}
+
+export function SetTaskOutputDir(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskOutputDir'](arg1, arg2);
+}
+
+export function SetTaskProxy(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskProxy'](arg1, arg2);
+}
+
+export function SetTaskSubtitles(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskSubtitles'](arg1, arg2);
+}
+
+export function SetTaskTags(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskTags'](arg1, arg2);
+}
+
 export function SetUseBrowserCookies(arg1) {
   return window['go']['main']['App']['SetUseBrowserCookies'](arg1);
 }
+
+export function SetWatchDirectory(arg1) {
+  return window['go']['main']['App']['SetWatchDirectory'](arg1);
+}
+
+export function TranscodeTask(arg1, arg2) {
+  return window['go']['main']['App']['TranscodeTask'](arg1, arg2);
+}
+
+export function UndoDelete(arg1) {
+  return window['go']['main']['App']['UndoDelete'](arg1);
+}
+
+export function UnpauseTask(arg1) {
+  return window['go']['main']['App']['UnpauseTask'](arg1);
+}
+
+export function UpdateProfile(arg1) {
+  return window['go']['main']['App']['UpdateProfile'](arg1);
+}
+
+export function UpdateTaskDetails(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateTaskDetails'](arg1, arg2, arg3);
+}
+
+export function UpdateYtDlp() {
+  return window['go']['main']['App']['UpdateYtDlp']();
+}
+
+export function VerifyAllTasks() {
+  return window['go']['main']['App']['VerifyAllTasks']();
+}
+
+export function VerifyTask(arg1) {
+  return window['go']['main']['App']['VerifyTask'](arg1);
+}
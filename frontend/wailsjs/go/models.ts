@@ -1,69 +1,1755 @@
 export namespace main {
 	
+	export class ArchiveEntry {
+	    extractor: string;
+	    videoId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ArchiveEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.extractor = source["extractor"];
+	        this.videoId = source["videoId"];
+	    }
+	}
+	export class Aria2Settings {
+	    enabled: boolean;
+	    connections: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Aria2Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.connections = source["connections"];
+	    }
+	}
+	export class BandwidthRule {
+	    start: string;
+	    end: string;
+	    limit: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BandwidthRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class BatchResult {
+	    succeeded: string[];
+	    failed: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.succeeded = source["succeeded"];
+	        this.failed = source["failed"];
+	    }
+	}
+	export class BatchSummary {
+	    id: string;
+	    taskIds: string[];
+	    total: number;
+	    counts: Record<string, number>;
+	    done: number;
+	    percent: number;
+	    downloadedBytes: number;
+	    createdAt: time.Time;
+	    updatedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.taskIds = source["taskIds"];
+	        this.total = source["total"];
+	        this.counts = source["counts"];
+	        this.done = source["done"];
+	        this.percent = source["percent"];
+	        this.downloadedBytes = source["downloadedBytes"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CleanupPolicy {
+	    failedTaskDays: number;
+	    trashDownloadsDays: number;
+	    purgeOrphans: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CleanupPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.failedTaskDays = source["failedTaskDays"];
+	        this.trashDownloadsDays = source["trashDownloadsDays"];
+	        this.purgeOrphans = source["purgeOrphans"];
+	    }
+	}
+	export class CleanupReport {
+	    dryRun: boolean;
+	    failedTasks: string[];
+	    trashedFiles: string[];
+	    orphanedFiles: string[];
+	    reclaimedBytes: number;
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CleanupReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dryRun = source["dryRun"];
+	        this.failedTasks = source["failedTasks"];
+	        this.trashedFiles = source["trashedFiles"];
+	        this.orphanedFiles = source["orphanedFiles"];
+	        this.reclaimedBytes = source["reclaimedBytes"];
+	        this.errors = source["errors"];
+	    }
+	}
+	export class ClipboardWatchSettings {
+	    enabled: boolean;
+	    autoQueue: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClipboardWatchSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.autoQueue = source["autoQueue"];
+	    }
+	}
+	export class CommandPreview {
+	    engine: string;
+	    argv: string[];
+	    command: string;
+	    outputDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommandPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.engine = source["engine"];
+	        this.argv = source["argv"];
+	        this.command = source["command"];
+	        this.outputDir = source["outputDir"];
+	    }
+	}
+	export class CookieSettings {
+	    browser: string;
+	    cookiesFile: string;
+	    browsers: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CookieSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.browser = source["browser"];
+	        this.cookiesFile = source["cookiesFile"];
+	        this.browsers = source["browsers"];
+	    }
+	}
+	export class DependencyStatus {
+	    name: string;
+	    found: boolean;
+	    path: string;
+	    version: string;
+	    managed: boolean;
+	    required: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DependencyStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.found = source["found"];
+	        this.path = source["path"];
+	        this.version = source["version"];
+	        this.managed = source["managed"];
+	        this.required = source["required"];
+	    }
+	}
+	export class DiagnosticCheck {
+	    id: string;
+	    name: string;
+	    status: string;
+	    detail: string;
+	    hint: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiagnosticCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.detail = source["detail"];
+	        this.hint = source["hint"];
+	    }
+	}
+	export class DiagnosticsReport {
+	    ok: boolean;
+	    checks: DiagnosticCheck[];
+	    ranAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiagnosticsReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ok = source["ok"];
+	        this.checks = this.convertValues(source["checks"], DiagnosticCheck);
+	        this.ranAt = this.convertValues(source["ranAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DownloadEngine {
+	    id: string;
+	    name: string;
+	    description: string;
+	    path: string;
+	    available: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DownloadEngine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.path = source["path"];
+	        this.available = source["available"];
+	    }
+	}
+	export class StatisticsBucket {
+	    key: string;
+	    count: number;
+	    succeeded: number;
+	    failed: number;
+	    canceled: number;
+	    bytes: number;
+	    downloadSeconds: number;
+	    averageSpeed: number;
+	    successRate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatisticsBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.count = source["count"];
+	        this.succeeded = source["succeeded"];
+	        this.failed = source["failed"];
+	        this.canceled = source["canceled"];
+	        this.bytes = source["bytes"];
+	        this.downloadSeconds = source["downloadSeconds"];
+	        this.averageSpeed = source["averageSpeed"];
+	        this.successRate = source["successRate"];
+	    }
+	}
+	export class DownloadStatistics {
+	    totals: StatisticsBucket;
+	    byDay: StatisticsBucket[];
+	    byHost: StatisticsBucket[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DownloadStatistics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.totals = this.convertValues(source["totals"], StatisticsBucket);
+	        this.byDay = this.convertValues(source["byDay"], StatisticsBucket);
+	        this.byHost = this.convertValues(source["byHost"], StatisticsBucket);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DuplicateFile {
+	    path: string;
+	    taskId: string;
+	    size: number;
+	    modTime: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.taskId = source["taskId"];
+	        this.size = source["size"];
+	        this.modTime = this.convertValues(source["modTime"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DuplicateGroup {
+	    sha256: string;
+	    size: number;
+	    files: DuplicateFile[];
+	    keep: string;
+	    trash: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sha256 = source["sha256"];
+	        this.size = source["size"];
+	        this.files = this.convertValues(source["files"], DuplicateFile);
+	        this.keep = source["keep"];
+	        this.trash = source["trash"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileFilter {
+	    displayName: string;
+	    pattern: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.displayName = source["displayName"];
+	        this.pattern = source["pattern"];
+	    }
+	}
+	export class FormatOption {
+	    id: string;
+	    ext: string;
+	    resolution: string;
+	    width: number;
+	    height: number;
+	    fps: number;
+	    vcodec: string;
+	    acodec: string;
+	    filesize: number;
+	    tbr: number;
+	    note: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FormatOption(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.ext = source["ext"];
+	        this.resolution = source["resolution"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.fps = source["fps"];
+	        this.vcodec = source["vcodec"];
+	        this.acodec = source["acodec"];
+	        this.filesize = source["filesize"];
+	        this.tbr = source["tbr"];
+	        this.note = source["note"];
+	    }
+	}
+	export class HistoryFilter {
+	    query: string;
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = source["query"];
+	        this.status = source["status"];
+	    }
+	}
+	export class StageSpan {
+	    stage: string;
+	    startedAt: time.Time;
+	    endedAt?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new StageSpan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stage = source["stage"];
+	        this.startedAt = this.convertValues(source["startedAt"], time.Time);
+	        this.endedAt = this.convertValues(source["endedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskOutput {
+	    path: string;
+	    kind: string;
+	    size: number;
+	    missing: boolean;
+	    sha256: string;
+	    modified: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskOutput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	        this.size = source["size"];
+	        this.missing = source["missing"];
+	        this.sha256 = source["sha256"];
+	        this.modified = source["modified"];
+	    }
+	}
+	export class SubtitleOptions {
+	    enabled: boolean;
+	    languages: string[];
+	    autoGenerated: boolean;
+	    embed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubtitleOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.languages = source["languages"];
+	        this.autoGenerated = source["autoGenerated"];
+	        this.embed = source["embed"];
+	    }
+	}
+	export class Task {
+	    id: string;
+	    url: string;
+	    title: string;
+	    notes: string;
+	    titleEdited?: boolean;
+	    tags: string[];
+	    extraArgs: string[];
+	    duplicateOf: string;
+	    extractor: string;
+	    videoId: string;
+	    allowDuplicate: boolean;
+	    sizeConfirmed?: boolean;
+	    sourceHost: string;
+	    status: string;
+	    stage: string;
+	    progress: string;
+	    speed: string;
+	    eta: string;
+	    downloadedBytes: number;
+	    totalBytes: number;
+	    outputDir: string;
+	    filenameTemplate?: string;
+	    formatId: string;
+	    startTime?: string;
+	    endTime?: string;
+	    batchId: string;
+	    recurrenceId?: string;
+	    deletedAt?: time.Time;
+	    profileId: string;
+	    subtitles?: SubtitleOptions;
+	    proxy: string;
+	    subtitlePaths: string[];
+	    outputPath: string;
+	    outputs: TaskOutput[];
+	    outputPaths: string[];
+	    embedded: string[];
+	    engine: string;
+	    command?: string;
+	    dryRun?: boolean;
+	    partialPath?: string;
+	    expectedSha256?: string;
+	    missingOutput: boolean;
+	    modifiedOutput: boolean;
+	    errorMessage: string;
+	    statusCode: string;
+	    stageCode: string;
+	    stageParams?: Record<string, string>;
+	    errorCode: string;
+	    errorParams?: Record<string, string>;
+	    errorHint?: string;
+	    errorAction?: string;
+	    resume: boolean;
+	    retryCount: number;
+	    maxRetries: number;
+	    scheduledAt?: time.Time;
+	    duration: number;
+	    filesize: number;
+	    width: number;
+	    height: number;
+	    createdAt: time.Time;
+	    timeline: StageSpan[];
+	    updatedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new Task(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.url = source["url"];
+	        this.title = source["title"];
+	        this.notes = source["notes"];
+	        this.titleEdited = source["titleEdited"];
+	        this.tags = source["tags"];
+	        this.extraArgs = source["extraArgs"];
+	        this.duplicateOf = source["duplicateOf"];
+	        this.extractor = source["extractor"];
+	        this.videoId = source["videoId"];
+	        this.allowDuplicate = source["allowDuplicate"];
+	        this.sizeConfirmed = source["sizeConfirmed"];
+	        this.sourceHost = source["sourceHost"];
+	        this.status = source["status"];
+	        this.stage = source["stage"];
+	        this.progress = source["progress"];
+	        this.speed = source["speed"];
+	        this.eta = source["eta"];
+	        this.downloadedBytes = source["downloadedBytes"];
+	        this.totalBytes = source["totalBytes"];
+	        this.outputDir = source["outputDir"];
+	        this.filenameTemplate = source["filenameTemplate"];
+	        this.formatId = source["formatId"];
+	        this.startTime = source["startTime"];
+	        this.endTime = source["endTime"];
+	        this.batchId = source["batchId"];
+	        this.recurrenceId = source["recurrenceId"];
+	        this.deletedAt = this.convertValues(source["deletedAt"], time.Time);
+	        this.profileId = source["profileId"];
+	        this.subtitles = this.convertValues(source["subtitles"], SubtitleOptions);
+	        this.proxy = source["proxy"];
+	        this.subtitlePaths = source["subtitlePaths"];
+	        this.outputPath = source["outputPath"];
+	        this.outputs = this.convertValues(source["outputs"], TaskOutput);
+	        this.outputPaths = source["outputPaths"];
+	        this.embedded = source["embedded"];
+	        this.engine = source["engine"];
+	        this.command = source["command"];
+	        this.dryRun = source["dryRun"];
+	        this.partialPath = source["partialPath"];
+	        this.expectedSha256 = source["expectedSha256"];
+	        this.missingOutput = source["missingOutput"];
+	        this.modifiedOutput = source["modifiedOutput"];
+	        this.errorMessage = source["errorMessage"];
+	        this.statusCode = source["statusCode"];
+	        this.stageCode = source["stageCode"];
+	        this.stageParams = source["stageParams"];
+	        this.errorCode = source["errorCode"];
+	        this.errorParams = source["errorParams"];
+	        this.errorHint = source["errorHint"];
+	        this.errorAction = source["errorAction"];
+	        this.resume = source["resume"];
+	        this.retryCount = source["retryCount"];
+	        this.maxRetries = source["maxRetries"];
+	        this.scheduledAt = this.convertValues(source["scheduledAt"], time.Time);
+	        this.duration = source["duration"];
+	        this.filesize = source["filesize"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.timeline = this.convertValues(source["timeline"], StageSpan);
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryPage {
+	    tasks: Task[];
+	    page: number;
+	    pageSize: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tasks = this.convertValues(source["tasks"], Task);
+	        this.page = source["page"];
+	        this.pageSize = source["pageSize"];
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HostCooldown {
+	    host: string;
+	    until: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new HostCooldown(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.until = this.convertValues(source["until"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HostFallback {
+	    host: string;
+	    fallbackId: string;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HostFallback(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.fallbackId = source["fallbackId"];
+	        this.name = source["name"];
+	    }
+	}
+	export class HostOverride {
+	    host: string;
+	    profileId: string;
+	    rateLimit: string;
+	    maxDownloads: number;
+	    concurrentFragments: number;
+	    sleepRequests: number;
+	    extraArgs: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HostOverride(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.profileId = source["profileId"];
+	        this.rateLimit = source["rateLimit"];
+	        this.maxDownloads = source["maxDownloads"];
+	        this.concurrentFragments = source["concurrentFragments"];
+	        this.sleepRequests = source["sleepRequests"];
+	        this.extraArgs = source["extraArgs"];
+	    }
+	}
+	export class ImportResult {
+	    created: Task[];
+	    found: number;
+	    duplicates: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.created = this.convertValues(source["created"], Task);
+	        this.found = source["found"];
+	        this.duplicates = source["duplicates"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MQTTConfig {
+	    enabled: boolean;
+	    broker: string;
+	    clientId: string;
+	    username: string;
+	    password: string;
+	    topicPrefix: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MQTTConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.broker = source["broker"];
+	        this.clientId = source["clientId"];
+	        this.username = source["username"];
+	        this.password = source["password"];
+	        this.topicPrefix = source["topicPrefix"];
+	    }
+	}
+	export class MediaPreferences {
+	    container: string;
+	    videoCodec: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MediaPreferences(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.container = source["container"];
+	        this.videoCodec = source["videoCodec"];
+	    }
+	}
+	export class MissingOutputTask {
+	    taskId: string;
+	    title: string;
+	    missingPaths: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MissingOutputTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.taskId = source["taskId"];
+	        this.title = source["title"];
+	        this.missingPaths = source["missingPaths"];
+	    }
+	}
+	export class OrganizePreview {
+	    taskId: string;
+	    title: string;
+	    ruleId: string;
+	    ruleName: string;
+	    folder: string;
+	    filenameTemplate: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrganizePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.taskId = source["taskId"];
+	        this.title = source["title"];
+	        this.ruleId = source["ruleId"];
+	        this.ruleName = source["ruleName"];
+	        this.folder = source["folder"];
+	        this.filenameTemplate = source["filenameTemplate"];
+	    }
+	}
+	export class OrganizeRule {
+	    id: string;
+	    name: string;
+	    hosts: string[];
+	    tags: string[];
+	    mediaTypes: string[];
+	    minHeight: number;
+	    maxHeight: number;
+	    folder: string;
+	    filenameTemplate: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrganizeRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.hosts = source["hosts"];
+	        this.tags = source["tags"];
+	        this.mediaTypes = source["mediaTypes"];
+	        this.minHeight = source["minHeight"];
+	        this.maxHeight = source["maxHeight"];
+	        this.folder = source["folder"];
+	        this.filenameTemplate = source["filenameTemplate"];
+	    }
+	}
+	export class PartialCleanupReport {
+	    removed: string[];
+	    reclaimedBytes: number;
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PartialCleanupReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.removed = source["removed"];
+	        this.reclaimedBytes = source["reclaimedBytes"];
+	        this.errors = source["errors"];
+	    }
+	}
+	export class PartialFile {
+	    path: string;
+	    size: number;
+	    modifiedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new PartialFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.modifiedAt = this.convertValues(source["modifiedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PipelineStep {
+	    kind: string;
+	    args: string[];
+	    target: string;
+	    removeSource?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PipelineStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.args = source["args"];
+	        this.target = source["target"];
+	        this.removeSource = source["removeSource"];
+	    }
+	}
+	export class Pipeline {
+	    id: string;
+	    name: string;
+	    steps: PipelineStep[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Pipeline(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.steps = this.convertValues(source["steps"], PipelineStep);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class PreviewEntry {
+	    url: string;
+	    title: string;
+	    duration: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PreviewEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.title = source["title"];
+	        this.duration = source["duration"];
+	    }
+	}
 	export class Profile {
 	    id: string;
-	    name: string;
-	    args: string[];
+	    name: string;
+	    args: string[];
+	    outputDir: string;
+	    filenameTemplate: string;
+	    postProcess: PipelineStep[];
+	    subtitles?: SubtitleOptions;
+	    postDownloadHook: string[];
+	    downloadArchive: boolean;
+	    transcodePreset: string;
+	    embedMetadata: boolean;
+	    embedChapters: boolean;
+	    embedThumbnail: boolean;
+	    aria2?: Aria2Settings;
+	    builtin: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Profile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.args = source["args"];
+	        this.outputDir = source["outputDir"];
+	        this.filenameTemplate = source["filenameTemplate"];
+	        this.postProcess = this.convertValues(source["postProcess"], PipelineStep);
+	        this.subtitles = this.convertValues(source["subtitles"], SubtitleOptions);
+	        this.postDownloadHook = source["postDownloadHook"];
+	        this.downloadArchive = source["downloadArchive"];
+	        this.transcodePreset = source["transcodePreset"];
+	        this.embedMetadata = source["embedMetadata"];
+	        this.embedChapters = source["embedChapters"];
+	        this.embedThumbnail = source["embedThumbnail"];
+	        this.aria2 = this.convertValues(source["aria2"], Aria2Settings);
+	        this.builtin = source["builtin"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QualityOption {
+	    label: string;
+	    height: number;
+	    audioOnly: boolean;
+	    estimatedSize: number;
+	    format: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QualityOption(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.height = source["height"];
+	        this.audioOnly = source["audioOnly"];
+	        this.estimatedSize = source["estimatedSize"];
+	        this.format = source["format"];
+	    }
+	}
+	export class QueueSummary {
+	    paused: boolean;
+	    counts: Record<string, number>;
+	    queued: number;
+	    scheduled: number;
+	    running: number;
+	    remainingBytes: number;
+	    percent: number;
+	    speed: number;
+	    speedText: string;
+	    etaSeconds: number;
+	    etaText: string;
+	    estimatedFinish: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueueSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.paused = source["paused"];
+	        this.counts = source["counts"];
+	        this.queued = source["queued"];
+	        this.scheduled = source["scheduled"];
+	        this.running = source["running"];
+	        this.remainingBytes = source["remainingBytes"];
+	        this.percent = source["percent"];
+	        this.speed = source["speed"];
+	        this.speedText = source["speedText"];
+	        this.etaSeconds = source["etaSeconds"];
+	        this.etaText = source["etaText"];
+	        this.estimatedFinish = this.convertValues(source["estimatedFinish"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UnreferencedFile {
+	    path: string;
+	    size: number;
+	    modifiedAt: time.Time;
+	    suggestedTaskId?: string;
+	    suggestedPath?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UnreferencedFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.modifiedAt = this.convertValues(source["modifiedAt"], time.Time);
+	        this.suggestedTaskId = source["suggestedTaskId"];
+	        this.suggestedPath = source["suggestedPath"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReconcileReport {
+	    unreferencedFiles: UnreferencedFile[];
+	    missingOutputs: MissingOutputTask[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReconcileReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.unreferencedFiles = this.convertValues(source["unreferencedFiles"], UnreferencedFile);
+	        this.missingOutputs = this.convertValues(source["missingOutputs"], MissingOutputTask);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Recurrence {
+	    id: string;
+	    url: string;
+	    title: string;
+	    intervalMinutes: number;
+	    cron: string;
+	    profileId: string;
+	    outputDir: string;
+	    enabled: boolean;
+	    nextRunAt: time.Time;
+	    lastRunAt: time.Time;
+	    lastTaskId: string;
+	    createdAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new Recurrence(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.url = source["url"];
+	        this.title = source["title"];
+	        this.intervalMinutes = source["intervalMinutes"];
+	        this.cron = source["cron"];
+	        this.profileId = source["profileId"];
+	        this.outputDir = source["outputDir"];
+	        this.enabled = source["enabled"];
+	        this.nextRunAt = this.convertValues(source["nextRunAt"], time.Time);
+	        this.lastRunAt = this.convertValues(source["lastRunAt"], time.Time);
+	        this.lastTaskId = source["lastTaskId"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RemoteConfig {
+	    enabled: boolean;
+	    port: number;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RemoteConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.token = source["token"];
+	    }
+	}
+	export class RemoteStatus {
+	    running: boolean;
+	    urls: string[];
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RemoteStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.urls = source["urls"];
+	        this.error = source["error"];
+	    }
+	}
+	export class RetentionPolicy {
+	    archiveAfterDays: number;
+	    deleteAfterDays: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RetentionPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.archiveAfterDays = source["archiveAfterDays"];
+	        this.deleteAfterDays = source["deleteAfterDays"];
+	    }
+	}
+	export class RetryPolicy {
+	    maxRetries: number;
+	    baseDelaySeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RetryPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxRetries = source["maxRetries"];
+	        this.baseDelaySeconds = source["baseDelaySeconds"];
+	    }
+	}
+	export class SiteCredential {
+	    host: string;
+	    username: string;
+	    inKeychain: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SiteCredential(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.username = source["username"];
+	        this.inKeychain = source["inKeychain"];
+	    }
+	}
+	export class Snapshot {
+	    id: string;
+	    createdAt: time.Time;
+	    reason: string;
+	    taskCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Snapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.reason = source["reason"];
+	        this.taskCount = source["taskCount"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class StageStat {
+	    stage: string;
+	    count: number;
+	    totalSeconds: number;
+	    averageSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StageStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stage = source["stage"];
+	        this.count = source["count"];
+	        this.totalSeconds = source["totalSeconds"];
+	        this.averageSeconds = source["averageSeconds"];
+	    }
+	}
+	
+	export class Subscription {
+	    id: string;
+	    kind: string;
+	    url: string;
+	    title: string;
+	    intervalMinutes: number;
+	    profileId: string;
+	    seen: string[];
+	    lastCheckedAt: time.Time;
+	    lastError: string;
+	    createdAt: time.Time;
 	
 	    static createFrom(source: any = {}) {
-	        return new Profile(source);
+	        return new Subscription(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.args = source["args"];
+	        this.kind = source["kind"];
+	        this.url = source["url"];
+	        this.title = source["title"];
+	        this.intervalMinutes = source["intervalMinutes"];
+	        this.profileId = source["profileId"];
+	        this.seen = source["seen"];
+	        this.lastCheckedAt = this.convertValues(source["lastCheckedAt"], time.Time);
+	        this.lastError = source["lastError"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class TaskCode {
+	    code: string;
+	    label: string;
+	    params: string[];
+	    hint?: string;
+	    action?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskCode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.label = source["label"];
+	        this.params = source["params"];
+	        this.hint = source["hint"];
+	        this.action = source["action"];
 	    }
 	}
-	export class Task {
-	    id: string;
+	export class TaskCodes {
+	    statuses: TaskCode[];
+	    stages: TaskCode[];
+	    errors: TaskCode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskCodes(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statuses = this.convertValues(source["statuses"], TaskCode);
+	        this.stages = this.convertValues(source["stages"], TaskCode);
+	        this.errors = this.convertValues(source["errors"], TaskCode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskFilter {
+	    statuses: string[];
+	    hosts: string[];
+	    batchId: string;
+	    since: time.Time;
+	    until: time.Time;
+	    search: string;
+	    sort: string;
+	    descending: boolean;
+	    limit: number;
+	    offset: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statuses = source["statuses"];
+	        this.hosts = source["hosts"];
+	        this.batchId = source["batchId"];
+	        this.since = this.convertValues(source["since"], time.Time);
+	        this.until = this.convertValues(source["until"], time.Time);
+	        this.search = source["search"];
+	        this.sort = source["sort"];
+	        this.descending = source["descending"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskOptions {
+	    url: string;
+	    outputDir: string;
+	    profileId: string;
+	    startTime: string;
+	    endTime: string;
+	    engine: string;
+	    sha256: string;
+	    extraArgs: string[];
+	    dryRun: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.outputDir = source["outputDir"];
+	        this.profileId = source["profileId"];
+	        this.startTime = source["startTime"];
+	        this.endTime = source["endTime"];
+	        this.engine = source["engine"];
+	        this.sha256 = source["sha256"];
+	        this.extraArgs = source["extraArgs"];
+	        this.dryRun = source["dryRun"];
+	    }
+	}
+	
+	export class TaskPreview {
 	    url: string;
 	    title: string;
+	    uploader: string;
 	    sourceHost: string;
-	    status: string;
-	    stage: string;
-	    progress: string;
-	    speed: string;
-	    eta: string;
-	    outputPath: string;
-	    missingOutput: boolean;
-	    errorMessage: string;
-	    resume: boolean;
+	    thumbnail: string;
 	    duration: number;
-	    filesize: number;
 	    width: number;
 	    height: number;
-	    // Go type: time
-	    createdAt: any;
-	    // Go type: time
-	    updatedAt: any;
+	    isPlaylist: boolean;
+	    playlistCount: number;
+	    entries: PreviewEntry[];
+	    isLive: boolean;
+	    engine: string;
+	    qualities: QualityOption[];
 	
 	    static createFrom(source: any = {}) {
-	        return new Task(source);
+	        return new TaskPreview(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
 	        this.url = source["url"];
 	        this.title = source["title"];
+	        this.uploader = source["uploader"];
 	        this.sourceHost = source["sourceHost"];
-	        this.status = source["status"];
-	        this.stage = source["stage"];
-	        this.progress = source["progress"];
-	        this.speed = source["speed"];
-	        this.eta = source["eta"];
-	        this.outputPath = source["outputPath"];
-	        this.missingOutput = source["missingOutput"];
-	        this.errorMessage = source["errorMessage"];
-	        this.resume = source["resume"];
+	        this.thumbnail = source["thumbnail"];
 	        this.duration = source["duration"];
-	        this.filesize = source["filesize"];
 	        this.width = source["width"];
 	        this.height = source["height"];
-	        this.createdAt = this.convertValues(source["createdAt"], null);
-	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.isPlaylist = source["isPlaylist"];
+	        this.playlistCount = source["playlistCount"];
+	        this.entries = this.convertValues(source["entries"], PreviewEntry);
+	        this.isLive = source["isLive"];
+	        this.engine = source["engine"];
+	        this.qualities = this.convertValues(source["qualities"], QualityOption);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskQueryResult {
+	    tasks: Task[];
+	    total: number;
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tasks = this.convertValues(source["tasks"], Task);
+	        this.total = source["total"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskTemplate {
+	    id: string;
+	    name: string;
+	    profileId: string;
+	    formatId: string;
+	    outputDir: string;
+	    subtitles?: SubtitleOptions;
+	    tags: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.profileId = source["profileId"];
+	        this.formatId = source["formatId"];
+	        this.outputDir = source["outputDir"];
+	        this.subtitles = this.convertValues(source["subtitles"], SubtitleOptions);
+	        this.tags = source["tags"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -84,6 +1770,102 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class TranscodePreset {
+	    id: string;
+	    name: string;
+	    ext: string;
+	    args: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TranscodePreset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.ext = source["ext"];
+	        this.args = source["args"];
+	    }
+	}
+	export class URLSupport {
+	    url: string;
+	    status: string;
+	    engine: string;
+	    extractor?: string;
+	    broken?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new URLSupport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.status = source["status"];
+	        this.engine = source["engine"];
+	        this.extractor = source["extractor"];
+	        this.broken = source["broken"];
+	    }
+	}
+	
+	export class VerifyResult {
+	    taskId: string;
+	    checked: number;
+	    missing: string[];
+	    modified: string[];
+	    recorded: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new VerifyResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.taskId = source["taskId"];
+	        this.checked = source["checked"];
+	        this.missing = source["missing"];
+	        this.modified = source["modified"];
+	        this.recorded = source["recorded"];
+	    }
+	}
+	export class YtDlpUpdateInfo {
+	    path: string;
+	    currentVersion: string;
+	    latestVersion: string;
+	    updateAvailable: boolean;
+	    managed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new YtDlpUpdateInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.currentVersion = source["currentVersion"];
+	        this.latestVersion = source["latestVersion"];
+	        this.updateAvailable = source["updateAvailable"];
+	        this.managed = source["managed"];
+	    }
+	}
+
+}
+
+export namespace time {
+	
+	export class Time {
+	
+	
+	    static createFrom(source: any = {}) {
+	        return new Time(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	
+	    }
+	}
 
 }
 
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	engineYtDlp     = "yt-dlp"
+	engineGalleryDl = "gallery-dl"
+)
+
+// defaultGalleryDlRules route image-gallery sites to gallery-dl. Each rule is
+// a regular expression matched against the full URL.
+var defaultGalleryDlRules = []string{
+	`^https?://([a-z0-9-]+\.)?imgur\.com/`,
+	`^https?://([a-z0-9-]+\.)?deviantart\.com/`,
+	`^https?://([a-z0-9-]+\.)?pixiv\.net/`,
+	`^https?://([a-z0-9-]+\.)?artstation\.com/`,
+	`^https?://([a-z0-9-]+\.)?flickr\.com/`,
+	`^https?://(www\.)?(twitter|x)\.com/[^/]+/status/\d+/photo/`,
+}
+
+// GetGalleryDlRules returns the URL patterns that are downloaded with gallery-dl.
+func (a *App) GetGalleryDlRules() ([]string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]string, len(a.galleryDlRules))
+	copy(out, a.galleryDlRules)
+	return out, nil
+}
+
+// SetGalleryDlRules replaces the gallery-dl routing patterns.
+func (a *App) SetGalleryDlRules(rules []string) error {
+	cleaned := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if _, err := regexp.Compile(rule); err != nil {
+			return fmt.Errorf("invalid rule %q", rule)
+		}
+		cleaned = append(cleaned, rule)
+	}
+	a.mu.Lock()
+	a.galleryDlRules = cleaned
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// engineForURL picks the download engine for a URL. gallery-dl is only chosen
+// when it is installed and one of the routing rules matches; links straight
+// to a file go to the built-in direct engine.
+func (a *App) engineForURL(rawURL string) string {
+	a.mu.Lock()
+	rules := a.galleryDlRules
+	available := a.galleryDlPath != ""
+	a.mu.Unlock()
+	if available {
+		for _, rule := range rules {
+			re, err := regexp.Compile(rule)
+			if err != nil {
+				continue
+			}
+			if re.MatchString(rawURL) {
+				return engineGalleryDl
+			}
+		}
+	}
+	if isDirectFileURL(rawURL) {
+		return engineDirect
+	}
+	return engineYtDlp
+}
+
+func resolveGalleryDlPath() string {
+	if envPath := strings.TrimSpace(os.Getenv("FETCHFORGE_GALLERYDL_PATH")); envPath != "" {
+		if fileExists(envPath) {
+			return envPath
+		}
+	}
+	if path, err := exec.LookPath("gallery-dl"); err == nil {
+		return path
+	}
+	candidates := []string{
+		"/opt/homebrew/bin/gallery-dl",
+		"/usr/local/bin/gallery-dl",
+		"/usr/bin/gallery-dl",
+	}
+	exe, err := os.Executable()
+	if err == nil {
+		exeDir := filepath.Dir(exe)
+		candidates = append(candidates,
+			filepath.Join(exeDir, "gallery-dl"),
+			filepath.Join(exeDir, "..", "Resources", "gallery-dl"),
+		)
+	}
+	home, err := os.UserHomeDir()
+	if err == nil {
+		candidates = append(candidates, filepath.Join(home, ".fetchforge", "bin", "gallery-dl"))
+	}
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func (a *App) galleryDlCommand(args ...string) *exec.Cmd {
+	path := a.galleryDlPath
+	if path == "" {
+		path = "gallery-dl"
+	}
+	cmd := exec.Command(path, args...)
+	configureProcessGroup(cmd)
+	return cmd
+}
+
+// countGalleryFiles asks gallery-dl for the file URLs without downloading so
+// progress can be reported as a percentage. It returns 0 when unknown.
+func (a *App) countGalleryFiles(targetURL string) int {
+	cmd := a.galleryDlCommand("--get-urls", targetURL)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// runGalleryTask downloads a gallery with gallery-dl, tracking each file it
+// writes. gallery-dl prints one path per downloaded file and prefixes files
+// it skipped as already present with "# ".
+func (a *App) runGalleryTask(id, targetURL, outputDir string) {
+	total := a.countGalleryFiles(targetURL)
+
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	task.setOutputs(nil)
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stageDownload, nil, task.UpdatedAt)
+	taskProxy := task.Proxy
+	updated := *task
+	a.mu.Unlock()
+	a.emitTaskUpdate(updated)
+
+	args := a.galleryDlArgs(targetURL, outputDir, taskProxy)
+	a.recordCommand(id, "gallery-dl", args)
+	cmd := a.galleryDlCommand(args...)
+	a.mu.Lock()
+	if task, ok := a.tasks[id]; !ok || task.Status == statusCanceled || task.Status == statusPaused || a.shuttingDown {
+		a.mu.Unlock()
+		return
+	}
+	a.running[id] = cmd
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		delete(a.running, id)
+		delete(a.suspended, id)
+		a.mu.Unlock()
+	}()
+
+	var files []string
+	onLine := func(line string) {
+		path := strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		if path == "" || !filepath.IsAbs(path) {
+			return
+		}
+		// Only the new file is stat'ed, outside the lock, so large galleries
+		// do not hold up the rest of the app.
+		output := imageOutput(path)
+		a.mu.Lock()
+		files = append(files, path)
+		progress := fmt.Sprintf("%d files", len(files))
+		if total > 0 {
+			percent := float64(len(files)) / float64(total) * 100
+			if percent > 100 {
+				percent = 100
+			}
+			progress = fmt.Sprintf("%.1f%%", percent)
+		}
+		task, ok := a.tasks[id]
+		if !ok {
+			a.mu.Unlock()
+			return
+		}
+		task.Progress = progress
+		task.setOutputs(append(task.Outputs, output))
+		task.UpdatedAt = time.Now()
+		updated := *task
+		a.mu.Unlock()
+		a.emitTaskUpdate(updated)
+		a.saveTasksSoon()
+	}
+
+	stdoutText, stderrText, err := a.runTaskCommand(id, cmd, onLine)
+	if a.taskStopped(id) {
+		return
+	}
+	if err != nil {
+		a.failTask(id, commandFailure(err, cmd, stdoutText, stderrText))
+		return
+	}
+	if len(files) == 0 {
+		a.failTask(id, taskError{Code: errorNoFiles, Message: "gallery-dl finished without downloading any files"})
+		return
+	}
+
+	a.mu.Lock()
+	task, ok = a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	task.setStatus(statusSuccess)
+	task.setOutputs(imageOutputs(files))
+	task.clearError()
+	if task.needsTitle() {
+		task.Title = filepath.Base(filepath.Dir(files[0]))
+	}
+	task.Progress = "100%"
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stageFinalize, nil, task.UpdatedAt)
+	task.closeStage(task.UpdatedAt)
+	updated = *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	go a.recordChecksumsAndCheckDuplicate(id)
+}
+
+// galleryDlArgs builds the gallery-dl arguments for a download.
+func (a *App) galleryDlArgs(targetURL, outputDir, taskProxy string) []string {
+	args := []string{"--destination", outputDir}
+	args = append(args, galleryDlFilenameArgs(a.currentFilenameMode())...)
+	args = append(args, a.cookieArgs()...)
+	args = append(args, a.proxyArgs(taskProxy)...)
+	return append(args, targetURL)
+}
+
+func imageOutputs(files []string) []TaskOutput {
+	outputs := make([]TaskOutput, 0, len(files))
+	for _, file := range files {
+		outputs = append(outputs, imageOutput(file))
+	}
+	return outputs
+}
+
+func imageOutput(file string) TaskOutput {
+	kind := outputKindImage
+	if outputKindForPath(file) == outputKindMedia {
+		kind = outputKindMedia
+	}
+	return statOutput(file, kind)
+}
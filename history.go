@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+	bolt "go.etcd.io/bbolt"
+)
+
+const historyPageSize = 50
+
+// historyBucket holds archived tasks keyed by big-endian finish time in
+// nanoseconds followed by the task ID, so entries sort oldest first.
+var historyBucket = []byte("history")
+
+// RetentionPolicy controls archiving. Finished tasks older than
+// ArchiveAfterDays leave the task list for the history; history entries older
+// than DeleteAfterDays are removed. Zero disables a rule.
+type RetentionPolicy struct {
+	ArchiveAfterDays int `json:"archiveAfterDays"`
+	DeleteAfterDays  int `json:"deleteAfterDays"`
+}
+
+// HistoryFilter narrows ListHistory. Query matches the title, URL or source
+// host; Status matches a status code such as "success".
+type HistoryFilter struct {
+	Query  string `json:"query"`
+	Status string `json:"status"`
+}
+
+// HistoryPage is one page of archived tasks, newest first.
+type HistoryPage struct {
+	Tasks    []Task `json:"tasks"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"pageSize"`
+	Total    int    `json:"total"`
+}
+
+// GetRetentionPolicy returns the archiving settings.
+func (a *App) GetRetentionPolicy() (RetentionPolicy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.retentionPolicy, nil
+}
+
+// SetRetentionPolicy changes the archiving settings and applies them.
+func (a *App) SetRetentionPolicy(policy RetentionPolicy) error {
+	if policy.ArchiveAfterDays < 0 || policy.DeleteAfterDays < 0 {
+		return errors.New("retention days cannot be negative")
+	}
+	a.mu.Lock()
+	a.retentionPolicy = policy
+	a.mu.Unlock()
+	a.saveConfig()
+	return a.applyRetentionPolicy()
+}
+
+// ListHistory returns a page of archived tasks, counting pages from 1.
+func (a *App) ListHistory(page int, filter HistoryFilter) (HistoryPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if a.store == nil {
+		return HistoryPage{}, errors.New("task store is not available")
+	}
+	query := strings.ToLower(strings.TrimSpace(filter.Query))
+	status := statusCodeFor(strings.TrimSpace(filter.Status))
+	result := HistoryPage{Tasks: []Task{}, Page: page, PageSize: historyPageSize}
+	skip := (page - 1) * historyPageSize
+	err := a.store.viewHistory(func(task Task) {
+		if status != "" && statusCodeFor(task.Status) != status {
+			return
+		}
+		if query != "" && !strings.Contains(strings.ToLower(task.Title), query) &&
+			!strings.Contains(strings.ToLower(task.URL), query) &&
+			!strings.Contains(strings.ToLower(task.SourceHost), query) {
+			return
+		}
+		result.Total++
+		if result.Total > skip && len(result.Tasks) < historyPageSize {
+			result.Tasks = append(result.Tasks, task)
+		}
+	})
+	return result, err
+}
+
+// addHistoryOutputs adds the output paths of archived tasks to referenced,
+// so their files are not taken for leftovers no task owns.
+func (a *App) addHistoryOutputs(referenced map[string]bool) error {
+	if a.store == nil {
+		return nil
+	}
+	return a.store.viewHistory(func(task Task) {
+		for _, path := range task.outputPaths() {
+			referenced[filepath.Clean(path)] = true
+		}
+	})
+}
+
+// applyRetentionPolicy moves old finished tasks into the history and prunes
+// expired history entries.
+func (a *App) applyRetentionPolicy() error {
+	if a.store == nil {
+		return nil
+	}
+	now := time.Now()
+	a.mu.Lock()
+	policy := a.retentionPolicy
+	var archived []Task
+	if policy.ArchiveAfterDays > 0 {
+		cutoff := now.Add(-time.Duration(policy.ArchiveAfterDays) * 24 * time.Hour)
+		for _, id := range a.order {
+			task, ok := a.tasks[id]
+			if !ok || task.deleted() || !task.finished() || task.UpdatedAt.After(cutoff) {
+				continue
+			}
+			archived = append(archived, *task)
+		}
+	}
+	a.mu.Unlock()
+
+	if len(archived) > 0 {
+		if err := a.store.archive(archived); err != nil {
+			return err
+		}
+		// A task retried while it was being archived stays in the list.
+		ids := make([]string, 0, len(archived))
+		a.mu.Lock()
+		for _, task := range archived {
+			if current, ok := a.tasks[task.ID]; ok && current.UpdatedAt.Equal(task.UpdatedAt) {
+				ids = append(ids, task.ID)
+			}
+		}
+		a.removeTasksLocked(ids)
+		a.mu.Unlock()
+		a.saveTasks()
+		if a.ctx != nil {
+			wailsruntime.EventsEmit(a.ctx, "tasks:archived", ids)
+		}
+	}
+	if policy.DeleteAfterDays > 0 {
+		return a.store.pruneHistory(now.Add(-time.Duration(policy.DeleteAfterDays) * 24 * time.Hour))
+	}
+	return nil
+}
+
+// finished reports whether the task has reached a final state.
+func (t *Task) finished() bool {
+	return t.Status == statusSuccess || t.Status == statusFailed || t.Status == statusCanceled
+}
+
+func historyKey(task Task) []byte {
+	key := make([]byte, 8, 8+len(task.ID))
+	binary.BigEndian.PutUint64(key, uint64(task.UpdatedAt.UnixNano()))
+	return append(key, task.ID...)
+}
+
+// archive writes tasks to the history bucket.
+func (s *taskStore) archive(tasks []Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(historyBucket)
+		for _, task := range tasks {
+			data, err := json.Marshal(task)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(historyKey(task), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// viewHistory calls fn for every archived task, newest first.
+func (s *taskStore) viewHistory(fn func(Task)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(historyBucket).Cursor()
+		for key, value := cursor.Last(); key != nil; key, value = cursor.Prev() {
+			var task Task
+			if err := json.Unmarshal(value, &task); err == nil {
+				fn(task)
+			}
+		}
+		return nil
+	})
+}
+
+// pruneHistory deletes history entries that finished before cutoff.
+func (s *taskStore) pruneHistory(cutoff time.Time) error {
+	limit := make([]byte, 8)
+	binary.BigEndian.PutUint64(limit, uint64(cutoff.UnixNano()))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(historyBucket).Cursor()
+		for key, _ := cursor.First(); key != nil && bytes.Compare(key[:8], limit) < 0; key, _ = cursor.First() {
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
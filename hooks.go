@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const postDownloadHookTimeout = 10 * time.Minute
+
+// GetPostDownloadHook returns the global post-download hook command.
+func (a *App) GetPostDownloadHook() ([]string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string{}, a.postDownloadHook...), nil
+}
+
+// SetPostDownloadHook sets the command run after every successful download
+// whose profile has no hook of its own. The first element is the program;
+// arguments may use the pipeline placeholders ({path}, {title}, {url}, ...)
+// and the command also receives FETCHFORGE_* environment variables. An empty
+// command disables the hook.
+func (a *App) SetPostDownloadHook(command []string) error {
+	command, err := normalizeHookCommand(command)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.postDownloadHook = command
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+func normalizeHookCommand(command []string) ([]string, error) {
+	out := make([]string, 0, len(command))
+	for _, arg := range command {
+		out = append(out, strings.TrimSpace(arg))
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	if len(out) > 0 && out[0] == "" {
+		return nil, errors.New("hook command needs a program")
+	}
+	return out, nil
+}
+
+// runPostDownloadHook runs the profile's hook, or the global one, for a task
+// that finished successfully. Its output goes to the task log; a failing
+// hook does not fail the task.
+func (a *App) runPostDownloadHook(id string, profile Profile) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok || task.Status != statusSuccess {
+		a.mu.Unlock()
+		return
+	}
+	snapshot := *task
+	command := profile.PostDownloadHook
+	if len(command) == 0 {
+		command = a.postDownloadHook
+	}
+	command = append([]string{}, command...)
+	a.mu.Unlock()
+	if len(command) == 0 {
+		return
+	}
+
+	argv := make([]string, 0, len(command))
+	for _, arg := range command {
+		argv = append(argv, expandPipelinePlaceholders(arg, snapshot))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), postDownloadHookTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = append(os.Environ(), pipelineEnv(snapshot)...)
+	output, err := cmd.CombinedOutput()
+	text := string(output)
+	if err != nil {
+		text = strings.TrimRight(text, "\n") + "\nhook failed: " + err.Error()
+	}
+	appendTaskLog(id, "post-download hook: "+strings.Join(argv, " "), text)
+}
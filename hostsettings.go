@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxConcurrentFragments = 32
+
+// HostOverride adjusts how downloads from one source host are run. Host
+// matches the host itself and its subdomains; the most specific match wins.
+// RateLimit uses yt-dlp syntax (e.g. "2M") and is combined with the
+// bandwidth schedule by taking the lower of the two. MaxDownloads caps
+// simultaneous downloads from the host; 0 uses the default per-host limit.
+// ProfileID is the profile new tasks from the host use when none is chosen,
+// e.g. Audio Only for soundcloud.com.
+type HostOverride struct {
+	Host                string   `json:"host"`
+	ProfileID           string   `json:"profileId"`
+	RateLimit           string   `json:"rateLimit"`
+	MaxDownloads        int      `json:"maxDownloads"`
+	ConcurrentFragments int      `json:"concurrentFragments"`
+	SleepRequests       float64  `json:"sleepRequests"`
+	ExtraArgs           []string `json:"extraArgs"`
+}
+
+// ListHostOverrides returns the per-host download settings sorted by host.
+func (a *App) ListHostOverrides() ([]HostOverride, error) {
+	a.mu.Lock()
+	out := make([]HostOverride, len(a.hostOverrides))
+	copy(out, a.hostOverrides)
+	a.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Host < out[j].Host })
+	return out, nil
+}
+
+// SaveHostOverride creates or replaces the settings for override.Host.
+func (a *App) SaveHostOverride(override HostOverride) (HostOverride, error) {
+	override.Host = normalizeOverrideHost(override.Host)
+	if override.Host == "" {
+		return HostOverride{}, errors.New("host is required")
+	}
+	override.RateLimit = strings.TrimSpace(override.RateLimit)
+	if override.RateLimit != "" && !rateLimitPattern.MatchString(override.RateLimit) {
+		return HostOverride{}, fmt.Errorf("invalid rate limit %q", override.RateLimit)
+	}
+	if override.MaxDownloads < 0 || override.MaxDownloads > maxConcurrencyLimit {
+		return HostOverride{}, fmt.Errorf("max downloads must be between 0 and %d", maxConcurrencyLimit)
+	}
+	if override.ConcurrentFragments < 0 || override.ConcurrentFragments > maxConcurrentFragments {
+		return HostOverride{}, fmt.Errorf("concurrent fragments must be between 0 and %d", maxConcurrentFragments)
+	}
+	if override.SleepRequests < 0 {
+		return HostOverride{}, errors.New("sleep between requests cannot be negative")
+	}
+	args := make([]string, 0, len(override.ExtraArgs))
+	for _, arg := range override.ExtraArgs {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			args = append(args, arg)
+		}
+	}
+	override.ExtraArgs = args
+	override.ProfileID = strings.TrimSpace(override.ProfileID)
+
+	a.mu.Lock()
+	if override.ProfileID != "" {
+		if _, ok := a.findProfileLocked(override.ProfileID); !ok {
+			a.mu.Unlock()
+			return HostOverride{}, errors.New("profile not found")
+		}
+	}
+	replaced := false
+	for i := range a.hostOverrides {
+		if a.hostOverrides[i].Host == override.Host {
+			a.hostOverrides[i] = override
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		a.hostOverrides = append(a.hostOverrides, override)
+	}
+	a.slotsChanged.Broadcast()
+	a.mu.Unlock()
+	a.saveConfig()
+	a.applyBandwidthChange()
+	return override, nil
+}
+
+// DeleteHostOverride removes the settings for a host.
+func (a *App) DeleteHostOverride(host string) error {
+	host = normalizeOverrideHost(host)
+	a.mu.Lock()
+	index := -1
+	for i := range a.hostOverrides {
+		if a.hostOverrides[i].Host == host {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		a.mu.Unlock()
+		return errors.New("host override not found")
+	}
+	a.hostOverrides = append(a.hostOverrides[:index:index], a.hostOverrides[index+1:]...)
+	a.slotsChanged.Broadcast()
+	a.mu.Unlock()
+	a.saveConfig()
+	a.applyBandwidthChange()
+	return nil
+}
+
+func normalizeOverrideHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if strings.Contains(host, "://") {
+		host = sourceHostFromURL(host)
+	}
+	host = strings.TrimPrefix(host, "www.")
+	return strings.Trim(host, ".")
+}
+
+// hostOverrideLocked returns the most specific override for host. The caller
+// must hold a.mu.
+func (a *App) hostOverrideLocked(host string) (HostOverride, bool) {
+	host = normalizeOverrideHost(host)
+	var best HostOverride
+	found := false
+	for _, override := range a.hostOverrides {
+		if host != override.Host && !strings.HasSuffix(host, "."+override.Host) {
+			continue
+		}
+		if !found || len(override.Host) > len(best.Host) {
+			best = override
+			found = true
+		}
+	}
+	return best, found
+}
+
+// hostProfileLocked returns the profile mapped to url's host, or "" when
+// there is none or it was deleted. The caller must hold a.mu.
+func (a *App) hostProfileLocked(url string) string {
+	override, ok := a.hostOverrideLocked(sourceHostFromURL(url))
+	if !ok || override.ProfileID == "" {
+		return ""
+	}
+	if _, ok := a.findProfileLocked(override.ProfileID); !ok {
+		return ""
+	}
+	return override.ProfileID
+}
+
+func (a *App) hostOverride(host string) (HostOverride, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.hostOverrideLocked(host)
+}
+
+// rateLimitForHost combines the bandwidth schedule with the host's limit.
+func (a *App) rateLimitForHost(host string, now time.Time) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rateLimitForHostLocked(host, now)
+}
+
+func (a *App) rateLimitForHostLocked(host string, now time.Time) string {
+	limit := a.scheduledRateLimitLocked(now)
+	if override, ok := a.hostOverrideLocked(host); ok {
+		limit = lowerRateLimit(limit, override.RateLimit)
+	}
+	return limit
+}
+
+// args returns the yt-dlp arguments for the override, excluding the rate
+// limit which is applied separately.
+func (o HostOverride) args() []string {
+	var args []string
+	if o.ConcurrentFragments > 0 {
+		args = append(args, "--concurrent-fragments", strconv.Itoa(o.ConcurrentFragments))
+	}
+	if o.SleepRequests > 0 {
+		args = append(args, "--sleep-requests", strconv.FormatFloat(o.SleepRequests, 'f', -1, 64))
+	}
+	return append(args, o.ExtraArgs...)
+}
+
+// lowerRateLimit returns the stricter of two yt-dlp rate limits, where an
+// empty limit means unlimited.
+func lowerRateLimit(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	if rateLimitBytes(b) < rateLimitBytes(a) {
+		return b
+	}
+	return a
+}
+
+func rateLimitBytes(limit string) float64 {
+	limit = strings.ToUpper(strings.TrimSpace(limit))
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(limit, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(limit, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(limit, "G"):
+		multiplier = 1 << 30
+	}
+	value, err := strconv.ParseFloat(strings.TrimRight(limit, "KMG"), 64)
+	if err != nil {
+		return 0
+	}
+	return value * multiplier
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxImportFileSize = 16 << 20
+
+// ImportResult reports what ImportURLsFromFile did with the URLs it found.
+// Duplicates counts URLs that already had a task or repeated in the file.
+type ImportResult struct {
+	Created    []Task `json:"created"`
+	Found      int    `json:"found"`
+	Duplicates int    `json:"duplicates"`
+}
+
+// ImportURLsFromFile queues the URLs listed in a .txt, .csv, .url or .json
+// file. Text files hold one URL per line (lines starting with # are skipped,
+// as in yt-dlp batch files); CSV files use a url/link column when the header
+// has one and otherwise the first cell that looks like a URL. .url files are
+// internet shortcuts, and JSON files hold a list of URLs or of objects with
+// a "url" field.
+func (a *App) ImportURLsFromFile(path string) (ImportResult, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ImportResult{}, errors.New("file path is required")
+	}
+	urls, err := readURLFile(path)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	return a.importURLs(urls), nil
+}
+
+// importURLs queues the URLs that have no task yet.
+func (a *App) importURLs(urls []string) ImportResult {
+	result := ImportResult{Found: len(urls)}
+	seen := make(map[string]bool, len(urls))
+	entries := make([]PreviewEntry, 0, len(urls))
+	a.mu.Lock()
+	for _, url := range urls {
+		if seen[url] || a.hasTaskForURLLocked(url) {
+			result.Duplicates++
+			continue
+		}
+		seen[url] = true
+		entries = append(entries, PreviewEntry{URL: url})
+	}
+	a.mu.Unlock()
+	result.Created = []Task{}
+	if len(entries) > 0 {
+		result.Created = a.createTasks(entries, newTaskOptions{})
+	}
+	return result
+}
+
+// readURLFile reads the URLs in a link file, picking the format by
+// extension.
+func readURLFile(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, errors.New("path is a directory")
+	}
+	if info.Size() > maxImportFileSize {
+		return nil, fmt.Errorf("file is larger than %d MB", maxImportFileSize>>20)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readCSVURLs(file)
+	case ".url":
+		return readShortcutURLs(file)
+	case ".json":
+		return readJSONURLs(file)
+	default:
+		return readTextURLs(file)
+	}
+}
+
+func readTextURLs(r io.Reader) ([]string, error) {
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		urls = append(urls, extractURLs(line)...)
+	}
+	return urls, scanner.Err()
+}
+
+func readCSVURLs(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	column := -1
+	var urls []string
+	for row := 0; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row == 0 {
+			if column = urlColumn(record); column >= 0 {
+				continue
+			}
+		}
+		if column >= 0 {
+			if column < len(record) && isHTTPURL(record[column]) {
+				urls = append(urls, strings.TrimSpace(record[column]))
+			}
+			continue
+		}
+		for _, cell := range record {
+			if isHTTPURL(cell) {
+				urls = append(urls, strings.TrimSpace(cell))
+				break
+			}
+		}
+	}
+	return urls, nil
+}
+
+// readShortcutURLs reads a Windows internet shortcut, an INI file with a
+// URL= line.
+func readShortcutURLs(r io.Reader) ([]string, error) {
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if ok && strings.EqualFold(key, "URL") && isHTTPURL(value) {
+			urls = append(urls, strings.TrimSpace(value))
+		}
+	}
+	return urls, scanner.Err()
+}
+
+// readJSONURLs accepts ["url", ...], [{"url": "..."}, ...] or
+// {"urls": [...]}.
+func readJSONURLs(r io.Reader) ([]string, error) {
+	var value interface{}
+	if err := json.NewDecoder(r).Decode(&value); err != nil {
+		return nil, err
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		value = object["urls"]
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("JSON file must hold a list of URLs")
+	}
+	var urls []string
+	for _, item := range items {
+		switch item := item.(type) {
+		case string:
+			if isHTTPURL(item) {
+				urls = append(urls, strings.TrimSpace(item))
+			}
+		case map[string]interface{}:
+			if url, ok := item["url"].(string); ok && isHTTPURL(url) {
+				urls = append(urls, strings.TrimSpace(url))
+			}
+		}
+	}
+	return urls, nil
+}
+
+// urlColumn finds the URL column in a CSV header row.
+func urlColumn(header []string) int {
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case "url", "link", "webpage_url", "source", "source url":
+			return i
+		}
+	}
+	return -1
+}
+
+func isHTTPURL(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
@@ -0,0 +1,26 @@
+//go:build darwin
+
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// The macOS keychain is reached through the security command line tool.
+
+func keychainSet(account, secret string) error {
+	return exec.Command("security", "add-generic-password", "-U", "-s", keychainService, "-a", account, "-w", secret).Run()
+}
+
+func keychainGet(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", keychainService, "-a", account, "-w").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func keychainDelete(account string) error {
+	return exec.Command("security", "delete-generic-password", "-s", keychainService, "-a", account).Run()
+}
@@ -0,0 +1,38 @@
+//go:build !darwin && !windows
+
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// On Linux and the BSDs the Secret Service (GNOME Keyring, KWallet) is used
+// through secret-tool when it is installed.
+
+func keychainSet(account, secret string) error {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return errKeychainUnavailable
+	}
+	cmd := exec.Command("secret-tool", "store", "--label", keychainService+" "+account, "service", keychainService, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	return cmd.Run()
+}
+
+func keychainGet(account string) (string, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return "", errKeychainUnavailable
+	}
+	out, err := exec.Command("secret-tool", "lookup", "service", keychainService, "account", account).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func keychainDelete(account string) error {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return errKeychainUnavailable
+	}
+	return exec.Command("secret-tool", "clear", "service", keychainService, "account", account).Run()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// LibraryMove is the payload of the task:moved event.
+type LibraryMove struct {
+	ID         string `json:"id"`
+	OutputPath string `json:"outputPath"`
+}
+
+// GetLibraryDirectory returns the library folder finished downloads are
+// moved into. Empty means downloads stay where they were downloaded.
+func (a *App) GetLibraryDirectory() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.libraryDir, nil
+}
+
+// SetLibraryDirectory sets the library folder. With a library set, the
+// download folder only holds downloads in progress: each finished download
+// is moved into the library, keeping its subfolder. Empty input turns the
+// library off.
+func (a *App) SetLibraryDirectory(path string) (string, error) {
+	dir, err := validateOutputDir(path)
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	a.libraryDir = dir
+	a.mu.Unlock()
+	a.saveConfig()
+	return dir, nil
+}
+
+// moveToLibrary moves a finished task's files from the download folder into
+// the library. Files outside the download folder, such as those of tasks
+// with their own output folder, stay put.
+func (a *App) moveToLibrary(id string) {
+	root, err := downloadRoot()
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	library := a.libraryDir
+	task, ok := a.tasks[id]
+	if !ok || library == "" || task.Status != statusSuccess {
+		a.mu.Unlock()
+		return
+	}
+	paths := task.outputPaths()
+	a.mu.Unlock()
+
+	moved := make(map[string]string)
+	var failure error
+	for _, path := range paths {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || !fileExists(path) {
+			continue
+		}
+		target := uniquePath(filepath.Join(library, rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			failure = err
+			break
+		}
+		if err := moveFile(path, target); err != nil {
+			failure = err
+			break
+		}
+		moved[path] = target
+		// Drop the staging folder once it is empty.
+		if dir := filepath.Dir(path); dir != filepath.Clean(root) {
+			_ = os.Remove(dir)
+		}
+	}
+
+	a.mu.Lock()
+	task, ok = a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	for from, to := range moved {
+		task.replaceOutputPath(from, to)
+	}
+	if failure != nil {
+		task.setError(taskError{Code: errorLibraryMove, Params: map[string]string{"detail": failure.Error()}, Message: "Could not move the download to the library: " + failure.Error()})
+	}
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	if len(moved) > 0 && a.ctx != nil {
+		wailsruntime.EventsEmit(a.ctx, "task:moved", LibraryMove{ID: id, OutputPath: updated.OutputPath})
+	}
+}
+
+// uniquePath returns path, or path with " (2)", " (3)"... before the
+// extension when a file already exists there.
+func uniquePath(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 2; ; n++ {
+		candidate := base + " (" + strconv.Itoa(n) + ")" + ext
+		if _, err := os.Stat(candidate); err != nil {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	plainURLPattern = regexp.MustCompile(`https?://[^\s]+`)
+	// linkTagPattern finds the tags whose href or src is worth downloading.
+	linkTagPattern  = regexp.MustCompile(`(?is)<(a|video|audio|source|iframe|embed)\b[^>]*>`)
+	linkAttrPattern = regexp.MustCompile(`(?is)\s(href|src)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	baseTagPattern  = regexp.MustCompile(`(?is)<base\b[^>]*>`)
+	// sourceURLPattern reads the page address Windows puts in the header of
+	// HTML clipboard content.
+	sourceURLPattern = regexp.MustCompile(`(?m)^SourceURL:(\S+)`)
+	anyTagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// extractedLink is a link found in pasted text or markup. Media is set for
+// the src of <video>, <audio> and <source> tags.
+type extractedLink struct {
+	URL   string
+	Media bool
+}
+
+// ExtractLinks returns the links in content, which may be plain text or
+// HTML such as a copied section of a web page. In HTML, <a href> and
+// <video>/<audio>/<source>/<iframe> src links are read and relative links are
+// resolved against the page's <base> or clipboard source URL. With
+// mediaOnly, only links that look downloadable are kept.
+func (a *App) ExtractLinks(content string, mediaOnly bool) ([]string, error) {
+	links := extractLinks(content)
+	out := make([]string, 0, len(links))
+	for _, link := range links {
+		if mediaOnly && !link.Media && !a.isLikelyMediaURL(link.URL) {
+			continue
+		}
+		out = append(out, link.URL)
+	}
+	return out, nil
+}
+
+func extractURLs(text string) []string {
+	links := extractLinks(text)
+	out := make([]string, 0, len(links))
+	for _, link := range links {
+		out = append(out, link.URL)
+	}
+	return out
+}
+
+// extractLinks finds http(s) links in text. Markup is searched for link tags
+// first; links in its text are picked up after the tags are stripped.
+func extractLinks(text string) []extractedLink {
+	var links []extractedLink
+	if linkTagPattern.MatchString(text) {
+		links = markupLinks(text)
+		text = sourceURLPattern.ReplaceAllString(text, "")
+		text = html.UnescapeString(anyTagPattern.ReplaceAllString(text, " "))
+	}
+	for _, match := range plainURLPattern.FindAllString(text, -1) {
+		links = append(links, extractedLink{URL: match})
+	}
+
+	out := make([]extractedLink, 0, len(links))
+	seen := make(map[string]int)
+	for _, link := range links {
+		if index, ok := seen[link.URL]; ok {
+			out[index].Media = out[index].Media || link.Media
+			continue
+		}
+		seen[link.URL] = len(out)
+		out = append(out, link)
+	}
+	return out
+}
+
+func markupLinks(markup string) []extractedLink {
+	var base *url.URL
+	if tag := baseTagPattern.FindString(markup); tag != "" {
+		if _, value, ok := linkAttr(tag); ok {
+			base, _ = url.Parse(value)
+		}
+	}
+	if base == nil {
+		if match := sourceURLPattern.FindStringSubmatch(markup); match != nil {
+			base, _ = url.Parse(match[1])
+		}
+	}
+
+	var links []extractedLink
+	for _, match := range linkTagPattern.FindAllStringSubmatch(markup, -1) {
+		tag := strings.ToLower(match[1])
+		attr, value, ok := linkAttr(match[0])
+		if !ok || (tag == "a") != (attr == "href") || strings.HasPrefix(value, "#") {
+			continue
+		}
+		link, err := url.Parse(value)
+		if err != nil {
+			continue
+		}
+		if base != nil {
+			link = base.ResolveReference(link)
+		}
+		if link.Scheme != "http" && link.Scheme != "https" {
+			continue
+		}
+		links = append(links, extractedLink{
+			URL:   link.String(),
+			Media: tag == "video" || tag == "audio" || tag == "source",
+		})
+	}
+	return links
+}
+
+// linkAttr returns the first href or src attribute of tag, unescaped.
+func linkAttr(tag string) (string, string, bool) {
+	match := linkAttrPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return "", "", false
+	}
+	value := match[2] + match[3] + match[4]
+	value = strings.TrimSpace(html.UnescapeString(value))
+	if value == "" {
+		return "", "", false
+	}
+	return strings.ToLower(match[1]), value, true
+}
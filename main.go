@@ -27,10 +27,16 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               "com.wails.FetchForge",
+			OnSecondInstanceLaunch: app.onSecondInstanceLaunch,
+		},
 		Bind: []interface{}{
 			app,
 		},
 		Mac: &mac.Options{
+			OnUrlOpen: app.handleDeepLink,
 			Preferences: &mac.Preferences{
 				FullscreenEnabled: mac.Enabled,
 			},
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ffmpegDurationPattern reads the input duration ffmpeg prints on stderr,
+	// which VOD playlists have and live streams lack.
+	ffmpegDurationPattern = regexp.MustCompile(`Duration: (\d+):(\d+):(\d+(?:\.\d+)?)`)
+	// ffmpegProgressPattern matches the key=value lines of -progress output.
+	ffmpegProgressPattern = regexp.MustCompile(`^(frame|fps|stream_\d+_\d+_q|bitrate|total_size|out_time_us|out_time_ms|out_time|dup_frames|drop_frames|speed|progress)=`)
+)
+
+// isManifestURL reports whether a URL points at an HLS or DASH manifest.
+func isManifestURL(rawURL string) bool {
+	ext := urlFileExt(rawURL)
+	return ext == ".m3u8" || ext == ".mpd"
+}
+
+// manifestFallbackApplies reports whether a failed yt-dlp run should be
+// retried by remuxing the stream with ffmpeg.
+func (a *App) manifestFallbackApplies(url, output string) bool {
+	if !isManifestURL(url) || a.ffmpegBinary() == "" {
+		return false
+	}
+	code, _ := classifyErrorText(output)
+	return code == errorUnsupportedURL
+}
+
+// downloadManifest remuxes an HLS/DASH stream into an .mp4 with ffmpeg. The
+// file is written as .mp4.part and renamed once ffmpeg finishes. It returns
+// the finished path, or a coded failure.
+func (a *App) downloadManifest(id, targetURL, outputDir, taskProxy string) (string, *taskError) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return "", nil
+	}
+	name := ""
+	if !task.needsTitle() {
+		name = sanitizeFilename(task.Title)
+	}
+	if taskProxy == "" {
+		taskProxy = a.proxy
+	}
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stageRetry, map[string]string{"fallback": "ffmpeg"}, task.UpdatedAt)
+	task.Progress = ""
+	updated := *task
+	a.mu.Unlock()
+	a.emitTaskUpdate(updated)
+
+	if name == "" {
+		name = sanitizeFilename(strings.TrimSuffix(defaultTitleFromURL(targetURL), urlFileExt(targetURL)))
+	}
+	if name == "" {
+		name = "stream"
+	}
+	if name = safeFilename(name, a.currentFilenameMode()); name == "" {
+		name = "stream"
+	}
+	finalPath := uniquePath(filepath.Join(outputDir, name+".mp4"))
+	partPath := finalPath + ".part"
+
+	args := []string{"-hide_banner", "-nostdin", "-y", "-nostats", "-progress", "pipe:1"}
+	if strings.HasPrefix(strings.ToLower(taskProxy), "http://") {
+		args = append(args, "-http_proxy", taskProxy)
+	}
+	args = append(args, "-i", targetURL, "-c", "copy", "-f", "mp4", partPath)
+	cmd := a.ffmpegCommand(args...)
+	configureProcessGroup(cmd)
+	a.mu.Lock()
+	if task, ok := a.tasks[id]; !ok || task.Status == statusCanceled || task.Status == statusPaused || a.shuttingDown {
+		a.mu.Unlock()
+		return "", nil
+	}
+	a.running[id] = cmd
+	a.mu.Unlock()
+	a.setPartialPath(id, partPath)
+
+	progress := &manifestProgress{}
+	stdoutText, stderrText, err := a.runTaskCommand(id, cmd, func(line string) {
+		if update, ok := progress.observe(line); ok {
+			a.applyProgressUpdate(id, update)
+		}
+	})
+	a.mu.Lock()
+	delete(a.running, id)
+	task, ok = a.tasks[id]
+	canceled := !ok || task.Status == statusCanceled
+	a.mu.Unlock()
+	if a.taskStopped(id) {
+		// A paused or interrupted recording keeps its .part file; only a
+		// cancel throws it away.
+		if canceled {
+			_ = os.Remove(partPath)
+			a.setPartialPath(id, "")
+		}
+		return "", nil
+	}
+	if err == nil {
+		err = os.Rename(partPath, finalPath)
+	}
+	a.setPartialPath(id, "")
+	if err != nil {
+		_ = os.Remove(partPath)
+		failure := commandFailure(err, cmd, stdoutText, stderrText)
+		return "", &failure
+	}
+	return finalPath, nil
+}
+
+// manifestProgress turns ffmpeg's -progress output into task progress. The
+// percentage needs the stream duration; live streams only report the time
+// and size recorded so far.
+type manifestProgress struct {
+	duration   float64
+	outSeconds float64
+	size       int64
+	lastSize   int64
+	lastAt     time.Time
+	speedX     float64
+}
+
+func (p *manifestProgress) observe(line string) (progressUpdate, bool) {
+	line = strings.TrimSpace(line)
+	if match := ffmpegDurationPattern.FindStringSubmatch(line); match != nil && p.duration == 0 {
+		hours, _ := strconv.ParseFloat(match[1], 64)
+		minutes, _ := strconv.ParseFloat(match[2], 64)
+		seconds, _ := strconv.ParseFloat(match[3], 64)
+		p.duration = hours*3600 + minutes*60 + seconds
+		return progressUpdate{}, false
+	}
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return progressUpdate{}, false
+	}
+	switch key {
+	case "out_time_us":
+		if micros, err := strconv.ParseInt(value, 10, 64); err == nil && micros > 0 {
+			p.outSeconds = float64(micros) / 1e6
+		}
+	case "total_size":
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil {
+			p.size = size
+		}
+	case "speed":
+		p.speedX = parseProgressFloat(strings.TrimSuffix(value, "x"))
+	case "progress":
+		return p.update(time.Now()), true
+	}
+	return progressUpdate{}, false
+}
+
+// update builds a progress sample at the end of each -progress block.
+func (p *manifestProgress) update(now time.Time) progressUpdate {
+	update := progressUpdate{downloaded: p.size, percent: formatETA(int64(p.outSeconds))}
+	if !p.lastAt.IsZero() && now.After(p.lastAt) && p.size >= p.lastSize {
+		update.rawSpeed = float64(p.size-p.lastSize) / now.Sub(p.lastAt).Seconds()
+	}
+	p.lastSize, p.lastAt = p.size, now
+	if p.duration > 0 {
+		percent := p.outSeconds / p.duration * 100
+		if percent > 100 {
+			percent = 100
+		}
+		update.percent = fmt.Sprintf("%.1f%%", percent)
+		if p.speedX > 0 {
+			update.eta = formatETA(int64((p.duration - p.outSeconds) / p.speedX))
+		}
+	}
+	return update
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	pageTitleTimeout  = 6 * time.Second
+	pageTitleMaxBytes = 512 << 10
+)
+
+var (
+	oembedLinkPattern = regexp.MustCompile(`(?i)<link[^>]+type=["']application/json\+oembed["'][^>]*>`)
+	hrefPattern       = regexp.MustCompile(`(?i)href=["']([^"']+)["']`)
+	ogTitlePattern    = regexp.MustCompile(`(?i)<meta[^>]+(?:property|name)=["'](?:og:title|twitter:title)["'][^>]*>`)
+	contentPattern    = regexp.MustCompile(`(?i)content=["']([^"']*)["']`)
+	titleTagPattern   = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+var pageTitleClient = &http.Client{Timeout: pageTitleTimeout}
+
+// RefreshMetadata re-runs the full metadata fetch for a task and falls back
+// to the page's oEmbed/OpenGraph title when yt-dlp cannot resolve it.
+func (a *App) RefreshMetadata(id string) (Task, error) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	targetURL := task.URL
+	taskProxy := task.Proxy
+	a.mu.Unlock()
+
+	if metadata := a.fetchMetadata(targetURL, taskProxy); metadata != nil {
+		updated, ok := a.applyMetadata(id, metadata)
+		if !ok {
+			return Task{}, errors.New("task not found")
+		}
+		return updated, nil
+	}
+	updated, ok := a.applyFallbackTitle(id, targetURL)
+	if !ok {
+		return Task{}, errors.New("metadata unavailable")
+	}
+	return updated, nil
+}
+
+// applyMetadata copies resolved metadata onto a task. Titles are only
+// replaced while the task still has a placeholder title.
+func (a *App) applyMetadata(id string, metadata *Task) (Task, bool) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, false
+	}
+	if task.needsTitle() && metadata.Title != "" {
+		task.Title = metadata.Title
+	}
+	if metadata.Duration > 0 {
+		task.Duration = metadata.Duration
+	}
+	if metadata.Filesize > 0 {
+		task.Filesize = metadata.Filesize
+	}
+	if metadata.Width > 0 {
+		task.Width = metadata.Width
+	}
+	if metadata.Height > 0 {
+		task.Height = metadata.Height
+	}
+	task.setMediaID(metadata)
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, true
+}
+
+// applyFallbackTitle fills a placeholder title from the page itself. It
+// reports false when nothing was changed.
+func (a *App) applyFallbackTitle(id, targetURL string) (Task, bool) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	needsTitle := ok && task.needsTitle()
+	a.mu.Unlock()
+	if !needsTitle {
+		return Task{}, false
+	}
+	title := fetchPageTitle(targetURL)
+	if title == "" {
+		return Task{}, false
+	}
+
+	a.mu.Lock()
+	task, ok = a.tasks[id]
+	if !ok || !task.needsTitle() {
+		a.mu.Unlock()
+		return Task{}, false
+	}
+	task.Title = title
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, true
+}
+
+// fetchPageTitle reads a title from the page's oEmbed endpoint, its
+// OpenGraph tags or its <title>, in that order.
+func fetchPageTitle(targetURL string) string {
+	page, err := url.Parse(targetURL)
+	if err != nil || (page.Scheme != "http" && page.Scheme != "https") {
+		return ""
+	}
+	body, err := fetchLimited(targetURL)
+	if err != nil {
+		return ""
+	}
+
+	if link := oembedLinkPattern.FindString(body); link != "" {
+		if match := hrefPattern.FindStringSubmatch(link); match != nil {
+			if endpoint, err := page.Parse(html.UnescapeString(match[1])); err == nil {
+				if title := fetchOEmbedTitle(endpoint.String()); title != "" {
+					return title
+				}
+			}
+		}
+	}
+	if meta := ogTitlePattern.FindString(body); meta != "" {
+		if match := contentPattern.FindStringSubmatch(meta); match != nil {
+			if title := cleanPageTitle(match[1]); title != "" {
+				return title
+			}
+		}
+	}
+	if match := titleTagPattern.FindStringSubmatch(body); match != nil {
+		return cleanPageTitle(match[1])
+	}
+	return ""
+}
+
+func fetchOEmbedTitle(endpoint string) string {
+	body, err := fetchLimited(endpoint)
+	if err != nil {
+		return ""
+	}
+	var payload struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		return ""
+	}
+	return cleanPageTitle(payload.Title)
+}
+
+func fetchLimited(targetURL string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, targetURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; FetchForge)")
+	resp, err := pageTitleClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New(resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, pageTitleMaxBytes))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func cleanPageTitle(title string) string {
+	title = strings.Join(strings.Fields(html.UnescapeString(title)), " ")
+	if shouldUpdateTitle(title) {
+		return ""
+	}
+	return title
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MQTTConfig configures the optional MQTT publisher used by home-automation
+// dashboards.
+//
+// Topics, relative to TopicPrefix:
+//
+//	status   "online"/"offline" (retained, offline is the last will)
+//	queue    QueueSummary JSON (retained)
+//	task     task lifecycle events as JSON
+//	command  accepts "pause" and "resume" for the queue
+type MQTTConfig struct {
+	Enabled     bool   `json:"enabled"`
+	Broker      string `json:"broker"`
+	ClientID    string `json:"clientId"`
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	TopicPrefix string `json:"topicPrefix"`
+}
+
+const (
+	defaultMQTTTopicPrefix = "fetchforge"
+	defaultMQTTClientID    = "fetchforge"
+	mqttKeepAlive          = 60 * time.Second
+	mqttDialTimeout        = 10 * time.Second
+	mqttMaxBackoff         = 2 * time.Minute
+)
+
+var errMQTTReconfigured = errors.New("mqtt configuration changed")
+
+type mqttMessage struct {
+	topic   string
+	payload []byte
+	retain  bool
+}
+
+// mqttTaskEvent is published on <prefix>/task whenever a task changes status.
+type mqttTaskEvent struct {
+	Event        string    `json:"event"`
+	ID           string    `json:"id"`
+	URL          string    `json:"url"`
+	Title        string    `json:"title"`
+	Status       string    `json:"status"`
+	OutputPath   string    `json:"outputPath"`
+	ErrorMessage string    `json:"errorMessage"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// GetMQTTConfig returns the MQTT publisher settings.
+func (a *App) GetMQTTConfig() (MQTTConfig, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.mqttConfig, nil
+}
+
+// SetMQTTConfig saves the MQTT publisher settings and reconnects.
+func (a *App) SetMQTTConfig(config MQTTConfig) error {
+	config.Broker = strings.TrimSpace(config.Broker)
+	config.ClientID = strings.TrimSpace(config.ClientID)
+	config.Username = strings.TrimSpace(config.Username)
+	config.TopicPrefix = strings.Trim(strings.TrimSpace(config.TopicPrefix), "/")
+	if config.TopicPrefix == "" {
+		config.TopicPrefix = defaultMQTTTopicPrefix
+	}
+	if strings.ContainsAny(config.TopicPrefix, "+#") {
+		return errors.New("topic prefix cannot contain MQTT wildcards")
+	}
+	if config.Enabled {
+		if _, _, err := parseMQTTBroker(config.Broker); err != nil {
+			return err
+		}
+	}
+
+	a.mu.Lock()
+	a.mqttConfig = config
+	a.mu.Unlock()
+	a.saveConfig()
+	select {
+	case a.mqttReconnect <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// parseMQTTBroker accepts host:port, tcp://host:port or tls://host:port and
+// returns the dial address and whether TLS is used.
+func parseMQTTBroker(broker string) (string, bool, error) {
+	if broker == "" {
+		return "", false, errors.New("broker address is required")
+	}
+	useTLS := false
+	host := broker
+	if strings.Contains(broker, "://") {
+		parsed, err := url.Parse(broker)
+		if err != nil || parsed.Host == "" {
+			return "", false, errors.New("invalid broker address")
+		}
+		switch strings.ToLower(parsed.Scheme) {
+		case "tcp", "mqtt":
+		case "tls", "ssl", "mqtts":
+			useTLS = true
+		default:
+			return "", false, fmt.Errorf("unsupported broker scheme %q", parsed.Scheme)
+		}
+		host = parsed.Host
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		port := "1883"
+		if useTLS {
+			port = "8883"
+		}
+		host = net.JoinHostPort(host, port)
+	}
+	return host, useTLS, nil
+}
+
+func (a *App) mqttEnabledPrefix() (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.mqttConfig.TopicPrefix, a.mqttConfig.Enabled
+}
+
+// publishTaskLifecycle sends a task event when the task's status differs
+// from the last one published. Progress-only updates are not sent.
+func (a *App) publishTaskLifecycle(task Task) {
+	a.mu.Lock()
+	if !a.mqttConfig.Enabled || a.mqttLastStatus[task.ID] == task.Status {
+		a.mu.Unlock()
+		return
+	}
+	a.mqttLastStatus[task.ID] = task.Status
+	prefix := a.mqttConfig.TopicPrefix
+	a.mu.Unlock()
+
+	event := strings.ToLower(task.Status)
+	switch task.Status {
+	case statusRunning:
+		event = "started"
+	case statusSuccess:
+		event = "completed"
+	}
+	payload, err := json.Marshal(mqttTaskEvent{
+		Event:        event,
+		ID:           task.ID,
+		URL:          task.URL,
+		Title:        task.Title,
+		Status:       task.Status,
+		OutputPath:   task.OutputPath,
+		ErrorMessage: task.ErrorMessage,
+		UpdatedAt:    task.UpdatedAt,
+	})
+	if err != nil {
+		return
+	}
+	a.enqueueMQTT(mqttMessage{topic: prefix + "/task", payload: payload})
+}
+
+func (a *App) publishQueueSummary(summary QueueSummary) {
+	prefix, enabled := a.mqttEnabledPrefix()
+	if !enabled {
+		return
+	}
+	payload, err := json.Marshal(summary)
+	if err != nil {
+		return
+	}
+	a.enqueueMQTT(mqttMessage{topic: prefix + "/queue", payload: payload, retain: true})
+}
+
+// enqueueMQTT hands a message to the publisher without blocking. Messages are
+// dropped while the broker is unreachable and the outbox is full.
+func (a *App) enqueueMQTT(message mqttMessage) {
+	select {
+	case a.mqttOutbox <- message:
+	default:
+	}
+}
+
+// mqttLoop keeps a broker connection open while MQTT is enabled, reconnecting
+// with backoff.
+func (a *App) mqttLoop() {
+	backoff := time.Second
+	for {
+		a.mu.Lock()
+		config := a.mqttConfig
+		a.mu.Unlock()
+		if !config.Enabled {
+			a.drainMQTTOutbox()
+			<-a.mqttReconnect
+			continue
+		}
+
+		connected, err := a.runMQTTSession(config)
+		if errors.Is(err, errMQTTReconfigured) {
+			backoff = time.Second
+			continue
+		}
+		if connected {
+			backoff = time.Second
+		}
+		select {
+		case <-time.After(backoff):
+		case <-a.mqttReconnect:
+			backoff = time.Second
+			continue
+		}
+		if backoff < mqttMaxBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+func (a *App) drainMQTTOutbox() {
+	for {
+		select {
+		case <-a.mqttOutbox:
+		default:
+			return
+		}
+	}
+}
+
+func (a *App) runMQTTSession(config MQTTConfig) (bool, error) {
+	address, useTLS, err := parseMQTTBroker(config.Broker)
+	if err != nil {
+		return false, err
+	}
+	dialer := &net.Dialer{Timeout: mqttDialTimeout}
+	var conn net.Conn
+	if useTLS {
+		host, _, _ := net.SplitHostPort(address)
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	prefix := config.TopicPrefix
+	statusTopic := prefix + "/status"
+	commandTopic := prefix + "/command"
+	reader := bufio.NewReader(conn)
+
+	if err := writeMQTT(conn, mqttConnectPacket(config, statusTopic)); err != nil {
+		return false, err
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(mqttDialTimeout))
+	header, body, err := readMQTTPacket(reader)
+	if err != nil {
+		return false, err
+	}
+	if header>>4 != 2 || len(body) < 2 {
+		return false, errors.New("unexpected reply from broker")
+	}
+	if body[1] != 0 {
+		return false, fmt.Errorf("broker refused connection (code %d)", body[1])
+	}
+
+	if err := writeMQTT(conn, mqttPublishPacket(statusTopic, []byte("online"), true)); err != nil {
+		return true, err
+	}
+	if err := writeMQTT(conn, mqttSubscribePacket(commandTopic)); err != nil {
+		return true, err
+	}
+	a.publishQueueSummary(a.queueSummary())
+
+	incoming := make(chan error, 1)
+	go func() {
+		incoming <- a.readMQTT(conn, reader, commandTopic)
+	}()
+	ping := time.NewTicker(mqttKeepAlive / 2)
+	defer ping.Stop()
+	for {
+		select {
+		case message := <-a.mqttOutbox:
+			if err := writeMQTT(conn, mqttPublishPacket(message.topic, message.payload, message.retain)); err != nil {
+				return true, err
+			}
+		case <-ping.C:
+			if err := writeMQTT(conn, []byte{0xC0, 0x00}); err != nil {
+				return true, err
+			}
+		case err := <-incoming:
+			return true, err
+		case <-a.mqttReconnect:
+			_ = writeMQTT(conn, mqttPublishPacket(statusTopic, []byte("offline"), true))
+			_ = writeMQTT(conn, []byte{0xE0, 0x00})
+			return true, errMQTTReconfigured
+		}
+	}
+}
+
+// readMQTT reads packets until the connection fails, applying queue commands
+// received on commandTopic.
+func (a *App) readMQTT(conn net.Conn, reader *bufio.Reader, commandTopic string) error {
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(mqttKeepAlive * 3 / 2))
+		header, body, err := readMQTTPacket(reader)
+		if err != nil {
+			return err
+		}
+		if header>>4 != 3 || len(body) < 2 {
+			continue
+		}
+		topicLen := int(body[0])<<8 | int(body[1])
+		if len(body) < 2+topicLen {
+			continue
+		}
+		topic := string(body[2 : 2+topicLen])
+		payload := body[2+topicLen:]
+		if (header>>1)&0x03 > 0 && len(payload) >= 2 {
+			payload = payload[2:]
+		}
+		if topic != commandTopic {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(string(payload))) {
+		case "pause":
+			a.PauseQueue()
+		case "resume":
+			a.ResumeQueue()
+		}
+	}
+}
+
+func mqttConnectPacket(config MQTTConfig, willTopic string) []byte {
+	clientID := config.ClientID
+	if clientID == "" {
+		clientID = defaultMQTTClientID
+	}
+	flags := byte(0x02 | 0x04 | 0x20)
+	if config.Username != "" {
+		flags |= 0x80
+		if config.Password != "" {
+			flags |= 0x40
+		}
+	}
+	keepAlive := int(mqttKeepAlive / time.Second)
+	body := append(mqttString("MQTT"), 4, flags, byte(keepAlive>>8), byte(keepAlive))
+	body = append(body, mqttString(clientID)...)
+	body = append(body, mqttString(willTopic)...)
+	body = append(body, mqttString("offline")...)
+	if config.Username != "" {
+		body = append(body, mqttString(config.Username)...)
+		if config.Password != "" {
+			body = append(body, mqttString(config.Password)...)
+		}
+	}
+	return mqttPacket(0x10, body)
+}
+
+func mqttPublishPacket(topic string, payload []byte, retain bool) []byte {
+	header := byte(0x30)
+	if retain {
+		header |= 0x01
+	}
+	return mqttPacket(header, append(mqttString(topic), payload...))
+}
+
+func mqttSubscribePacket(topic string) []byte {
+	body := append([]byte{0x00, 0x01}, mqttString(topic)...)
+	return mqttPacket(0x82, append(body, 0x00))
+}
+
+func mqttString(value string) []byte {
+	return append([]byte{byte(len(value) >> 8), byte(len(value))}, value...)
+}
+
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+func readMQTTPacket(reader *bufio.Reader) (byte, []byte, error) {
+	header, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length := 0
+	for shift := 0; ; shift += 7 {
+		if shift > 21 {
+			return 0, nil, errors.New("malformed packet length")
+		}
+		digit, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length |= int(digit&0x7F) << shift
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+func writeMQTT(conn net.Conn, packet []byte) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(mqttDialTimeout))
+	_, err := conn.Write(packet)
+	return err
+}
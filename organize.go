@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	mediaTypeVideo = "video"
+	mediaTypeAudio = "audio"
+	mediaTypeImage = "image"
+	mediaTypeFile  = "file"
+)
+
+// OrganizeRule decides where a download goes. Rules are checked in order
+// and the first whose conditions all match wins; empty conditions match
+// anything. Folder is relative to the download root and may use {host},
+// {extractor}, {tag}, {mediaType}, {height}, {date}, {year} and {month}.
+// A non-empty FilenameTemplate replaces the profile's template.
+type OrganizeRule struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Hosts            []string `json:"hosts"`
+	Tags             []string `json:"tags"`
+	MediaTypes       []string `json:"mediaTypes"`
+	MinHeight        int      `json:"minHeight"`
+	MaxHeight        int      `json:"maxHeight"`
+	Folder           string   `json:"folder"`
+	FilenameTemplate string   `json:"filenameTemplate"`
+}
+
+// OrganizePreview is where one task would go under a set of rules. RuleID is
+// empty when no rule matches and the dated default folder is used.
+type OrganizePreview struct {
+	TaskID           string `json:"taskId"`
+	Title            string `json:"title"`
+	RuleID           string `json:"ruleId"`
+	RuleName         string `json:"ruleName"`
+	Folder           string `json:"folder"`
+	FilenameTemplate string `json:"filenameTemplate"`
+}
+
+// GetOrganizeRules returns the file organization rules in order.
+func (a *App) GetOrganizeRules() ([]OrganizeRule, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]OrganizeRule{}, a.organizeRules...), nil
+}
+
+// SetOrganizeRules replaces the file organization rules. Rules without an ID
+// get one.
+func (a *App) SetOrganizeRules(rules []OrganizeRule) ([]OrganizeRule, error) {
+	rules, err := normalizeOrganizeRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	a.organizeRules = rules
+	a.mu.Unlock()
+	a.saveConfig()
+	return rules, nil
+}
+
+// PreviewOrganizeRules shows where each task in the list would be saved
+// under rules, without changing anything, so the rules can be tried before
+// they are saved. Folders and templates a task already has are ignored.
+func (a *App) PreviewOrganizeRules(rules []OrganizeRule) ([]OrganizePreview, error) {
+	rules, err := normalizeOrganizeRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	root, err := downloadRoot()
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	tasks := make([]Task, 0, len(a.order))
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok {
+			tasks = append(tasks, *task)
+		}
+	}
+	a.mu.Unlock()
+
+	out := make([]OrganizePreview, 0, len(tasks))
+	for _, task := range tasks {
+		profile := a.taskProfile(task.ProfileID)
+		preview := OrganizePreview{TaskID: task.ID, Title: task.Title}
+		if rule, ok := matchOrganizeRule(rules, task, profile); ok {
+			preview.RuleID = rule.ID
+			preview.RuleName = rule.Name
+			preview.Folder = filepath.Join(root, expandOrganizeFolder(rule.Folder, task, profile))
+			preview.FilenameTemplate = rule.FilenameTemplate
+		} else {
+			preview.Folder = filepath.Join(root, task.CreatedAt.Format("2006-01-02"))
+		}
+		if preview.FilenameTemplate == "" {
+			a.mu.Lock()
+			preview.FilenameTemplate = a.filenameTemplateForLocked(profile)
+			a.mu.Unlock()
+		}
+		out = append(out, preview)
+	}
+	return out, nil
+}
+
+func normalizeOrganizeRules(rules []OrganizeRule) ([]OrganizeRule, error) {
+	out := make([]OrganizeRule, 0, len(rules))
+	for _, rule := range rules {
+		rule.Name = strings.TrimSpace(rule.Name)
+		rule.Folder = strings.TrimSpace(rule.Folder)
+		rule.FilenameTemplate = strings.TrimSpace(rule.FilenameTemplate)
+		if rule.ID == "" {
+			rule.ID = newID()
+		}
+		if filepath.IsAbs(rule.Folder) {
+			return nil, errors.New("rule folder must be relative to the download folder")
+		}
+		for _, part := range strings.FieldsFunc(rule.Folder, func(r rune) bool { return r == '/' || r == '\\' }) {
+			if part == ".." {
+				return nil, errors.New("rule folder cannot leave the download folder")
+			}
+		}
+		if err := validateFilenameTemplate(rule.FilenameTemplate); err != nil {
+			return nil, err
+		}
+		if rule.MinHeight < 0 || rule.MaxHeight < 0 || (rule.MaxHeight > 0 && rule.MaxHeight < rule.MinHeight) {
+			return nil, errors.New("invalid resolution range")
+		}
+		for i, mediaType := range rule.MediaTypes {
+			mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+			if mediaType != mediaTypeVideo && mediaType != mediaTypeAudio && mediaType != mediaTypeImage && mediaType != mediaTypeFile {
+				return nil, errors.New("media type must be video, audio, image or file")
+			}
+			rule.MediaTypes[i] = mediaType
+		}
+		for i, host := range rule.Hosts {
+			rule.Hosts[i] = normalizeOverrideHost(host)
+		}
+		for i, tag := range rule.Tags {
+			rule.Tags[i] = normalizeTag(tag)
+		}
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+func matchOrganizeRule(rules []OrganizeRule, task Task, profile Profile) (OrganizeRule, bool) {
+	mediaType := taskMediaType(task, profile)
+	for _, rule := range rules {
+		if len(rule.Hosts) > 0 && !hostMatchesAny(normalizeOverrideHost(task.SourceHost), rule.Hosts) {
+			continue
+		}
+		if len(rule.Tags) > 0 && !hasAnyTag(task.Tags, rule.Tags) {
+			continue
+		}
+		if len(rule.MediaTypes) > 0 && !slices.Contains(rule.MediaTypes, mediaType) {
+			continue
+		}
+		if rule.MinHeight > 0 && task.Height < rule.MinHeight {
+			continue
+		}
+		if rule.MaxHeight > 0 && (task.Height == 0 || task.Height > rule.MaxHeight) {
+			continue
+		}
+		return rule, true
+	}
+	return OrganizeRule{}, false
+}
+
+// taskMediaType tells images (gallery-dl), audio-only profiles and video
+// apart. Direct downloads are typed by their file extension, and anything
+// that is not media counts as a file.
+func taskMediaType(task Task, profile Profile) string {
+	if task.Engine == engineGalleryDl {
+		return mediaTypeImage
+	}
+	if task.Engine == engineDirect {
+		switch urlFileExt(task.URL) {
+		case ".mp3", ".m4a", ".flac", ".wav", ".ogg", ".opus":
+			return mediaTypeAudio
+		case ".mp4", ".mkv", ".webm", ".mov", ".avi":
+			return mediaTypeVideo
+		}
+		return mediaTypeFile
+	}
+	for _, arg := range profile.Args {
+		if arg == "-x" || arg == "--extract-audio" {
+			return mediaTypeAudio
+		}
+	}
+	return mediaTypeVideo
+}
+
+func expandOrganizeFolder(folder string, task Task, profile Profile) string {
+	value := func(text string) string {
+		if text = sanitizeFilename(text); text == "" {
+			return "unknown"
+		}
+		return text
+	}
+	tag := ""
+	if len(task.Tags) > 0 {
+		tag = task.Tags[0]
+	}
+	height := ""
+	if task.Height > 0 {
+		height = strconv.Itoa(task.Height) + "p"
+	}
+	replacer := strings.NewReplacer(
+		"{host}", value(task.SourceHost),
+		"{extractor}", value(task.Extractor),
+		"{tag}", value(tag),
+		"{mediaType}", taskMediaType(task, profile),
+		"{height}", value(height),
+		"{date}", task.CreatedAt.Format("2006-01-02"),
+		"{year}", task.CreatedAt.Format("2006"),
+		"{month}", task.CreatedAt.Format("01"),
+	)
+	return filepath.Clean(replacer.Replace(folder))
+}
+
+// organizeTask applies the first matching rule to a task that has no folder
+// of its own, pinning the folder and filename template on the task so a
+// resumed download writes to the same place. It returns both, or empty
+// strings when no rule matches.
+func (a *App) organizeTask(id string, profile Profile) (string, string) {
+	root, err := downloadRoot()
+	if err != nil {
+		return "", ""
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok || task.OutputDir != "" {
+		a.mu.Unlock()
+		return "", ""
+	}
+	rule, ok := matchOrganizeRule(a.organizeRules, *task, profile)
+	if !ok {
+		a.mu.Unlock()
+		return "", ""
+	}
+	task.OutputDir = filepath.Join(root, expandOrganizeFolder(rule.Folder, *task, profile))
+	task.FilenameTemplate = rule.FilenameTemplate
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+	a.emitTaskUpdate(updated)
+	return updated.OutputDir, updated.FilenameTemplate
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	outputKindMedia     = "media"
+	outputKindSubtitle  = "subtitle"
+	outputKindThumbnail = "thumbnail"
+	outputKindImage     = "image"
+	outputKindInfo      = "info"
+	outputKindOther     = "other"
+)
+
+// TaskOutput is one file produced by a task.
+type TaskOutput struct {
+	Path    string `json:"path"`
+	Kind    string `json:"kind"`
+	Size    int64  `json:"size"`
+	Missing bool   `json:"missing"`
+	SHA256  string `json:"sha256"`
+	// Modified is set when the file no longer matches SHA256, the checksum
+	// recorded when the download finished.
+	Modified bool `json:"modified"`
+}
+
+// UnmarshalJSON accepts task records written before Outputs existed and
+// rebuilds the list from the legacy outputPath/outputFiles fields.
+func (t *Task) UnmarshalJSON(data []byte) error {
+	type taskAlias Task
+	var decoded struct {
+		taskAlias
+		OutputFiles []string `json:"outputFiles"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*t = Task(decoded.taskAlias)
+	t.fillLegacyCodes()
+	if len(t.Outputs) > 0 {
+		t.OutputPaths = outputPathList(t.Outputs)
+		return nil
+	}
+	paths := decoded.OutputFiles
+	if len(paths) == 0 && t.OutputPath != "" {
+		paths = []string{t.OutputPath}
+	}
+	for _, path := range paths {
+		t.Outputs = append(t.Outputs, TaskOutput{Path: path, Kind: outputKindForPath(path)})
+	}
+	t.OutputPaths = outputPathList(t.Outputs)
+	return nil
+}
+
+// setOutputs replaces the task's outputs and keeps the primary OutputPath,
+// OutputPaths, Filesize, MissingOutput and ModifiedOutput fields in sync with
+// them.
+func (t *Task) setOutputs(outputs []TaskOutput) {
+	t.Outputs = outputs
+	t.OutputPaths = outputPathList(outputs)
+	t.SubtitlePaths = nil
+	for _, output := range outputs {
+		if output.Kind == outputKindSubtitle && !output.Missing {
+			t.SubtitlePaths = append(t.SubtitlePaths, output.Path)
+		}
+	}
+	primary, ok := primaryOutput(outputs)
+	if !ok {
+		t.OutputPath = ""
+		t.MissingOutput = false
+		t.ModifiedOutput = false
+		return
+	}
+	t.OutputPath = primary.Path
+	var total int64
+	missing := false
+	modified := false
+	for _, output := range outputs {
+		total += output.Size
+		if output.Missing {
+			missing = true
+		}
+		if output.Modified {
+			modified = true
+		}
+	}
+	if total > 0 {
+		t.Filesize = total
+	}
+	t.MissingOutput = missing
+	t.ModifiedOutput = modified
+}
+
+// outputPaths returns every output path, falling back to OutputPath for tasks
+// that have not recorded an output list.
+func (t *Task) outputPaths() []string {
+	if len(t.Outputs) == 0 {
+		if t.OutputPath == "" {
+			return nil
+		}
+		return []string{t.OutputPath}
+	}
+	paths := make([]string, 0, len(t.Outputs))
+	for _, output := range t.Outputs {
+		paths = append(paths, output.Path)
+	}
+	return paths
+}
+
+// replaceOutputPath points the output at oldPath to newPath. The recorded
+// checksum carries over only when the size is unchanged, as after a move.
+func (t *Task) replaceOutputPath(oldPath, newPath string) {
+	outputs := make([]TaskOutput, 0, len(t.Outputs)+1)
+	found := false
+	for _, output := range t.Outputs {
+		if output.Path == oldPath {
+			moved := statOutput(newPath, output.Kind)
+			if moved.Size == output.Size {
+				moved.SHA256 = output.SHA256
+				moved.Modified = output.Modified
+			}
+			output = moved
+			found = true
+		}
+		outputs = append(outputs, output)
+	}
+	if !found {
+		outputs = append([]TaskOutput{statOutput(newPath, outputKindForPath(newPath))}, outputs...)
+	}
+	t.setOutputs(outputs)
+}
+
+// prependOutput records a file made from the task's output, such as a
+// transcode, as the new primary output. The existing outputs are kept.
+func (t *Task) prependOutput(path string) {
+	outputs := []TaskOutput{statOutput(path, outputKindForPath(path))}
+	for _, output := range t.Outputs {
+		if output.Path != path {
+			outputs = append(outputs, output)
+		}
+	}
+	t.setOutputs(outputs)
+}
+
+// outputPathList returns the output paths with the primary file first.
+func outputPathList(outputs []TaskOutput) []string {
+	paths := []string{}
+	primary, ok := primaryOutput(outputs)
+	if ok {
+		paths = append(paths, primary.Path)
+	}
+	for _, output := range outputs {
+		if !ok || output.Path != primary.Path {
+			paths = append(paths, output.Path)
+		}
+	}
+	return paths
+}
+
+func primaryOutput(outputs []TaskOutput) (TaskOutput, bool) {
+	for _, output := range outputs {
+		if output.Kind == outputKindMedia {
+			return output, true
+		}
+	}
+	if len(outputs) == 0 {
+		return TaskOutput{}, false
+	}
+	return outputs[0], true
+}
+
+func statOutput(path, kind string) TaskOutput {
+	output := TaskOutput{Path: path, Kind: kind}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		output.Missing = true
+		return output
+	}
+	output.Size = info.Size()
+	return output
+}
+
+// refreshOutputs re-stats every output and reports whether any changed. A
+// file whose size no longer matches its recorded checksum is marked
+// Modified without re-hashing it.
+func refreshOutputs(outputs []TaskOutput) ([]TaskOutput, bool) {
+	refreshed := make([]TaskOutput, 0, len(outputs))
+	changed := false
+	for _, output := range outputs {
+		next := statOutput(output.Path, output.Kind)
+		if next.Missing {
+			next.Size = output.Size
+		}
+		next.SHA256 = output.SHA256
+		next.Modified = output.Modified || (next.Size != output.Size && output.SHA256 != "")
+		if next != output {
+			changed = true
+		}
+		refreshed = append(refreshed, next)
+	}
+	return refreshed, changed
+}
+
+func outputKindForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".srt", ".vtt", ".ass", ".ssa", ".ttml", ".srv1", ".srv2", ".srv3", ".json3", ".lrc":
+		return outputKindSubtitle
+	case ".jpg", ".jpeg", ".png", ".webp", ".gif":
+		return outputKindThumbnail
+	case ".json", ".description", ".txt":
+		return outputKindInfo
+	case ".mp4", ".mkv", ".webm", ".mov", ".avi", ".flv", ".m4v", ".ts",
+		".mp3", ".m4a", ".opus", ".ogg", ".flac", ".wav", ".aac":
+		return outputKindMedia
+	}
+	return outputKindOther
+}
+
+var (
+	outputLinePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^\[[A-Za-z]+\] .*Destination: (.+)$`),
+		regexp.MustCompile(`^\[Merger\] Merging formats into "(.+)"$`),
+		regexp.MustCompile(`^\[info\] Writing video subtitles to: (.+)$`),
+		regexp.MustCompile(`^\[info\] Writing video thumbnail .* to: (.+)$`),
+		regexp.MustCompile(`^\[info\] Writing video metadata as JSON to: (.+)$`),
+	}
+	outputMovePattern = regexp.MustCompile(`^\[MoveFiles\] Moving file "(.+)" to "(.+)"$`)
+	// outputExistsPattern is printed instead of downloading when the target
+	// file is already on disk.
+	outputExistsPattern = regexp.MustCompile(`^\[download\] (.+) has already been downloaded`)
+	// partialDestinationPattern names the file a download is about to write;
+	// yt-dlp keeps it as <destination>.part until it is complete.
+	partialDestinationPattern = regexp.MustCompile(`^\[download\] Destination: (.+)$`)
+)
+
+// outputTracker collects the files yt-dlp reports writing, in order.
+type outputTracker struct {
+	mu         sync.Mutex
+	paths      []string
+	existing   []string
+	embedSteps map[string]bool
+}
+
+func (o *outputTracker) observe(line string) {
+	line = strings.TrimSpace(line)
+	if o.observeEmbed(line) {
+		return
+	}
+	if match := outputMovePattern.FindStringSubmatch(line); match != nil {
+		o.mu.Lock()
+		for i, path := range o.paths {
+			if path == match[1] {
+				o.paths[i] = match[2]
+			}
+		}
+		o.mu.Unlock()
+		o.add(match[2])
+		return
+	}
+	if match := outputExistsPattern.FindStringSubmatch(line); match != nil {
+		path := strings.TrimSpace(match[1])
+		o.mu.Lock()
+		o.existing = append(o.existing, path)
+		o.mu.Unlock()
+		o.add(path)
+		return
+	}
+	for _, pattern := range outputLinePatterns {
+		if match := pattern.FindStringSubmatch(line); match != nil {
+			o.add(strings.TrimSpace(match[1]))
+			return
+		}
+	}
+}
+
+func (o *outputTracker) add(path string) {
+	if path == "" {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, existing := range o.paths {
+		if existing == path {
+			return
+		}
+	}
+	o.paths = append(o.paths, path)
+}
+
+// alreadyDownloaded returns the first file yt-dlp skipped because it was
+// already on disk.
+func (o *outputTracker) alreadyDownloaded() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.existing) == 0 {
+		return ""
+	}
+	return o.existing[0]
+}
+
+// reset forgets everything tracked so far, before the download is run again.
+func (o *outputTracker) reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.paths = nil
+	o.existing = nil
+}
+
+// outputs returns the tracked files that still exist once the download is
+// done. Intermediate format files removed by the merger are dropped.
+func (o *outputTracker) outputs() []TaskOutput {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var outputs []TaskOutput
+	for _, path := range o.paths {
+		if isPartialFile(filepath.Base(path)) || !fileExists(path) {
+			continue
+		}
+		outputs = append(outputs, statOutput(path, outputKindForPath(path)))
+	}
+	return outputs
+}
+
+// ListTaskOutputs returns every file produced by a task with fresh sizes.
+func (a *App) ListTaskOutputs(id string) ([]TaskOutput, error) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return nil, errors.New("task not found")
+	}
+	outputs := make([]TaskOutput, len(task.Outputs))
+	copy(outputs, task.Outputs)
+	a.mu.Unlock()
+
+	refreshed, changed := refreshOutputs(outputs)
+	if changed {
+		a.mu.Lock()
+		if task, ok := a.tasks[id]; ok {
+			task.setOutputs(refreshed)
+		}
+		a.mu.Unlock()
+		a.saveTasks()
+	}
+	return refreshed, nil
+}
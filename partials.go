@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PartialFile is a leftover .part/.ytdl file in the download folder.
+type PartialFile struct {
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+}
+
+// PartialCleanupReport lists what CleanPartialFiles removed.
+type PartialCleanupReport struct {
+	Removed        []string `json:"removed"`
+	ReclaimedBytes int64    `json:"reclaimedBytes"`
+	Errors         []string `json:"errors"`
+}
+
+// ScanPartialFiles lists partial download files under the download folder
+// that no unfinished task can resume from. A partial belongs to a task when
+// it is the task's recorded .part file or one of its fragments, or sits in
+// the task's folder with the task's title in its name. Files changed in the
+// last day are left out in case a download is still writing them.
+func (a *App) ScanPartialFiles() ([]PartialFile, error) {
+	root, err := downloadRoot()
+	if err != nil {
+		return nil, err
+	}
+	return a.orphanedPartials(root, time.Now()), nil
+}
+
+// CleanPartialFiles deletes the given partial files. Only paths that a fresh
+// scan still reports as orphaned are removed.
+func (a *App) CleanPartialFiles(paths []string) (PartialCleanupReport, error) {
+	report := PartialCleanupReport{Removed: []string{}, Errors: []string{}}
+	if len(paths) == 0 {
+		return report, errors.New("no files selected")
+	}
+	root, err := downloadRoot()
+	if err != nil {
+		return report, err
+	}
+	orphaned := make(map[string]PartialFile)
+	for _, partial := range a.orphanedPartials(root, time.Now()) {
+		orphaned[partial.Path] = partial
+	}
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		partial, ok := orphaned[path]
+		if !ok {
+			report.Errors = append(report.Errors, path+": not an orphaned partial file")
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			report.Errors = append(report.Errors, path+": "+err.Error())
+			continue
+		}
+		report.Removed = append(report.Removed, path)
+		report.ReclaimedBytes += partial.Size
+	}
+	return report, nil
+}
+
+// partialOwner is what an unfinished task's partial files look like.
+type partialOwner struct {
+	dir   string
+	title string
+}
+
+func (a *App) orphanedPartials(root string, now time.Time) []PartialFile {
+	var claimed []string
+	var owners []partialOwner
+	a.mu.Lock()
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok || task.Status == statusSuccess {
+			continue
+		}
+		if task.PartialPath != "" {
+			claimed = append(claimed, filepath.Clean(task.PartialPath))
+		}
+		dir, err := taskOutputDir(task.OutputDir, task.CreatedAt)
+		if err != nil {
+			continue
+		}
+		owners = append(owners, partialOwner{dir: filepath.Clean(dir), title: normalizeForMatch(task.Title)})
+	}
+	a.mu.Unlock()
+
+	partials := []PartialFile{}
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !isPartialFile(d.Name()) {
+			return nil
+		}
+		for _, partPath := range claimed {
+			if belongsToPartial(partPath, path) {
+				return nil
+			}
+		}
+		info, err := d.Info()
+		if err != nil || now.Sub(info.ModTime()) < orphanMinimumAge {
+			return nil
+		}
+		name := normalizeForMatch(d.Name())
+		for _, owner := range owners {
+			if owner.title != "" && strings.Contains(name, owner.title) &&
+				(filepath.Dir(path) == owner.dir || strings.HasPrefix(path, owner.dir+string(filepath.Separator))) {
+				return nil
+			}
+		}
+		partials = append(partials, PartialFile{Path: path, Size: info.Size(), ModifiedAt: info.ModTime()})
+		return nil
+	})
+	return partials
+}
+
+// partialExists reports whether any piece of the download kept at partPath
+// is still on disk.
+func partialExists(partPath string) bool {
+	if fileExists(partPath) {
+		return true
+	}
+	entries, err := os.ReadDir(filepath.Dir(partPath))
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if belongsToPartial(partPath, filepath.Join(filepath.Dir(partPath), entry.Name())) {
+			return true
+		}
+	}
+	return false
+}
+
+// belongsToPartial reports whether path is the .part file at partPath or
+// one of the files yt-dlp keeps next to it: numbered fragments of a
+// segmented download and the .ytdl file that records their progress.
+func belongsToPartial(partPath, path string) bool {
+	if path == partPath {
+		return true
+	}
+	if strings.HasPrefix(path, partPath+"-Frag") {
+		return true
+	}
+	return path == strings.TrimSuffix(partPath, ".part")+".ytdl"
+}
+
+// removeTaskPartials deletes the partial files a task left behind: its
+// recorded .part file with any fragments, and partial files named after one
+// of its outputs, such as "Title.f137.mp4.part" or a transcode's temp file.
+// Only the folders holding those files are listed, never searched, so
+// unrelated downloads in a shared folder are left alone.
+func removeTaskPartials(partialPath string, outputPaths []string) {
+	if partialPath != "" {
+		removeMatchingFiles(filepath.Dir(partialPath), func(path string) bool {
+			return belongsToPartial(partialPath, path)
+		})
+	}
+	for _, output := range outputPaths {
+		stem := strings.TrimSuffix(filepath.Base(output), filepath.Ext(output)) + "."
+		removeMatchingFiles(filepath.Dir(output), func(path string) bool {
+			name := filepath.Base(path)
+			return strings.HasPrefix(name, stem) && isPartialFile(name)
+		})
+	}
+}
+
+// removeMatchingFiles removes the files directly inside dir that match.
+func removeMatchingFiles(dir string, match func(path string) bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() && match(path) {
+			_ = os.Remove(path)
+		}
+	}
+}
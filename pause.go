@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"time"
+)
+
+var errSuspendUnsupported = errors.New("process suspension is not supported")
+
+// PauseTask pauses a queued or running task. A running download is suspended
+// in place where the platform allows it and gives its download slot back to
+// the queue; otherwise the process is stopped and the .part file is kept so
+// UnpauseTask can continue from it.
+func (a *App) PauseTask(id string) error {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return errors.New("task not found")
+	}
+	if task.Status != statusQueued && task.Status != statusRunning {
+		a.mu.Unlock()
+		return errors.New("task is not active")
+	}
+	if cmd, ok := a.running[id]; ok && !a.suspended[id] {
+		if err := suspendProcessTree(cmd); err == nil {
+			a.suspended[id] = true
+			a.slotFreed[id] = true
+			a.releaseSlotLocked(sourceHostFromURL(task.URL))
+		} else {
+			task.Resume = true
+			_ = killProcessTree(cmd)
+		}
+	}
+	task.setStatus(statusPaused)
+	task.Speed = ""
+	task.ETA = ""
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return nil
+}
+
+// UnpauseTask continues a paused task, either by resuming the suspended
+// process or by queueing the download again. A suspended download stays
+// Queued until it can take a download slot again.
+func (a *App) UnpauseTask(id string) error {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return errors.New("task is deleted")
+	}
+	if task.Status != statusPaused {
+		a.mu.Unlock()
+		return errors.New("task is not paused")
+	}
+	_, running := a.running[id]
+	if running && !a.suspended[id] {
+		a.mu.Unlock()
+		return errors.New("task is still stopping")
+	}
+	task.setStatus(statusQueued)
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	if running {
+		go a.resumeSuspended(id)
+	} else {
+		a.enqueueTasks([]string{id})
+	}
+	return nil
+}
+
+// resumeSuspended waits until a suspended download can take a download slot
+// under the same limits the worker uses, then continues the process. It gives
+// up when the task is canceled, deleted or paused again in the meantime.
+func (a *App) resumeSuspended(id string) {
+	a.mu.Lock()
+	var task *Task
+	var cmd *exec.Cmd
+	var host string
+	for {
+		var ok, running bool
+		task, ok = a.tasks[id]
+		cmd, running = a.running[id]
+		if !ok || !running || !a.suspended[id] || task.Status != statusQueued {
+			a.mu.Unlock()
+			return
+		}
+		host = sourceHostFromURL(task.URL)
+		if !a.queuePaused && !a.shuttingDown && a.activeDownloads < a.maxConcurrency &&
+			a.hostActive[host] < a.hostLimitLocked(host) {
+			break
+		}
+		a.slotsChanged.Wait()
+	}
+	delete(a.suspended, id)
+	if err := resumeProcessTree(cmd); err == nil {
+		delete(a.slotFreed, id)
+		a.activeDownloads++
+		a.hostActive[host]++
+		task.setStatus(statusRunning)
+	} else {
+		// Leave it paused so its runner exits cleanly; unpausing again
+		// continues from the .part file.
+		task.Resume = true
+		task.setStatus(statusPaused)
+		_ = killProcessTree(cmd)
+	}
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+}
+
+// interruptRunning stops every running download when the app quits. Their
+// tasks become Interrupted with Resume set, so the partial files are picked
+// up on the next launch. Suspended (paused) downloads are left to
+// stopSuspended.
+func (a *App) interruptRunning() {
+	now := time.Now()
+	a.mu.Lock()
+	a.shuttingDown = true
+	for id, cmd := range a.running {
+		if !a.suspended[id] {
+			_ = killProcessTree(cmd)
+		}
+	}
+	for _, task := range a.tasks {
+		if task.Status != statusRunning {
+			continue
+		}
+		task.setStatus(statusInterrupted)
+		task.Resume = true
+		task.Speed = ""
+		task.ETA = ""
+		task.UpdatedAt = now
+		task.closeStage(now)
+	}
+	a.mu.Unlock()
+	a.saveTasks()
+}
+
+// stopSuspended kills suspended downloads so no stopped processes outlive
+// the app. Their tasks stay paused and continue from the .part file.
+func (a *App) stopSuspended() {
+	a.mu.Lock()
+	for id := range a.suspended {
+		if cmd, ok := a.running[id]; ok {
+			_ = killProcessTree(cmd)
+		}
+		if task, ok := a.tasks[id]; ok {
+			task.Resume = true
+		}
+		delete(a.suspended, id)
+	}
+	a.mu.Unlock()
+	a.saveTasks()
+}
+
+// PauseAll holds the queue and pauses every running download.
+func (a *App) PauseAll() error {
+	a.setQueuePaused(true)
+	a.mu.Lock()
+	var ids []string
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok && task.Status == statusRunning {
+			ids = append(ids, id)
+		}
+	}
+	a.mu.Unlock()
+	for _, id := range ids {
+		_ = a.PauseTask(id)
+	}
+	return nil
+}
+
+// ResumeAll releases the queue and continues every paused download.
+func (a *App) ResumeAll() error {
+	a.mu.Lock()
+	var ids []string
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok && task.Status == statusPaused {
+			ids = append(ids, id)
+		}
+	}
+	a.mu.Unlock()
+	for _, id := range ids {
+		_ = a.UnpauseTask(id)
+	}
+	a.setQueuePaused(false)
+	return nil
+}
@@ -0,0 +1,653 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pipelineCommandTimeout stops a command step that hangs.
+const pipelineCommandTimeout = 10 * time.Minute
+
+const (
+	pipelineStepTranscode = "transcode"
+	pipelineStepMetadata  = "metadata"
+	pipelineStepCommand   = "command"
+	pipelineStepMove      = "move"
+)
+
+// PipelineStep is one post-processing action applied to a task's output.
+//
+// transcode: runs ffmpeg with Args as output options, writing a file with the
+// Target extension next to the original. The new file becomes the primary
+// output and the original stays tracked as a second one, unless RemoveSource
+// is set, in which case the original is moved to the trash.
+// metadata: writes Args ("key=value") as container tags without re-encoding.
+// command: runs Args as an external command, stopped after
+// pipelineCommandTimeout or by CancelTask.
+// move: moves the output into the Target directory. {title}, {name} and {url}
+// are made safe file names there and may not lead out of the directory
+// before them.
+//
+// Args and Target may use {path}, {dir}, {name}, {title} and {url}.
+type PipelineStep struct {
+	Kind   string   `json:"kind"`
+	Args   []string `json:"args"`
+	Target string   `json:"target"`
+	// RemoveSource trashes the original after a transcode step.
+	RemoveSource bool `json:"removeSource,omitempty"`
+}
+
+// Pipeline is a named, ordered list of post-processing steps.
+type Pipeline struct {
+	ID    string         `json:"id"`
+	Name  string         `json:"name"`
+	Steps []PipelineStep `json:"steps"`
+}
+
+// ListPipelines returns the configured post-processing pipelines.
+func (a *App) ListPipelines() ([]Pipeline, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]Pipeline, len(a.pipelines))
+	copy(out, a.pipelines)
+	return out, nil
+}
+
+// SavePipeline creates a pipeline, or replaces the one with the same id.
+func (a *App) SavePipeline(pipeline Pipeline) (Pipeline, error) {
+	pipeline.Name = strings.TrimSpace(pipeline.Name)
+	if pipeline.Name == "" {
+		return Pipeline{}, errors.New("pipeline name is required")
+	}
+	if len(pipeline.Steps) == 0 {
+		return Pipeline{}, errors.New("pipeline needs at least one step")
+	}
+	for _, step := range pipeline.Steps {
+		if err := validatePipelineStep(step); err != nil {
+			return Pipeline{}, err
+		}
+	}
+	if strings.TrimSpace(pipeline.ID) == "" {
+		pipeline.ID = newID()
+	}
+
+	a.mu.Lock()
+	replaced := false
+	for i := range a.pipelines {
+		if a.pipelines[i].ID == pipeline.ID {
+			a.pipelines[i] = pipeline
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		a.pipelines = append(a.pipelines, pipeline)
+	}
+	a.mu.Unlock()
+	a.savePipelines()
+	return pipeline, nil
+}
+
+// DeletePipeline removes a pipeline by id.
+func (a *App) DeletePipeline(id string) error {
+	a.mu.Lock()
+	index := -1
+	for i := range a.pipelines {
+		if a.pipelines[i].ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		a.mu.Unlock()
+		return errors.New("pipeline not found")
+	}
+	a.pipelines = append(a.pipelines[:index], a.pipelines[index+1:]...)
+	a.mu.Unlock()
+	a.savePipelines()
+	return nil
+}
+
+// RunPipeline runs a pipeline against a completed task in the background.
+// Progress is reported through task:update events on the task's stage.
+func (a *App) RunPipeline(taskID string, pipelineID string) error {
+	pipeline, ok := a.findPipeline(pipelineID)
+	if !ok {
+		return errors.New("pipeline not found")
+	}
+
+	a.mu.Lock()
+	task, ok := a.tasks[taskID]
+	if !ok {
+		a.mu.Unlock()
+		return errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return errors.New("task is deleted")
+	}
+	if task.Status != statusSuccess {
+		a.mu.Unlock()
+		return errors.New("task has not completed")
+	}
+	if !fileExists(task.OutputPath) {
+		a.mu.Unlock()
+		return errors.New("output file not available")
+	}
+	if a.pipelineTasks[taskID] {
+		a.mu.Unlock()
+		return errors.New("a pipeline is already running for this task")
+	}
+	a.pipelineTasks[taskID] = true
+	a.mu.Unlock()
+
+	go a.runPipeline(taskID, pipeline)
+	return nil
+}
+
+func (a *App) findPipeline(id string) (Pipeline, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, pipeline := range a.pipelines {
+		if pipeline.ID == id {
+			return pipeline, true
+		}
+	}
+	return Pipeline{}, false
+}
+
+func (a *App) runPipeline(taskID string, pipeline Pipeline) {
+	defer func() {
+		a.mu.Lock()
+		delete(a.pipelineTasks, taskID)
+		a.mu.Unlock()
+	}()
+
+	for i, step := range pipeline.Steps {
+		a.mu.Lock()
+		stopped := a.postProcessingStoppedLocked(taskID)
+		a.mu.Unlock()
+		if stopped {
+			a.stopPipeline(taskID)
+			return
+		}
+		params := map[string]string{
+			"pipeline": pipeline.Name,
+			"step":     strconv.Itoa(i + 1),
+			"total":    strconv.Itoa(len(pipeline.Steps)),
+			"kind":     step.Kind,
+		}
+		task, ok := a.setTaskStage(taskID, stagePipelineStep, params, "")
+		if !ok {
+			return
+		}
+		outputPath, err := a.runPipelineStep(taskID, task, step)
+		if errors.Is(err, errPostProcessingStopped) {
+			a.stopPipeline(taskID)
+			return
+		}
+		if err != nil {
+			params["detail"] = err.Error()
+			a.finishPipeline(taskID, pipeline.Name, &taskError{
+				Code:    errorPipelineFailed,
+				Params:  params,
+				Message: fmt.Sprintf("%s failed at step %d (%s): %s", pipeline.Name, i+1, step.Kind, err.Error()),
+			})
+			return
+		}
+		if outputPath != "" && outputPath != task.OutputPath {
+			a.mu.Lock()
+			if current, ok := a.tasks[taskID]; ok {
+				if step.Kind == pipelineStepTranscode && !step.RemoveSource {
+					current.prependOutput(outputPath)
+				} else {
+					current.replaceOutputPath(task.OutputPath, outputPath)
+				}
+			}
+			a.mu.Unlock()
+		}
+	}
+	a.finishPipeline(taskID, pipeline.Name, nil)
+}
+
+// setTaskStage updates a task's stage and progress and returns a snapshot.
+func (a *App) setTaskStage(id, stageCode string, stageParams map[string]string, progress string) (Task, bool) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, false
+	}
+	task.Progress = progress
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stageCode, stageParams, task.UpdatedAt)
+	updated := *task
+	a.mu.Unlock()
+	a.emitTaskUpdate(updated)
+	return updated, true
+}
+
+// finishPipeline closes a pipeline run. A nil failure marks it complete.
+func (a *App) finishPipeline(id, pipelineName string, failure *taskError) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	task.Progress = "100%"
+	task.UpdatedAt = time.Now()
+	if failure != nil {
+		task.setError(*failure)
+	} else {
+		task.clearError()
+		task.setStageCode(stagePipelineComplete, map[string]string{"pipeline": pipelineName}, task.UpdatedAt)
+	}
+	task.closeStage(task.UpdatedAt)
+	updated := *task
+	a.mu.Unlock()
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+}
+
+// stopPipeline closes the stage of a pipeline run that was stopped part way;
+// the steps already done are kept.
+func (a *App) stopPipeline(id string) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	task.Progress = "100%"
+	task.UpdatedAt = time.Now()
+	task.closeStage(task.UpdatedAt)
+	updated := *task
+	a.mu.Unlock()
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+}
+
+func validatePipelineStep(step PipelineStep) error {
+	switch step.Kind {
+	case pipelineStepTranscode:
+		if strings.TrimSpace(step.Target) == "" {
+			return errors.New("transcode step needs a target extension")
+		}
+	case pipelineStepMetadata:
+		for _, arg := range step.Args {
+			if !strings.Contains(arg, "=") {
+				return errors.New("metadata step args must be key=value")
+			}
+		}
+	case pipelineStepCommand:
+		if len(step.Args) == 0 {
+			return errors.New("command step needs a command")
+		}
+	case pipelineStepMove:
+		if strings.TrimSpace(step.Target) == "" {
+			return errors.New("move step needs a target directory")
+		}
+	default:
+		return fmt.Errorf("unknown pipeline step %q", step.Kind)
+	}
+	return nil
+}
+
+// runPipelineStep executes one step and returns the task's new output path.
+func (a *App) runPipelineStep(taskID string, task Task, step PipelineStep) (string, error) {
+	input := task.OutputPath
+	expand := func(value string) string {
+		return expandPipelinePlaceholders(value, task)
+	}
+	switch step.Kind {
+	case pipelineStepTranscode:
+		ext := strings.TrimPrefix(expand(step.Target), ".")
+		base := strings.TrimSuffix(input, filepath.Ext(input))
+		output := base + "." + ext
+		if output == input {
+			output = base + ".transcoded." + ext
+		}
+		output = uniquePath(output)
+		args := []string{"-y", "-i", input}
+		for _, arg := range step.Args {
+			args = append(args, expand(arg))
+		}
+		args = append(args, output)
+		if err := a.runFfmpegWithProgress(taskID, task.Duration, args); err != nil {
+			_ = os.Remove(output)
+			return "", err
+		}
+		if step.RemoveSource {
+			if err := a.moveToTrash(input); err != nil {
+				_ = os.Remove(output)
+				return "", err
+			}
+		}
+		return output, nil
+	case pipelineStepMetadata:
+		tmp := strings.TrimSuffix(input, filepath.Ext(input)) + ".tagging" + filepath.Ext(input)
+		args := []string{"-y", "-i", input, "-map", "0", "-c", "copy"}
+		for _, arg := range step.Args {
+			args = append(args, "-metadata", expand(arg))
+		}
+		args = append(args, tmp)
+		if err := a.runFfmpegWithProgress(taskID, task.Duration, args); err != nil {
+			_ = os.Remove(tmp)
+			return "", err
+		}
+		if err := os.Rename(tmp, input); err != nil {
+			_ = os.Remove(tmp)
+			return "", err
+		}
+		return input, nil
+	case pipelineStepCommand:
+		argv := make([]string, 0, len(step.Args))
+		for _, arg := range step.Args {
+			argv = append(argv, expand(arg))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), pipelineCommandTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+		configureProcessGroup(cmd)
+		cmd.Cancel = func() error { return killProcessTree(cmd) }
+		cmd.Env = append(os.Environ(), pipelineEnv(task)...)
+		var output strings.Builder
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		if err := cmd.Start(); err != nil {
+			return "", err
+		}
+		if !a.trackPostProcessing(taskID, cmd) {
+			return "", errPostProcessingStopped
+		}
+		err := cmd.Wait()
+		if a.untrackPostProcessing(taskID, cmd) {
+			return "", errPostProcessingStopped
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("command timed out after %s", pipelineCommandTimeout)
+		}
+		if err != nil {
+			return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(output.String()))
+		}
+		return input, nil
+	case pipelineStepMove:
+		targetDir, err := pipelineMoveTarget(step.Target, task)
+		if err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(targetDir, 0o755); err != nil {
+			return "", err
+		}
+		output := filepath.Join(targetDir, filepath.Base(input))
+		if err := moveFile(input, output); err != nil {
+			return "", err
+		}
+		return output, nil
+	}
+	return "", fmt.Errorf("unknown pipeline step %q", step.Kind)
+}
+
+func expandPipelinePlaceholders(value string, task Task) string {
+	replacer := strings.NewReplacer(
+		"{path}", task.OutputPath,
+		"{dir}", filepath.Dir(task.OutputPath),
+		"{name}", filepath.Base(task.OutputPath),
+		"{title}", task.Title,
+		"{url}", task.URL,
+	)
+	return replacer.Replace(value)
+}
+
+// pipelineMoveTarget expands a move step's target directory. Placeholders
+// that come from the download, {title}, {name} and {url}, are made safe file
+// names first, so a title such as "../x" cannot lead out of the folder the
+// target names before them.
+func pipelineMoveTarget(target string, task Task) (string, error) {
+	safe := task
+	safe.Title = sanitizeFilename(task.Title)
+	safe.URL = sanitizeFilename(task.URL)
+	expanded := strings.NewReplacer("{name}", sanitizeFilename(filepath.Base(task.OutputPath))).Replace(target)
+	expanded = filepath.Clean(expandPipelinePlaceholders(expanded, safe))
+
+	first := -1
+	for _, placeholder := range []string{"{title}", "{name}", "{url}"} {
+		if i := strings.Index(target, placeholder); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+	if first < 0 {
+		return expanded, nil
+	}
+	root := filepath.Dir(expandPipelinePlaceholders(target[:first], safe) + "x")
+	if rel, err := filepath.Rel(root, expanded); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.New("move target leaves " + root)
+	}
+	return expanded, nil
+}
+
+func pipelineEnv(task Task) []string {
+	return []string{
+		"FETCHFORGE_TASK_ID=" + task.ID,
+		"FETCHFORGE_OUTPUT_PATH=" + task.OutputPath,
+		"FETCHFORGE_TITLE=" + task.Title,
+		"FETCHFORGE_URL=" + task.URL,
+	}
+}
+
+// runFfmpegWithProgress runs ffmpeg and reports percent progress on the task
+// using the known media duration. The process is tracked in a.running like a
+// download, so canceling or deleting the task or quitting the app kills it;
+// errPostProcessingStopped is returned then.
+func (a *App) runFfmpegWithProgress(taskID string, duration int, args []string) error {
+	args = append([]string{"-hide_banner", "-nostats", "-progress", "pipe:1"}, args...)
+	cmd := a.ffmpegCommand(args...)
+	configureProcessGroup(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return errors.New("ffmpeg not found")
+	}
+	if !a.trackPostProcessing(taskID, cmd) {
+		return errPostProcessingStopped
+	}
+	a.readFfmpegProgress(taskID, duration, stdout)
+	err = cmd.Wait()
+	if a.untrackPostProcessing(taskID, cmd) {
+		return errPostProcessingStopped
+	}
+	if err != nil {
+		lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+		return fmt.Errorf("ffmpeg failed: %s", lines[len(lines)-1])
+	}
+	return nil
+}
+
+// trackPostProcessing registers a started post-processing process in
+// a.running, so canceling or deleting the task or quitting the app kills it.
+// When the run has already been stopped the process is killed instead and
+// false is returned.
+func (a *App) trackPostProcessing(taskID string, cmd *exec.Cmd) bool {
+	a.mu.Lock()
+	stopped := a.postProcessingStoppedLocked(taskID)
+	if !stopped {
+		a.running[taskID] = cmd
+	}
+	a.mu.Unlock()
+	if stopped {
+		_ = killProcessTree(cmd)
+		_ = cmd.Wait()
+		return false
+	}
+	return true
+}
+
+// untrackPostProcessing removes a finished process from a.running and
+// reports whether the run was stopped while it ran.
+func (a *App) untrackPostProcessing(taskID string, cmd *exec.Cmd) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.running[taskID] == cmd {
+		delete(a.running, taskID)
+	}
+	return a.postProcessingStoppedLocked(taskID)
+}
+
+// postProcessingStoppedLocked reports whether a transcode or pipeline run on
+// the task was canceled, the task was deleted or the app is quitting. The
+// caller must hold a.mu.
+func (a *App) postProcessingStoppedLocked(id string) bool {
+	task, ok := a.tasks[id]
+	return !ok || task.deleted() || a.shuttingDown || !a.pipelineTasks[id]
+}
+
+func (a *App) readFfmpegProgress(taskID string, duration int, reader io.Reader) {
+	scanner := bufio.NewScanner(reader)
+	lastPercent := ""
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || key != "out_time_us" || duration <= 0 {
+			continue
+		}
+		micros, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		percent := float64(micros) / float64(duration*1_000_000) * 100
+		if percent > 100 {
+			percent = 100
+		}
+		text := fmt.Sprintf("%.1f%%", percent)
+		if text == lastPercent {
+			continue
+		}
+		lastPercent = text
+		a.mu.Lock()
+		task, ok := a.tasks[taskID]
+		if !ok {
+			a.mu.Unlock()
+			return
+		}
+		task.Progress = text
+		task.UpdatedAt = time.Now()
+		updated := *task
+		a.mu.Unlock()
+		a.emitTaskUpdate(updated)
+		a.saveTasksSoon()
+	}
+}
+
+// moveFile renames src to dst, copying across devices when needed.
+func moveFile(src, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return errors.New("destination file already exists")
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func pipelinesFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "pipelines.json"), nil
+}
+
+func (a *App) loadPipelines() {
+	path, err := pipelinesFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var items []Pipeline
+	if err := json.Unmarshal(data, &items); err != nil {
+		return
+	}
+	a.mu.Lock()
+	a.pipelines = items
+	a.mu.Unlock()
+}
+
+func (a *App) savePipelines() {
+	path, err := pipelinesFilePath()
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	snapshot := make([]Pipeline, len(a.pipelines))
+	copy(snapshot, a.pipelines)
+	a.mu.Unlock()
+	writeJSONFile(path, snapshot)
+}
+
+// writeJSONFile atomically replaces path with the indented JSON of value. The
+// file is only readable by the user, as some, such as a restored config,
+// hold secrets.
+func writeJSONFile(path string, value interface{}) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return
+	}
+	if err := os.Chmod(tmpPath, 0o600); err != nil {
+		return
+	}
+	_ = os.Rename(tmpPath, path)
+}
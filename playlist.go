@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// ExpandPlaylist lists every entry of a playlist or channel URL. A URL that
+// is a single video returns an error.
+func (a *App) ExpandPlaylist(url string) ([]PreviewEntry, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return nil, errors.New("url is required")
+	}
+	if a.engineForURL(url) != engineYtDlp {
+		return nil, errors.New("url is not a playlist")
+	}
+	probe, err := a.probeURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if probe.Type != "playlist" && len(probe.Entries) == 0 {
+		return nil, errors.New("url is not a playlist")
+	}
+	return playlistEntries(probe.Entries, 0), nil
+}
+
+// CreatePlaylistTasks expands a playlist and queues one task per entry. The
+// tasks share a BatchID so the UI can group them.
+func (a *App) CreatePlaylistTasks(url string, outputDir string) ([]Task, error) {
+	outputDir, err := validateOutputDir(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := a.ExpandPlaylist(url)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return []Task{}, nil
+	}
+	return a.createTasks(entries, newTaskOptions{outputDir: outputDir, batchID: newID()}), nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// MediaPreferences are the preferred container and video codec for every
+// download. Empty fields leave the choice to yt-dlp.
+type MediaPreferences struct {
+	// Container is "mp4", "mkv" or "webm". Downloads are remuxed into it.
+	Container string `json:"container"`
+	// VideoCodec is "h264", "vp9" or "av1". Formats with that codec are
+	// preferred when the site offers them.
+	VideoCodec string `json:"videoCodec"`
+}
+
+var (
+	preferredContainers = []string{"mp4", "mkv", "webm"}
+	// preferredCodecSorts maps codec names to yt-dlp's vcodec sort values.
+	preferredCodecSorts = map[string]string{"h264": "h264", "vp9": "vp9", "av1": "av01"}
+	// containerSorts prefer formats that remux into the container without
+	// re-encoding; mkv holds anything.
+	containerSorts = map[string]string{"mp4": "ext:mp4:m4a", "webm": "ext:webm:webm"}
+)
+
+// GetMediaPreferences returns the preferred container and codec.
+func (a *App) GetMediaPreferences() (MediaPreferences, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.mediaPreferences, nil
+}
+
+// SetMediaPreferences sets the preferred container and codec. They apply to
+// every profile; a profile, host override or extra args that pass their own
+// -S or --remux-video take precedence.
+func (a *App) SetMediaPreferences(preferences MediaPreferences) (MediaPreferences, error) {
+	preferences, err := normalizeMediaPreferences(preferences)
+	if err != nil {
+		return MediaPreferences{}, err
+	}
+	a.mu.Lock()
+	a.mediaPreferences = preferences
+	a.mu.Unlock()
+	a.saveConfig()
+	return preferences, nil
+}
+
+func normalizeMediaPreferences(preferences MediaPreferences) (MediaPreferences, error) {
+	preferences.Container = strings.ToLower(strings.TrimSpace(preferences.Container))
+	preferences.VideoCodec = strings.ToLower(strings.TrimSpace(preferences.VideoCodec))
+	if preferences.Container != "" && !slices.Contains(preferredContainers, preferences.Container) {
+		return MediaPreferences{}, errors.New("container must be mp4, mkv or webm")
+	}
+	if _, ok := preferredCodecSorts[preferences.VideoCodec]; preferences.VideoCodec != "" && !ok {
+		return MediaPreferences{}, errors.New("video codec must be h264, vp9 or av1")
+	}
+	return preferences, nil
+}
+
+// mediaPreferenceArgs returns the -S and --remux-video args for the
+// preferences, leaving out whatever args already sets. Audio extraction
+// skips both.
+func (a *App) mediaPreferenceArgs(args []string) []string {
+	a.mu.Lock()
+	preferences := a.mediaPreferences
+	a.mu.Unlock()
+	if hasArg(args, "-x", "--extract-audio") {
+		return nil
+	}
+	var out []string
+	if !hasArg(args, "-S", "--format-sort") {
+		var sorts []string
+		if sort, ok := preferredCodecSorts[preferences.VideoCodec]; ok {
+			sorts = append(sorts, "vcodec:"+sort)
+		}
+		if sort, ok := containerSorts[preferences.Container]; ok {
+			sorts = append(sorts, sort)
+		}
+		if len(sorts) > 0 {
+			out = append(out, "-S", strings.Join(sorts, ","))
+		}
+	}
+	if preferences.Container != "" && !hasArg(args, "--remux-video", "--recode-video", "--merge-output-format") {
+		out = append(out, "--remux-video", preferences.Container)
+	}
+	return out
+}
+
+// hasArg reports whether args contain any of the flags, either alone or in
+// --flag=value form.
+func hasArg(args []string, flags ...string) bool {
+	for _, arg := range args {
+		for _, flag := range flags {
+			if arg == flag || strings.HasPrefix(arg, flag+"=") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TaskPreview is what a URL resolves to before any task is created, so the
+// UI can confirm the download and offer quality choices.
+type TaskPreview struct {
+	URL           string          `json:"url"`
+	Title         string          `json:"title"`
+	Uploader      string          `json:"uploader"`
+	SourceHost    string          `json:"sourceHost"`
+	Thumbnail     string          `json:"thumbnail"`
+	Duration      int             `json:"duration"`
+	Width         int             `json:"width"`
+	Height        int             `json:"height"`
+	IsPlaylist    bool            `json:"isPlaylist"`
+	PlaylistCount int             `json:"playlistCount"`
+	Entries       []PreviewEntry  `json:"entries"`
+	IsLive        bool            `json:"isLive"`
+	Engine        string          `json:"engine"`
+	Qualities     []QualityOption `json:"qualities"`
+}
+
+// PreviewEntry is one item of a playlist preview.
+type PreviewEntry struct {
+	URL      string `json:"url"`
+	Title    string `json:"title"`
+	Duration int    `json:"duration"`
+}
+
+// QualityOption is one selectable quality with its estimated download size.
+// Format is a yt-dlp -f selector for that choice.
+type QualityOption struct {
+	Label         string `json:"label"`
+	Height        int    `json:"height"`
+	AudioOnly     bool   `json:"audioOnly"`
+	EstimatedSize int64  `json:"estimatedSize"`
+	Format        string `json:"format"`
+}
+
+const maxPreviewEntries = 200
+
+type ytdlpProbe struct {
+	ytdlpMetadata
+	Type          string        `json:"_type"`
+	Uploader      string        `json:"uploader"`
+	Thumbnail     string        `json:"thumbnail"`
+	IsLive        bool          `json:"is_live"`
+	LiveStatus    string        `json:"live_status"`
+	PlaylistCount int           `json:"playlist_count"`
+	Entries       []ytdlpEntry  `json:"entries"`
+	Formats       []probeFormat `json:"formats"`
+}
+
+type ytdlpEntry struct {
+	URL        string   `json:"url"`
+	WebpageURL string   `json:"webpage_url"`
+	Title      string   `json:"title"`
+	Duration   *float64 `json:"duration"`
+}
+
+type probeFormat struct {
+	ytdlpFormat
+	VCodec string   `json:"vcodec"`
+	ACodec string   `json:"acodec"`
+	TBR    *float64 `json:"tbr"`
+}
+
+// PreviewTask probes a URL without creating a task.
+func (a *App) PreviewTask(url string) (TaskPreview, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return TaskPreview{}, errors.New("url is required")
+	}
+	preview := TaskPreview{URL: url, SourceHost: sourceHostFromURL(url), Engine: a.engineForURL(url)}
+	if preview.Engine == engineGalleryDl {
+		preview.Title = fetchPageTitle(url)
+		return preview, nil
+	}
+	if preview.Engine == engineDirect {
+		preview.Title = defaultTitleFromURL(url)
+		return preview, nil
+	}
+
+	probe, err := a.probeURL(url)
+	if err != nil {
+		return TaskPreview{}, err
+	}
+
+	preview.Title = strings.TrimSpace(probe.Title)
+	preview.Uploader = probe.Uploader
+	preview.Thumbnail = probe.Thumbnail
+	preview.Duration = floatToInt(probe.Duration)
+	preview.Width = floatToInt(probe.ytdlpMetadata.Width)
+	preview.Height = floatToInt(probe.ytdlpMetadata.Height)
+	preview.IsLive = probe.IsLive || probe.LiveStatus == "is_live"
+	if extractor := strings.TrimSpace(probe.Extractor); extractor != "" {
+		preview.SourceHost = extractor
+	}
+	if probe.Type == "playlist" || len(probe.Entries) > 0 {
+		preview.IsPlaylist = true
+		preview.PlaylistCount = probe.PlaylistCount
+		if preview.PlaylistCount == 0 {
+			preview.PlaylistCount = len(probe.Entries)
+		}
+		preview.Entries = playlistEntries(probe.Entries, maxPreviewEntries)
+		return preview, nil
+	}
+	preview.Qualities = qualityOptions(probe.Formats, preview.Duration)
+	return preview, nil
+}
+
+// probeURL runs a flat yt-dlp probe, which lists playlist entries without
+// resolving each one.
+func (a *App) probeURL(url string) (ytdlpProbe, error) {
+	args := []string{"--skip-download", "--no-warnings", "--flat-playlist", "-J"}
+	args = append(args, extraYtDlpArgs()...)
+	args = append(args, a.cookieArgs()...)
+	args = append(args, a.proxyArgs("")...)
+	args = append(args, url)
+	cmd := a.ytDlpCommand(args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return ytdlpProbe{}, errors.New(formatCommandError(err, cmd, stdout.String(), stderr.String()))
+	}
+	var probe ytdlpProbe
+	if err := json.Unmarshal(stdout.Bytes(), &probe); err != nil {
+		return ytdlpProbe{}, errors.New("could not read probe output")
+	}
+	return probe, nil
+}
+
+// playlistEntries converts flat playlist entries, keeping at most limit of
+// them when limit is positive.
+func playlistEntries(entries []ytdlpEntry, limit int) []PreviewEntry {
+	out := make([]PreviewEntry, 0, len(entries))
+	for _, entry := range entries {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		entryURL := entry.WebpageURL
+		if entryURL == "" {
+			entryURL = entry.URL
+		}
+		if entryURL == "" {
+			continue
+		}
+		out = append(out, PreviewEntry{
+			URL:      entryURL,
+			Title:    entry.Title,
+			Duration: floatToInt(entry.Duration),
+		})
+	}
+	return out
+}
+
+// qualityOptions groups formats by height and estimates the size of the best
+// video at each height merged with the best audio track.
+func qualityOptions(formats []probeFormat, duration int) []QualityOption {
+	estimate := func(format probeFormat) int64 {
+		if size := pickFilesize(format.Filesize, format.FilesizeApprox); size > 0 {
+			return size
+		}
+		if format.TBR != nil && duration > 0 {
+			return int64(*format.TBR * 1000 / 8 * float64(duration))
+		}
+		return 0
+	}
+
+	var bestAudio int64
+	videoByHeight := make(map[int]int64)
+	muxedByHeight := make(map[int]int64)
+	for _, format := range formats {
+		hasVideo := format.VCodec != "" && format.VCodec != "none"
+		hasAudio := format.ACodec != "" && format.ACodec != "none"
+		size := estimate(format)
+		height := floatToInt(format.Height)
+		if height == 0 && format.Resolution != "" {
+			_, height = parseResolution(format.Resolution)
+		}
+		switch {
+		case hasVideo && height > 0 && hasAudio:
+			if size > muxedByHeight[height] {
+				muxedByHeight[height] = size
+			}
+		case hasVideo && height > 0:
+			if size > videoByHeight[height] {
+				videoByHeight[height] = size
+			}
+		case hasAudio && !hasVideo:
+			if size > bestAudio {
+				bestAudio = size
+			}
+		}
+	}
+
+	heights := make(map[int]struct{})
+	for height := range videoByHeight {
+		heights[height] = struct{}{}
+	}
+	for height := range muxedByHeight {
+		heights[height] = struct{}{}
+	}
+	options := make([]QualityOption, 0, len(heights)+1)
+	for height := range heights {
+		size := muxedByHeight[height]
+		if video, ok := videoByHeight[height]; ok && video+bestAudio > size {
+			size = video + bestAudio
+		}
+		options = append(options, QualityOption{
+			Label:         fmt.Sprintf("%dp", height),
+			Height:        height,
+			EstimatedSize: size,
+			Format:        fmt.Sprintf("bv*[height<=%d]+ba/b[height<=%d]", height, height),
+		})
+	}
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Height > options[j].Height
+	})
+	if bestAudio > 0 || len(options) == 0 {
+		options = append(options, QualityOption{
+			Label:         "Audio only",
+			AudioOnly:     true,
+			EstimatedSize: bestAudio,
+			Format:        "ba/b",
+		})
+	}
+	return options
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+package main
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// configureProcessGroup starts cmd in a new process group and without a
+// console window.
+func configureProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// killProcessTree kills cmd and its child processes with taskkill /T.
+func killProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	kill.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if err := kill.Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
+
+// suspendProcessTree is not supported on Windows; paused downloads are
+// stopped instead and continue from their .part file.
+func suspendProcessTree(cmd *exec.Cmd) error {
+	return errSuspendUnsupported
+}
+
+func resumeProcessTree(cmd *exec.Cmd) error {
+	return nil
+}
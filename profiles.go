@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const defaultFilenameTemplate = "%(title)s.%(ext)s"
+
+// templateFieldPattern matches one yt-dlp template field such as %(title)s,
+// %(upload_date>%Y-%m-%d)s or %(playlist_index)03d.
+var templateFieldPattern = regexp.MustCompile(`^%\(([^()]+)\)[-#0+ ]*\d*(?:\.\d+)?[diouxXeEfFgGcrsaBlqDSUj]`)
+
+// GetFilenameTemplate returns the global output filename template. Empty
+// means the built-in default.
+func (a *App) GetFilenameTemplate() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.filenameTemplate, nil
+}
+
+// SetFilenameTemplate sets the output filename template used by profiles
+// without their own template.
+func (a *App) SetFilenameTemplate(template string) error {
+	template = strings.TrimSpace(template)
+	if err := validateFilenameTemplate(template); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.filenameTemplate = template
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// CreateProfile adds a custom profile with a new id.
+func (a *App) CreateProfile(profile Profile) (Profile, error) {
+	profile, err := normalizeProfile(profile)
+	if err != nil {
+		return Profile{}, err
+	}
+	profile.ID = newID()
+
+	a.mu.Lock()
+	a.customProfiles = append(a.customProfiles, profile)
+	a.mu.Unlock()
+	a.saveProfiles()
+	return profile, nil
+}
+
+// UpdateProfile replaces the custom profile with the same id. Built-in
+// profiles are read-only.
+func (a *App) UpdateProfile(profile Profile) (Profile, error) {
+	profile, err := normalizeProfile(profile)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	a.mu.Lock()
+	index, err := a.customProfileIndexLocked(profile.ID)
+	if err != nil {
+		a.mu.Unlock()
+		return Profile{}, err
+	}
+	a.customProfiles[index] = profile
+	a.mu.Unlock()
+	a.saveProfiles()
+	return profile, nil
+}
+
+// DuplicateProfile copies a built-in or custom profile into a new custom
+// profile that can then be edited.
+func (a *App) DuplicateProfile(id string) (Profile, error) {
+	a.mu.Lock()
+	source, ok := a.findProfileLocked(id)
+	if !ok {
+		a.mu.Unlock()
+		return Profile{}, errors.New("profile not found")
+	}
+	profile := source
+	profile.ID = newID()
+	profile.Name = source.Name + " (copy)"
+	profile.Builtin = false
+	profile.Args = append([]string{}, source.Args...)
+	profile.PostProcess = append([]PipelineStep(nil), source.PostProcess...)
+	profile.PostDownloadHook = append([]string(nil), source.PostDownloadHook...)
+	if source.Subtitles != nil {
+		subtitles := *source.Subtitles
+		subtitles.Languages = append([]string{}, source.Subtitles.Languages...)
+		profile.Subtitles = &subtitles
+	}
+	if source.Aria2 != nil {
+		aria2 := *source.Aria2
+		profile.Aria2 = &aria2
+	}
+	a.customProfiles = append(a.customProfiles, profile)
+	a.mu.Unlock()
+	a.saveProfiles()
+	return profile, nil
+}
+
+// DeleteProfile removes a custom profile. If it was active, the default
+// profile becomes active.
+func (a *App) DeleteProfile(id string) error {
+	a.mu.Lock()
+	index, err := a.customProfileIndexLocked(id)
+	if err != nil {
+		a.mu.Unlock()
+		return err
+	}
+	a.customProfiles = append(a.customProfiles[:index:index], a.customProfiles[index+1:]...)
+	wasActive := a.activeProfileID == id
+	if wasActive {
+		a.activeProfileID = defaultProfileID
+	}
+	a.mu.Unlock()
+	a.saveProfiles()
+	if wasActive {
+		a.saveConfig()
+	}
+	return nil
+}
+
+// customProfileIndexLocked finds a custom profile, reporting built-in ids as
+// read-only. The caller must hold a.mu.
+func (a *App) customProfileIndexLocked(id string) (int, error) {
+	for _, builtin := range builtinProfiles() {
+		if builtin.ID == id {
+			return -1, errors.New("built-in profiles are read-only")
+		}
+	}
+	for i := range a.customProfiles {
+		if a.customProfiles[i].ID == id {
+			return i, nil
+		}
+	}
+	return -1, errors.New("profile not found")
+}
+
+// normalizeProfile validates and cleans user input for a custom profile.
+func normalizeProfile(profile Profile) (Profile, error) {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return Profile{}, errors.New("profile name is required")
+	}
+	outputDir, err := validateOutputDir(profile.OutputDir)
+	if err != nil {
+		return Profile{}, err
+	}
+	profile.OutputDir = outputDir
+	profile.FilenameTemplate = strings.TrimSpace(profile.FilenameTemplate)
+	if err := validateFilenameTemplate(profile.FilenameTemplate); err != nil {
+		return Profile{}, err
+	}
+	for _, step := range profile.PostProcess {
+		if err := validatePipelineStep(step); err != nil {
+			return Profile{}, err
+		}
+	}
+	subtitles, err := normalizeSubtitleOptions(profile.Subtitles)
+	if err != nil {
+		return Profile{}, err
+	}
+	profile.Subtitles = subtitles
+	hook, err := normalizeHookCommand(profile.PostDownloadHook)
+	if err != nil {
+		return Profile{}, err
+	}
+	profile.PostDownloadHook = hook
+	if profile.TranscodePreset != "" {
+		if _, ok := findTranscodePreset(profile.TranscodePreset); !ok {
+			return Profile{}, errors.New("unknown transcode preset")
+		}
+	}
+	if profile.Aria2 != nil {
+		aria2, err := normalizeAria2Settings(*profile.Aria2)
+		if err != nil {
+			return Profile{}, err
+		}
+		profile.Aria2 = &aria2
+	}
+	if profile.Args == nil {
+		profile.Args = []string{}
+	}
+	profile.Builtin = false
+	return profile, nil
+}
+
+func profilesFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "profiles.json"), nil
+}
+
+func (a *App) loadProfiles() {
+	path, err := profilesFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var items []Profile
+	if err := json.Unmarshal(data, &items); err != nil {
+		return
+	}
+	for i := range items {
+		items[i].Builtin = false
+	}
+	a.mu.Lock()
+	a.customProfiles = items
+	a.mu.Unlock()
+}
+
+func (a *App) saveProfiles() {
+	path, err := profilesFilePath()
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	snapshot := make([]Profile, len(a.customProfiles))
+	copy(snapshot, a.customProfiles)
+	a.mu.Unlock()
+	writeJSONFile(path, snapshot)
+}
+
+// validateFilenameTemplate checks a yt-dlp output template. Templates are
+// relative to the output folder and may create subfolders inside it.
+func validateFilenameTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	if filepath.IsAbs(template) {
+		return errors.New("filename template must be relative")
+	}
+	for _, part := range strings.FieldsFunc(template, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return errors.New("filename template cannot leave the output directory")
+		}
+	}
+	if !strings.Contains(template, "%(ext)s") {
+		return errors.New("filename template must include %(ext)s")
+	}
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' {
+			continue
+		}
+		if i+1 < len(template) && template[i+1] == '%' {
+			i++
+			continue
+		}
+		field := templateFieldPattern.FindString(template[i:])
+		if field == "" {
+			return fmt.Errorf("invalid template field at %q; use %%(name)s or %%%% for a literal %%", template[i:])
+		}
+		i += len(field) - 1
+	}
+	return nil
+}
+
+// filenameTemplateForLocked picks the profile's template, then the global
+// one, then the default. The caller must hold a.mu.
+func (a *App) filenameTemplateForLocked(profile Profile) string {
+	if profile.FilenameTemplate != "" {
+		return profile.FilenameTemplate
+	}
+	if a.filenameTemplate != "" {
+		return a.filenameTemplate
+	}
+	return defaultFilenameTemplate
+}
+
+// taskProfile returns the profile a task was created with, falling back to
+// the active profile when it is unset or has been deleted.
+func (a *App) taskProfile(profileID string) Profile {
+	if profileID != "" {
+		a.mu.Lock()
+		profile, ok := a.findProfileLocked(profileID)
+		a.mu.Unlock()
+		if ok {
+			return profile
+		}
+	}
+	profile, _ := a.getActiveProfile()
+	return profile
+}
+
+// runProfilePostProcess runs a profile's post-processing chain on a freshly
+// downloaded task, reusing the pipeline runner.
+func (a *App) runProfilePostProcess(id string, profile Profile) {
+	a.mu.Lock()
+	if a.pipelineTasks[id] {
+		a.mu.Unlock()
+		return
+	}
+	a.pipelineTasks[id] = true
+	a.mu.Unlock()
+	a.runPipeline(id, Pipeline{ID: profile.ID, Name: profile.Name, Steps: profile.PostProcess})
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	speedWindow          = 5 * time.Second
+	queueSummaryInterval = 2 * time.Second
+)
+
+// progressTemplate is passed to yt-dlp so each progress line carries both the
+// display strings and the raw byte counters used for smoothing.
+const progressTemplate = "progress:%(progress._percent_str)s|%(progress._speed_str)s|%(progress._eta_str)s|%(progress.downloaded_bytes)s|%(progress.total_bytes)s|%(progress.total_bytes_estimate)s|%(progress.speed)s"
+
+// QueueSummary is an aggregate view of the whole download queue. Counts has
+// an entry for every status code. Percent is the combined progress of the
+// running downloads with a known size, for a single global progress bar.
+type QueueSummary struct {
+	Paused          bool           `json:"paused"`
+	Counts          map[string]int `json:"counts"`
+	Queued          int            `json:"queued"`
+	Scheduled       int            `json:"scheduled"`
+	Running         int            `json:"running"`
+	RemainingBytes  int64          `json:"remainingBytes"`
+	Percent         float64        `json:"percent"`
+	Speed           float64        `json:"speed"`
+	SpeedText       string         `json:"speedText"`
+	ETASeconds      int64          `json:"etaSeconds"`
+	ETAText         string         `json:"etaText"`
+	EstimatedFinish time.Time      `json:"estimatedFinish"`
+}
+
+type speedSample struct {
+	at    time.Time
+	speed float64
+}
+
+// speedEstimator keeps a short window of raw speed samples and reports their
+// average, which is far steadier than the per-line value yt-dlp prints.
+type speedEstimator struct {
+	samples []speedSample
+}
+
+func (e *speedEstimator) add(at time.Time, speed float64) {
+	e.samples = append(e.samples, speedSample{at: at, speed: speed})
+	cutoff := at.Add(-speedWindow)
+	drop := 0
+	for drop < len(e.samples)-1 && e.samples[drop].at.Before(cutoff) {
+		drop++
+	}
+	e.samples = e.samples[drop:]
+}
+
+func (e *speedEstimator) average() float64 {
+	if len(e.samples) == 0 {
+		return 0
+	}
+	var total float64
+	for _, sample := range e.samples {
+		total += sample.speed
+	}
+	return total / float64(len(e.samples))
+}
+
+type progressUpdate struct {
+	percent    string
+	speed      string
+	eta        string
+	downloaded int64
+	total      int64
+	rawSpeed   float64
+}
+
+func parseProgressLine(progress string) progressUpdate {
+	parts := strings.Split(progress, "|")
+	field := func(i int) string {
+		if i < len(parts) {
+			return strings.TrimSpace(parts[i])
+		}
+		return ""
+	}
+	update := progressUpdate{
+		percent:    field(0),
+		speed:      field(1),
+		eta:        field(2),
+		downloaded: parseProgressInt(field(3)),
+		total:      parseProgressInt(field(4)),
+		rawSpeed:   parseProgressFloat(field(6)),
+	}
+	if update.total == 0 {
+		update.total = parseProgressInt(field(5))
+	}
+	return update
+}
+
+func parseProgressInt(value string) int64 {
+	return int64(parseProgressFloat(value))
+}
+
+func parseProgressFloat(value string) float64 {
+	if value == "" || value == "NA" || value == "None" {
+		return 0
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) || parsed < 0 {
+		return 0
+	}
+	return parsed
+}
+
+func formatSpeed(bytesPerSecond float64) string {
+	if bytesPerSecond <= 0 {
+		return ""
+	}
+	return formatBytes(int64(bytesPerSecond)) + "/s"
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	value := float64(size)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB"}
+	suffix := ""
+	for _, s := range suffixes {
+		value /= unit
+		suffix = s
+		if value < unit {
+			break
+		}
+	}
+	return fmt.Sprintf("%.2f%s", value, suffix)
+}
+
+func formatETA(seconds int64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	hours := seconds / 3600
+	minutes := (seconds % 3600) / 60
+	secs := seconds % 60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, secs)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, secs)
+}
+
+// smoothProgress feeds a progress sample into the task's estimator and returns
+// the speed and ETA strings to display. Callers must hold a.mu.
+func (a *App) smoothProgress(id string, update progressUpdate) (string, string) {
+	if update.rawSpeed <= 0 {
+		return update.speed, update.eta
+	}
+	estimator, ok := a.estimators[id]
+	if !ok {
+		estimator = &speedEstimator{}
+		a.estimators[id] = estimator
+	}
+	estimator.add(time.Now(), update.rawSpeed)
+	speed := estimator.average()
+	eta := update.eta
+	if update.total > 0 && update.downloaded <= update.total && speed > 0 {
+		eta = formatETA(int64(math.Ceil(float64(update.total-update.downloaded) / speed)))
+	}
+	return formatSpeed(speed), eta
+}
+
+// GetQueueSummary returns the current aggregate queue state.
+func (a *App) GetQueueSummary() (QueueSummary, error) {
+	return a.queueSummary(), nil
+}
+
+func (a *App) queueSummary() QueueSummary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	summary := QueueSummary{Paused: a.queuePaused, Counts: make(map[string]int, len(statusCodes))}
+	for _, code := range statusCodes {
+		summary.Counts[code] = 0
+	}
+	var downloaded, size int64
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok || task.deleted() {
+			continue
+		}
+		summary.Counts[statusCodeFor(task.Status)]++
+		switch task.Status {
+		case statusQueued:
+			if task.scheduledLater(time.Now()) {
+				summary.Scheduled++
+				continue
+			}
+			summary.Queued++
+			summary.RemainingBytes += task.Filesize
+		case statusRunning:
+			summary.Running++
+			total := task.TotalBytes
+			if total == 0 {
+				total = task.Filesize
+			}
+			if remaining := total - task.DownloadedBytes; remaining > 0 {
+				summary.RemainingBytes += remaining
+			}
+			if total > 0 {
+				downloaded += min(task.DownloadedBytes, total)
+				size += total
+			}
+			if estimator, ok := a.estimators[id]; ok {
+				summary.Speed += estimator.average()
+			}
+		}
+	}
+	if size > 0 {
+		summary.Percent = math.Round(float64(downloaded)/float64(size)*1000) / 10
+	}
+	summary.SpeedText = formatSpeed(summary.Speed)
+	if summary.Speed > 0 && summary.RemainingBytes > 0 {
+		summary.ETASeconds = int64(math.Ceil(float64(summary.RemainingBytes) / summary.Speed))
+		summary.ETAText = formatETA(summary.ETASeconds)
+		summary.EstimatedFinish = time.Now().Add(time.Duration(summary.ETASeconds) * time.Second)
+	}
+	return summary
+}
+
+func (a *App) queueSummaryLoop() {
+	ticker := time.NewTicker(queueSummaryInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.emitQueueSummary()
+	}
+}
+
+func (a *App) emitQueueSummary() {
+	summary := a.queueSummary()
+	a.publishQueueSummary(summary)
+	a.remoteHub.broadcast("queue:summary", summary)
+	if a.ctx == nil {
+		return
+	}
+	wailsruntime.EventsEmit(a.ctx, "queue:summary", summary)
+}
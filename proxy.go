@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var proxySchemes = map[string]bool{
+	"http": true, "https": true, "socks4": true, "socks4a": true, "socks5": true, "socks5h": true,
+}
+
+// GetProxy returns the global proxy URL. Empty means a direct connection.
+func (a *App) GetProxy() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.proxy, nil
+}
+
+// SetProxy sets the proxy used for every download, e.g.
+// "http://proxy:8080" or "socks5://127.0.0.1:1080". Empty disables it.
+func (a *App) SetProxy(proxy string) error {
+	proxy, err := validateProxy(proxy)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.proxy = proxy
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// SetTaskProxy overrides the global proxy for one task. Empty goes back to
+// the global proxy.
+func (a *App) SetTaskProxy(id string, proxy string) (Task, error) {
+	proxy, err := validateProxy(proxy)
+	if err != nil {
+		return Task{}, err
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	task.Proxy = proxy
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
+
+// proxyArgs returns the --proxy argument for a task's proxy, falling back to
+// the global proxy.
+func (a *App) proxyArgs(taskProxy string) []string {
+	proxy := taskProxy
+	if proxy == "" {
+		a.mu.Lock()
+		proxy = a.proxy
+		a.mu.Unlock()
+	}
+	if proxy == "" {
+		return nil
+	}
+	return []string{"--proxy", proxy}
+}
+
+func validateProxy(proxy string) (string, error) {
+	proxy = strings.TrimSpace(proxy)
+	if proxy == "" {
+		return "", nil
+	}
+	parsed, err := url.Parse(proxy)
+	if err != nil || parsed.Host == "" {
+		return "", errors.New("invalid proxy url")
+	}
+	if !proxySchemes[strings.ToLower(parsed.Scheme)] {
+		return "", errors.New("proxy must use http, https, socks4, socks4a, socks5 or socks5h")
+	}
+	return proxy, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const maxQueryLimit = 500
+
+// TaskFilter selects and orders tasks for QueryTasks. Empty fields match
+// everything. Statuses and Hosts match any listed value; Hosts include
+// subdomains. BatchID keeps the tasks of one batch. Since and Until bound
+// CreatedAt. Sort is "created" (default),
+// "updated", "title" or "size"; Descending reverses it. Limit defaults to 100
+// and is capped at 500.
+type TaskFilter struct {
+	Statuses   []string  `json:"statuses"`
+	Hosts      []string  `json:"hosts"`
+	BatchID    string    `json:"batchId"`
+	Since      time.Time `json:"since"`
+	Until      time.Time `json:"until"`
+	Search     string    `json:"search"`
+	Sort       string    `json:"sort"`
+	Descending bool      `json:"descending"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+}
+
+// TaskQueryResult is one page of QueryTasks. Total counts every match before
+// Limit and Offset apply.
+type TaskQueryResult struct {
+	Tasks  []Task `json:"tasks"`
+	Total  int    `json:"total"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// QueryTasks filters, sorts and pages the task list so the UI does not need
+// to load every task.
+func (a *App) QueryTasks(filter TaskFilter) (TaskQueryResult, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > maxQueryLimit {
+		limit = maxQueryLimit
+	}
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	statuses := make(map[string]bool, len(filter.Statuses))
+	for _, status := range filter.Statuses {
+		if code := statusCodeFor(strings.TrimSpace(status)); code != "" {
+			statuses[code] = true
+		}
+	}
+	var hosts []string
+	for _, host := range filter.Hosts {
+		if host = normalizeOverrideHost(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	search := strings.ToLower(strings.TrimSpace(filter.Search))
+
+	a.mu.Lock()
+	matches := make([]Task, 0)
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok || task.deleted() {
+			continue
+		}
+		if len(statuses) > 0 && !statuses[statusCodeFor(task.Status)] {
+			continue
+		}
+		if len(hosts) > 0 && !hostMatchesAny(normalizeOverrideHost(task.SourceHost), hosts) {
+			continue
+		}
+		if filter.BatchID != "" && task.BatchID != filter.BatchID {
+			continue
+		}
+		if !filter.Since.IsZero() && task.CreatedAt.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && !task.CreatedAt.Before(filter.Until) {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(task.Title), search) &&
+			!strings.Contains(strings.ToLower(task.URL), search) {
+			continue
+		}
+		matches = append(matches, *task)
+	}
+	a.mu.Unlock()
+
+	less := taskSortLess(filter.Sort)
+	sort.SliceStable(matches, func(i, j int) bool {
+		if filter.Descending {
+			return less(matches[j], matches[i])
+		}
+		return less(matches[i], matches[j])
+	})
+
+	result := TaskQueryResult{Tasks: []Task{}, Total: len(matches), Offset: offset, Limit: limit}
+	if offset < len(matches) {
+		end := offset + limit
+		if end > len(matches) {
+			end = len(matches)
+		}
+		result.Tasks = matches[offset:end]
+	}
+	return result, nil
+}
+
+func taskSortLess(field string) func(a, b Task) bool {
+	switch strings.ToLower(strings.TrimSpace(field)) {
+	case "updated":
+		return func(a, b Task) bool { return a.UpdatedAt.Before(b.UpdatedAt) }
+	case "title":
+		return func(a, b Task) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	case "size":
+		return func(a, b Task) bool { return taskSize(a) < taskSize(b) }
+	}
+	return func(a, b Task) bool { return a.CreatedAt.Before(b.CreatedAt) }
+}
+
+func taskSize(task Task) int64 {
+	if task.TotalBytes > 0 {
+		return task.TotalBytes
+	}
+	return task.Filesize
+}
+
+func hostMatchesAny(host string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if host == candidate || strings.HasSuffix(host, "."+candidate) {
+			return true
+		}
+	}
+	return false
+}
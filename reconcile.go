@@ -0,0 +1,380 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// UnreferencedFile is a file in the download or library folder that no task
+// lists as an output. SuggestedTaskID names a task whose missing output has
+// the same name and size, i.e. the file it was probably moved from.
+type UnreferencedFile struct {
+	Path            string    `json:"path"`
+	Size            int64     `json:"size"`
+	ModifiedAt      time.Time `json:"modifiedAt"`
+	SuggestedTaskID string    `json:"suggestedTaskId,omitempty"`
+	SuggestedPath   string    `json:"suggestedPath,omitempty"`
+}
+
+// MissingOutputTask is a finished task with output files that are gone.
+type MissingOutputTask struct {
+	TaskID       string   `json:"taskId"`
+	Title        string   `json:"title"`
+	MissingPaths []string `json:"missingPaths"`
+}
+
+// ReconcileReport compares the download tree with the task list.
+type ReconcileReport struct {
+	UnreferencedFiles []UnreferencedFile  `json:"unreferencedFiles"`
+	MissingOutputs    []MissingOutputTask `json:"missingOutputs"`
+}
+
+// ReconcileOutputs lists files under the download and library folders that
+// no task references, archived ones in the history included, and finished
+// tasks whose files have vanished. Partial
+// files are left to ScanPartialFiles. Each unreferenced file can then be
+// adopted into a new task, relinked to a task that lost it, or deleted.
+func (a *App) ReconcileOutputs() (ReconcileReport, error) {
+	report := ReconcileReport{UnreferencedFiles: []UnreferencedFile{}, MissingOutputs: []MissingOutputTask{}}
+	roots, err := a.reconcileRoots()
+	if err != nil {
+		return report, err
+	}
+
+	a.mu.Lock()
+	ids := make([]string, 0, len(a.order))
+	outputs := make(map[string][]TaskOutput, len(a.order))
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok {
+			ids = append(ids, id)
+			outputs[id] = append([]TaskOutput(nil), task.Outputs...)
+		}
+	}
+	a.mu.Unlock()
+
+	referenced := make(map[string]bool)
+	// missingByName finds the task a moved file came from by its file name
+	// and size.
+	type missingOutput struct {
+		taskID string
+		path   string
+		size   int64
+	}
+	missingByName := make(map[string][]missingOutput)
+	refreshed := make(map[string][]TaskOutput)
+	for _, id := range ids {
+		next, changed := refreshOutputs(outputs[id])
+		if changed {
+			refreshed[id] = next
+		}
+		for _, output := range next {
+			referenced[filepath.Clean(output.Path)] = true
+			if output.Missing {
+				name := filepath.Base(output.Path)
+				missingByName[name] = append(missingByName[name], missingOutput{taskID: id, path: output.Path, size: output.Size})
+			}
+		}
+	}
+
+	a.mu.Lock()
+	for id, next := range refreshed {
+		if task, ok := a.tasks[id]; ok {
+			task.setOutputs(next)
+		}
+	}
+	for _, id := range ids {
+		task, ok := a.tasks[id]
+		if !ok || !task.MissingOutput {
+			continue
+		}
+		entry := MissingOutputTask{TaskID: id, Title: task.Title, MissingPaths: []string{}}
+		for _, output := range task.Outputs {
+			if output.Missing {
+				entry.MissingPaths = append(entry.MissingPaths, output.Path)
+			}
+		}
+		report.MissingOutputs = append(report.MissingOutputs, entry)
+	}
+	a.mu.Unlock()
+	if len(refreshed) > 0 {
+		a.saveTasks()
+	}
+	if err := a.addHistoryOutputs(referenced); err != nil {
+		return report, err
+	}
+
+	for _, root := range roots {
+		_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || isPartialFile(d.Name()) || referenced[path] {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			file := UnreferencedFile{Path: path, Size: info.Size(), ModifiedAt: info.ModTime()}
+			for _, candidate := range missingByName[d.Name()] {
+				if candidate.size == 0 || candidate.size == info.Size() {
+					file.SuggestedTaskID = candidate.taskID
+					file.SuggestedPath = candidate.path
+					break
+				}
+			}
+			report.UnreferencedFiles = append(report.UnreferencedFiles, file)
+			return nil
+		})
+	}
+	return report, nil
+}
+
+// AdoptOutputFile creates a finished task for an unreferenced file so it
+// shows up in the history and library like any other download.
+func (a *App) AdoptOutputFile(path string) (Task, error) {
+	path, err := a.checkUnreferencedFile(path)
+	if err != nil {
+		return Task{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Task{}, err
+	}
+	now := time.Now()
+	title := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	fileURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	task := &Task{
+		ID:         newID(),
+		URL:        fileURL,
+		Title:      title,
+		OutputDir:  filepath.Dir(path),
+		Engine:     engineDirect,
+		Status:     statusSuccess,
+		StatusCode: statusCodeFor(statusSuccess),
+		Stage:      stageLabels[stageFinalize],
+		StageCode:  stageFinalize,
+		Progress:   "100%",
+		CreatedAt:  info.ModTime(),
+		UpdatedAt:  now,
+	}
+	task.setOutputs([]TaskOutput{statOutput(path, outputKindForPath(path))})
+
+	a.mu.Lock()
+	a.tasks[task.ID] = task
+	a.order = append(a.order, task.ID)
+	created := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(created)
+	a.saveTasks()
+	return created, nil
+}
+
+// RelinkMissingOutput points a task's missing output at missingPath to the
+// file at newPath, after the file was moved outside FetchForge.
+func (a *App) RelinkMissingOutput(id string, missingPath string, newPath string) (Task, error) {
+	return a.relinkOutput(id, missingPath, newPath)
+}
+
+// RelinkTaskOutput points a task's main output at newPath after the file was
+// moved. Empty newPath shows a file picker opened in the old file's folder;
+// cancelling it leaves the task unchanged. The new file must be the same kind
+// of file, e.g. a video for a video download.
+func (a *App) RelinkTaskOutput(id string, newPath string) (Task, error) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is deleted")
+	}
+	current := *task
+	a.mu.Unlock()
+	if current.OutputPath == "" {
+		return Task{}, errors.New("task has no output file")
+	}
+
+	if strings.TrimSpace(newPath) == "" {
+		if a.ctx == nil {
+			return Task{}, errors.New("app not ready")
+		}
+		ext := filepath.Ext(current.OutputPath)
+		options := wailsruntime.OpenDialogOptions{
+			Title:            "Locate " + filepath.Base(current.OutputPath),
+			DefaultDirectory: existingDir(filepath.Dir(current.OutputPath)),
+		}
+		if ext != "" {
+			options.Filters = []wailsruntime.FileFilter{{DisplayName: strings.ToUpper(ext[1:]) + " files", Pattern: "*" + ext}}
+		}
+		picked, err := wailsruntime.OpenFileDialog(a.ctx, options)
+		if err != nil {
+			return Task{}, err
+		}
+		if picked == "" {
+			return current, nil
+		}
+		newPath = picked
+	}
+	if kind := outputKindForPath(current.OutputPath); kind != outputKindOther && outputKindForPath(newPath) != kind {
+		return Task{}, errors.New("file is not a " + kind + " file")
+	}
+	return a.relinkOutput(id, current.OutputPath, newPath)
+}
+
+// existingDir returns dir, or its nearest existing parent, so a dialog can
+// open near a folder that was moved or deleted.
+func existingDir(dir string) string {
+	for dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return ""
+}
+
+// DeleteUnreferencedFile moves a file that no task references to the trash.
+func (a *App) DeleteUnreferencedFile(path string) error {
+	path, err := a.checkUnreferencedFile(path)
+	if err != nil {
+		return err
+	}
+	return a.moveToTrash(path)
+}
+
+// relinkOutput replaces the output at oldPath with newPath. The new file
+// must exist and must not belong to another task.
+func (a *App) relinkOutput(id, oldPath, newPath string) (Task, error) {
+	newPath = strings.TrimSpace(newPath)
+	if newPath == "" || !filepath.IsAbs(newPath) {
+		return Task{}, errors.New("file path must be absolute")
+	}
+	newPath = filepath.Clean(newPath)
+	info, err := os.Stat(newPath)
+	if err != nil {
+		return Task{}, errors.New("file not found")
+	}
+	if info.IsDir() {
+		return Task{}, errors.New("path is a directory")
+	}
+	if isPartialFile(filepath.Base(newPath)) {
+		return Task{}, errors.New("file is an unfinished download")
+	}
+
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is deleted")
+	}
+	if task.Status != statusSuccess {
+		a.mu.Unlock()
+		return Task{}, errors.New("task has not finished downloading")
+	}
+	if !slices.Contains(task.outputPaths(), oldPath) {
+		a.mu.Unlock()
+		return Task{}, errors.New("task has no output at " + oldPath)
+	}
+	if owner := a.outputOwnerLocked(newPath); owner != "" && owner != id {
+		a.mu.Unlock()
+		return Task{}, errors.New("file belongs to another task")
+	}
+	task.replaceOutputPath(oldPath, newPath)
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
+
+// checkUnreferencedFile checks that path is a file inside the download or
+// library folder that no task lists as an output.
+func (a *App) checkUnreferencedFile(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" || !filepath.IsAbs(path) {
+		return "", errors.New("file path must be absolute")
+	}
+	path = filepath.Clean(path)
+	roots, err := a.reconcileRoots()
+	if err != nil {
+		return "", err
+	}
+	inside := false
+	for _, root := range roots {
+		if rel, err := filepath.Rel(root, path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			inside = true
+			break
+		}
+	}
+	if !inside {
+		return "", errors.New("file is outside the download and library folders")
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", errors.New("file not found")
+	}
+	a.mu.Lock()
+	owner := a.outputOwnerLocked(path)
+	a.mu.Unlock()
+	if owner != "" {
+		return "", errors.New("file belongs to a task")
+	}
+	archived := make(map[string]bool)
+	if err := a.addHistoryOutputs(archived); err != nil {
+		return "", err
+	}
+	if archived[path] {
+		return "", errors.New("file belongs to an archived task")
+	}
+	return path, nil
+}
+
+// outputOwnerLocked returns the id of the task that lists path as an output.
+// The caller must hold a.mu.
+func (a *App) outputOwnerLocked(path string) string {
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok {
+			continue
+		}
+		for _, output := range task.outputPaths() {
+			if filepath.Clean(output) == path {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+func (a *App) reconcileRoots() ([]string, error) {
+	root, err := downloadRoot()
+	if err != nil {
+		return nil, err
+	}
+	roots := []string{filepath.Clean(root)}
+	a.mu.Lock()
+	library := a.libraryDir
+	a.mu.Unlock()
+	if library != "" {
+		roots = append(roots, filepath.Clean(library))
+	}
+	return roots, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// recoveryFallback is a known yt-dlp workaround that is retried automatically
+// when a download fails with one of its signatures.
+type recoveryFallback struct {
+	ID         string
+	Name       string
+	Args       []string
+	Signatures []*regexp.Regexp
+}
+
+// HostFallback reports the workaround remembered for a source host.
+type HostFallback struct {
+	Host       string `json:"host"`
+	FallbackID string `json:"fallbackId"`
+	Name       string `json:"name"`
+}
+
+// recoveryFallbacks is ordered by preference: cheaper workarounds first.
+var recoveryFallbacks = []recoveryFallback{
+	{
+		ID:   "player-client",
+		Name: "Alternate player client",
+		Args: []string{"--extractor-args", "youtube:player_client=default,web_safari,android_vr"},
+		Signatures: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)HTTP Error 403`),
+			regexp.MustCompile(`(?i)Sign in to confirm you.re not a bot`),
+			regexp.MustCompile(`(?i)Failed to extract any player response`),
+			regexp.MustCompile(`(?i)Requested format is not available`),
+		},
+	},
+	{
+		ID:   "force-ipv4",
+		Name: "Force IPv4",
+		Args: []string{"--force-ipv4"},
+		Signatures: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)HTTP Error 403`),
+			regexp.MustCompile(`(?i)Unable to download webpage`),
+			regexp.MustCompile(`(?i)Connection (reset|refused|aborted)`),
+			regexp.MustCompile(`(?i)timed out`),
+			regexp.MustCompile(`(?i)Network is unreachable`),
+		},
+	},
+	{
+		ID:   "impersonate",
+		Name: "Browser impersonation",
+		Args: []string{"--impersonate", "chrome"},
+		Signatures: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)HTTP Error 403`),
+			regexp.MustCompile(`(?i)impersonat`),
+			regexp.MustCompile(`(?i)cloudflare`),
+			regexp.MustCompile(`(?i)HTTP Error 503`),
+		},
+	},
+}
+
+func findRecoveryFallback(id string) (recoveryFallback, bool) {
+	for _, fallback := range recoveryFallbacks {
+		if fallback.ID == id {
+			return fallback, true
+		}
+	}
+	return recoveryFallback{}, false
+}
+
+// nextRecoveryFallback picks the first untried workaround whose signature
+// appears in the failed command's output.
+func nextRecoveryFallback(output string, tried map[string]bool) (recoveryFallback, bool) {
+	for _, fallback := range recoveryFallbacks {
+		if tried[fallback.ID] {
+			continue
+		}
+		for _, signature := range fallback.Signatures {
+			if signature.MatchString(output) {
+				return fallback, true
+			}
+		}
+	}
+	return recoveryFallback{}, false
+}
+
+func (a *App) knownFallback(host string) (recoveryFallback, bool) {
+	if host == "" {
+		return recoveryFallback{}, false
+	}
+	a.mu.Lock()
+	id := a.hostFallbacks[host]
+	a.mu.Unlock()
+	if id == "" {
+		return recoveryFallback{}, false
+	}
+	return findRecoveryFallback(id)
+}
+
+func (a *App) rememberFallback(host, fallbackID string) {
+	if host == "" {
+		return
+	}
+	a.mu.Lock()
+	if a.hostFallbacks[host] == fallbackID {
+		a.mu.Unlock()
+		return
+	}
+	a.hostFallbacks[host] = fallbackID
+	a.mu.Unlock()
+	a.saveConfig()
+}
+
+// ListHostFallbacks returns the workarounds learned for each source host.
+func (a *App) ListHostFallbacks() ([]HostFallback, error) {
+	a.mu.Lock()
+	out := make([]HostFallback, 0, len(a.hostFallbacks))
+	for host, id := range a.hostFallbacks {
+		item := HostFallback{Host: host, FallbackID: id}
+		if fallback, ok := findRecoveryFallback(id); ok {
+			item.Name = fallback.Name
+		}
+		out = append(out, item)
+	}
+	a.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Host < out[j].Host })
+	return out, nil
+}
+
+// ClearHostFallback forgets the workaround remembered for a host.
+func (a *App) ClearHostFallback(host string) error {
+	host = strings.TrimSpace(host)
+	a.mu.Lock()
+	if _, ok := a.hostFallbacks[host]; !ok {
+		a.mu.Unlock()
+		return errors.New("host fallback not found")
+	}
+	delete(a.hostFallbacks, host)
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
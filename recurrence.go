@@ -0,0 +1,381 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	recurrenceCheckInterval = 30 * time.Second
+	minRecurrenceInterval   = 5
+)
+
+// Recurrence re-downloads a URL on a schedule, such as a daily news stream or
+// a page whose content changes. It runs every IntervalMinutes, or at the
+// times matched by Cron, a five-field crontab expression (minute hour
+// day-of-month month day-of-week) in local time. Exactly one of the two is
+// set. Disabled recurrences keep their settings but do not run. Each run
+// creates a new task tagged with the recurrence's id, whose files carry the
+// run's date so earlier runs are not overwritten.
+type Recurrence struct {
+	ID              string    `json:"id"`
+	URL             string    `json:"url"`
+	Title           string    `json:"title"`
+	IntervalMinutes int       `json:"intervalMinutes"`
+	Cron            string    `json:"cron"`
+	ProfileID       string    `json:"profileId"`
+	OutputDir       string    `json:"outputDir"`
+	Enabled         bool      `json:"enabled"`
+	NextRunAt       time.Time `json:"nextRunAt"`
+	LastRunAt       time.Time `json:"lastRunAt"`
+	LastTaskID      string    `json:"lastTaskId"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// ListRecurrences returns the recurring downloads.
+func (a *App) ListRecurrences() ([]Recurrence, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]Recurrence, len(a.recurrences))
+	copy(out, a.recurrences)
+	return out, nil
+}
+
+// SaveRecurrence creates a recurring download, or replaces the one with the
+// same id. The next run is worked out from the current time.
+func (a *App) SaveRecurrence(recurrence Recurrence) (Recurrence, error) {
+	recurrence, err := normalizeRecurrence(recurrence)
+	if err != nil {
+		return Recurrence{}, err
+	}
+	now := time.Now()
+	recurrence.NextRunAt, err = recurrence.nextRun(now)
+	if err != nil {
+		return Recurrence{}, err
+	}
+
+	a.mu.Lock()
+	if recurrence.ProfileID != "" {
+		if _, ok := a.findProfileLocked(recurrence.ProfileID); !ok {
+			a.mu.Unlock()
+			return Recurrence{}, errors.New("profile not found")
+		}
+	}
+	replaced := false
+	for i := range a.recurrences {
+		if a.recurrences[i].ID == recurrence.ID {
+			recurrence.LastRunAt = a.recurrences[i].LastRunAt
+			recurrence.LastTaskID = a.recurrences[i].LastTaskID
+			recurrence.CreatedAt = a.recurrences[i].CreatedAt
+			a.recurrences[i] = recurrence
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		recurrence.ID = newID()
+		recurrence.LastRunAt = time.Time{}
+		recurrence.LastTaskID = ""
+		recurrence.CreatedAt = now
+		a.recurrences = append(a.recurrences, recurrence)
+	}
+	a.mu.Unlock()
+	a.saveRecurrences()
+	return recurrence, nil
+}
+
+// DeleteRecurrence removes a recurring download. Tasks it already created
+// are kept.
+func (a *App) DeleteRecurrence(id string) error {
+	a.mu.Lock()
+	index := -1
+	for i := range a.recurrences {
+		if a.recurrences[i].ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		a.mu.Unlock()
+		return errors.New("recurrence not found")
+	}
+	a.recurrences = append(a.recurrences[:index:index], a.recurrences[index+1:]...)
+	a.mu.Unlock()
+	a.saveRecurrences()
+	return nil
+}
+
+// RecurrenceHistory returns the tasks a recurrence has created, newest
+// first.
+func (a *App) RecurrenceHistory(id string) ([]Task, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := []Task{}
+	for i := len(a.order) - 1; i >= 0; i-- {
+		if task, ok := a.tasks[a.order[i]]; ok && task.RecurrenceID == id {
+			out = append(out, *task)
+		}
+	}
+	return out, nil
+}
+
+// normalizeRecurrence validates and cleans user input for a recurrence.
+func normalizeRecurrence(recurrence Recurrence) (Recurrence, error) {
+	urls := extractURLs(recurrence.URL)
+	if len(urls) != 1 {
+		return Recurrence{}, errors.New("enter exactly one URL")
+	}
+	recurrence.URL = urls[0]
+	recurrence.Title = strings.TrimSpace(recurrence.Title)
+	if recurrence.Title == "" {
+		recurrence.Title = defaultTitleFromURL(recurrence.URL)
+	}
+	recurrence.Cron = strings.Join(strings.Fields(recurrence.Cron), " ")
+	switch {
+	case recurrence.Cron != "" && recurrence.IntervalMinutes != 0:
+		return Recurrence{}, errors.New("set either an interval or a cron expression, not both")
+	case recurrence.Cron != "":
+		if _, err := parseCron(recurrence.Cron); err != nil {
+			return Recurrence{}, err
+		}
+	case recurrence.IntervalMinutes < minRecurrenceInterval:
+		return Recurrence{}, fmt.Errorf("interval must be at least %d minutes", minRecurrenceInterval)
+	}
+	outputDir, err := validateOutputDir(recurrence.OutputDir)
+	if err != nil {
+		return Recurrence{}, err
+	}
+	recurrence.OutputDir = outputDir
+	recurrence.ProfileID = strings.TrimSpace(recurrence.ProfileID)
+	return recurrence, nil
+}
+
+// nextRun returns the first run time after now.
+func (r Recurrence) nextRun(now time.Time) (time.Time, error) {
+	if r.Cron == "" {
+		return now.Add(time.Duration(r.IntervalMinutes) * time.Minute), nil
+	}
+	schedule, err := parseCron(r.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.next(now)
+}
+
+// recurrenceLoop starts the recurrences whose next run time has passed.
+// Runs missed while the app was closed are made up once, not once per
+// missed slot.
+func (a *App) recurrenceLoop() {
+	ticker := time.NewTicker(recurrenceCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		var due []Recurrence
+		a.mu.Lock()
+		for i := range a.recurrences {
+			recurrence := &a.recurrences[i]
+			if !recurrence.Enabled || recurrence.NextRunAt.After(now) {
+				continue
+			}
+			due = append(due, *recurrence)
+			next, err := recurrence.nextRun(now)
+			if err != nil {
+				recurrence.Enabled = false
+				continue
+			}
+			recurrence.NextRunAt = next
+		}
+		a.mu.Unlock()
+		if len(due) == 0 {
+			continue
+		}
+		for _, recurrence := range due {
+			created := a.createTasks([]PreviewEntry{{
+				URL:   recurrence.URL,
+				Title: recurrence.Title + " " + now.Format("2006-01-02 15:04"),
+			}}, newTaskOptions{
+				outputDir:    recurrence.OutputDir,
+				profileID:    recurrence.ProfileID,
+				recurrenceID: recurrence.ID,
+			})
+			a.mu.Lock()
+			for i := range a.recurrences {
+				if a.recurrences[i].ID == recurrence.ID {
+					a.recurrences[i].LastRunAt = now
+					a.recurrences[i].LastTaskID = created[0].ID
+				}
+			}
+			a.mu.Unlock()
+		}
+		a.saveRecurrences()
+	}
+}
+
+// recurrenceFilenameTemplate stamps a recurring task's files with the run
+// time so each run keeps its own copy.
+func recurrenceFilenameTemplate(template string, runAt time.Time) string {
+	suffix := " " + runAt.Format("2006-01-02 1504")
+	if base, ok := strings.CutSuffix(template, ".%(ext)s"); ok {
+		return base + suffix + ".%(ext)s"
+	}
+	return template + suffix
+}
+
+// cronSchedule holds the allowed values of each crontab field.
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays [64]bool
+	// anyDay and anyWeekday record a "*" day-of-month or day-of-week; when
+	// both are restricted a time matches either one, as in cron.
+	anyDay, anyWeekday bool
+}
+
+// parseCron parses a five-field crontab expression. Fields accept *, numbers,
+// ranges (1-5), lists (1,15) and steps (*/10, 0-30/5); day-of-week 7 is
+// Sunday like 0.
+func parseCron(expr string) (cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSchedule{}, errors.New("cron expression needs five fields: minute hour day month weekday")
+	}
+	var schedule cronSchedule
+	limits := []struct {
+		name     string
+		min, max int
+		set      *[64]bool
+	}{
+		{"minute", 0, 59, &schedule.minutes},
+		{"hour", 0, 23, &schedule.hours},
+		{"day", 1, 31, &schedule.days},
+		{"month", 1, 12, &schedule.months},
+		{"weekday", 0, 7, &schedule.weekdays},
+	}
+	for i, limit := range limits {
+		if err := parseCronField(fields[i], limit.min, limit.max, limit.set); err != nil {
+			return cronSchedule{}, fmt.Errorf("invalid cron %s field %q", limit.name, fields[i])
+		}
+	}
+	if schedule.weekdays[7] {
+		schedule.weekdays[0] = true
+	}
+	schedule.anyDay = strings.HasPrefix(fields[2], "*")
+	schedule.anyWeekday = strings.HasPrefix(fields[4], "*")
+	return schedule, nil
+}
+
+func parseCronField(field string, min, max int, set *[64]bool) error {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return errors.New("invalid step")
+			}
+			step = n
+		}
+		low, high := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = strconv.Atoi(from); err != nil {
+				return err
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(to); err != nil {
+					return err
+				}
+			} else if hasStep {
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return errors.New("value out of range")
+		}
+		for v := low; v <= high; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+// next returns the first matching minute after now, searching up to five
+// years ahead so that expressions such as "0 0 30 2 *" fail instead of
+// looping forever.
+func (s cronSchedule) next(now time.Time) (time.Time, error) {
+	t := now.Truncate(time.Minute).Add(time.Minute)
+	limit := now.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !s.months[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !s.hours[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !s.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("cron expression never matches")
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	day, weekday := s.days[t.Day()], s.weekdays[int(t.Weekday())]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+func recurrencesFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "recurrences.json"), nil
+}
+
+func (a *App) loadRecurrences() {
+	path, err := recurrencesFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var items []Recurrence
+	if err := json.Unmarshal(data, &items); err != nil {
+		return
+	}
+	a.mu.Lock()
+	a.recurrences = items
+	a.mu.Unlock()
+}
+
+func (a *App) saveRecurrences() {
+	path, err := recurrencesFilePath()
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	snapshot := make([]Recurrence, len(a.recurrences))
+	copy(snapshot, a.recurrences)
+	a.mu.Unlock()
+	writeJSONFile(path, snapshot)
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultRemotePort = 8765
+	remoteWriteWait   = 10 * time.Second
+	remotePingPeriod  = 30 * time.Second
+	remoteClientQueue = 64
+)
+
+//go:embed remote.html
+var remotePage []byte
+
+// RemoteConfig controls remote mode: the control API served on Port on every
+// network interface, so a phone on the same network can watch and add
+// downloads. Requests need Token, as a bearer token or a token query
+// parameter.
+type RemoteConfig struct {
+	Enabled bool   `json:"enabled"`
+	Port    int    `json:"port"`
+	Token   string `json:"token"`
+}
+
+// RemoteStatus reports whether the remote server is listening and the URLs it
+// can be reached at.
+type RemoteStatus struct {
+	Running bool     `json:"running"`
+	URLs    []string `json:"urls"`
+	Error   string   `json:"error"`
+}
+
+// remoteEvent is one message on the /v1/events WebSocket.
+type remoteEvent struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data"`
+}
+
+// remoteHub fans task and queue events out to connected WebSocket clients.
+// A client that falls behind is dropped rather than slowing the app down.
+type remoteHub struct {
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+}
+
+func (h *remoteHub) add() chan []byte {
+	client := make(chan []byte, remoteClientQueue)
+	h.mu.Lock()
+	if h.clients == nil {
+		h.clients = make(map[chan []byte]struct{})
+	}
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+	return client
+}
+
+func (h *remoteHub) remove(client chan []byte) {
+	h.mu.Lock()
+	if _, ok := h.clients[client]; ok {
+		delete(h.clients, client)
+		close(client)
+	}
+	h.mu.Unlock()
+}
+
+// closeAll disconnects every client, as hijacked WebSocket connections
+// outlive http.Server.Shutdown.
+func (h *remoteHub) closeAll() {
+	h.mu.Lock()
+	for client := range h.clients {
+		delete(h.clients, client)
+		close(client)
+	}
+	h.mu.Unlock()
+}
+
+func (h *remoteHub) broadcast(event string, data interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.clients) == 0 {
+		return
+	}
+	payload, err := json.Marshal(remoteEvent{Event: event, Data: data})
+	if err != nil {
+		return
+	}
+	for client := range h.clients {
+		select {
+		case client <- payload:
+		default:
+			delete(h.clients, client)
+			close(client)
+		}
+	}
+}
+
+var remoteUpgrader = websocket.Upgrader{ReadBufferSize: 1024, WriteBufferSize: 4096}
+
+// GetRemoteConfig returns the remote mode settings.
+func (a *App) GetRemoteConfig() (RemoteConfig, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.remoteConfig, nil
+}
+
+// SetRemoteConfig saves the remote mode settings and restarts the server. A
+// port of 0 uses the default, and an empty token is replaced with a new
+// random one.
+func (a *App) SetRemoteConfig(config RemoteConfig) (RemoteConfig, error) {
+	if config.Port == 0 {
+		config.Port = defaultRemotePort
+	}
+	if config.Port < 1024 || config.Port > 65535 {
+		return RemoteConfig{}, errors.New("port must be between 1024 and 65535")
+	}
+	if config.Token == "" {
+		config.Token = newID() + newID()
+	}
+	if len(config.Token) < 16 {
+		return RemoteConfig{}, errors.New("token must be at least 16 characters")
+	}
+	a.mu.Lock()
+	a.remoteConfig = config
+	a.mu.Unlock()
+	a.saveConfig()
+	a.stopRemoteServer()
+	if config.Enabled {
+		if err := a.startRemoteServer(); err != nil {
+			return config, err
+		}
+	}
+	return config, nil
+}
+
+// GetRemoteStatus reports whether the remote server is running and lists the
+// addresses to open on another device.
+func (a *App) GetRemoteStatus() (RemoteStatus, error) {
+	a.mu.Lock()
+	running := a.remoteServer != nil
+	port := a.remoteConfig.Port
+	lastErr := a.remoteError
+	a.mu.Unlock()
+	status := RemoteStatus{Running: running, URLs: []string{}, Error: lastErr}
+	if !running {
+		return status, nil
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return status, nil
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+			continue
+		}
+		status.URLs = append(status.URLs, "http://"+net.JoinHostPort(ipNet.IP.String(), strconv.Itoa(port))+"/")
+	}
+	return status, nil
+}
+
+// startRemoteServer listens on the configured port on all interfaces.
+func (a *App) startRemoteServer() error {
+	a.mu.Lock()
+	config := a.remoteConfig
+	a.mu.Unlock()
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(config.Port))
+	if err != nil {
+		a.mu.Lock()
+		a.remoteError = err.Error()
+		a.mu.Unlock()
+		return fmt.Errorf("could not listen on port %d: %w", config.Port, err)
+	}
+
+	api := requireToken(config.Token, a.controlRoutes(true))
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(remotePage)
+	})
+	mux.Handle("GET /v1/events", requireToken(config.Token, http.HandlerFunc(a.serveRemoteEvents)))
+	mux.Handle("/v1/", api)
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	a.mu.Lock()
+	a.remoteServer = server
+	a.remoteError = ""
+	a.mu.Unlock()
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return nil
+}
+
+func (a *App) stopRemoteServer() {
+	a.mu.Lock()
+	server := a.remoteServer
+	a.remoteServer = nil
+	a.mu.Unlock()
+	if server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = server.Shutdown(ctx)
+	a.remoteHub.closeAll()
+}
+
+// serveRemoteEvents mirrors task:update, tasks:removed and queue:summary to a
+// WebSocket.
+func (a *App) serveRemoteEvents(w http.ResponseWriter, r *http.Request) {
+	conn, err := remoteUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	client := a.remoteHub.add()
+	defer a.remoteHub.remove(client)
+
+	// The client sends nothing; reading only notices when it goes away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(remotePingPeriod)
+	defer ping.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case payload, ok := <-client:
+			if !ok {
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(remoteWriteWait))
+			if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(remoteWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxHeights are the resolution ceilings offered in settings; 2160 is 4K.
+var maxHeights = []int{480, 720, 1080, 1440, 2160}
+
+// GetMaxHeight returns the resolution ceiling in pixels, or 0 for none.
+func (a *App) GetMaxHeight() (int, error) {
+	return a.currentMaxHeight(), nil
+}
+
+// SetMaxHeight caps the video height of every download, e.g. 1080 to never
+// fetch 1440p or 4K. The cap is applied on top of the profile's format
+// selector; a format picked for a single task is left alone. 0 removes it.
+func (a *App) SetMaxHeight(height int) error {
+	if height != 0 && !slices.Contains(maxHeights, height) {
+		return errors.New("max height must be 0, 480, 720, 1080, 1440 or 2160")
+	}
+	a.mu.Lock()
+	a.maxHeight = height
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+func (a *App) currentMaxHeight() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.maxHeight
+}
+
+// capFormatArgs limits the format selector in args to maxHeight. The last
+// -f wins in yt-dlp, so that one is wrapped in a group filtered by height;
+// formats without a height, such as audio, still match. Without a selector
+// yt-dlp's default is capped instead, unless audio is being extracted.
+func capFormatArgs(args []string, maxHeight int) []string {
+	if maxHeight <= 0 {
+		return args
+	}
+	filter := "[height<=?" + strconv.Itoa(maxHeight) + "]"
+	out := append([]string(nil), args...)
+	for i := len(out) - 1; i >= 0; i-- {
+		switch {
+		case (out[i] == "-f" || out[i] == "--format") && i+1 < len(out):
+			out[i+1] = "(" + out[i+1] + ")" + filter
+			return out
+		case strings.HasPrefix(out[i], "--format="):
+			out[i] = "--format=(" + strings.TrimPrefix(out[i], "--format=") + ")" + filter
+			return out
+		}
+	}
+	if hasArg(out, "-x", "--extract-audio") {
+		return out
+	}
+	return append(out, "-f", "bv*"+filter+"+ba/b"+filter)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries       = 3
+	defaultRetryBaseSeconds = 10
+	maxRetryDelay           = 10 * time.Minute
+)
+
+// RetryPolicy controls automatic retries of failed downloads. Each retry
+// waits twice as long as the previous one, starting at BaseDelaySeconds.
+type RetryPolicy struct {
+	MaxRetries       int `json:"maxRetries"`
+	BaseDelaySeconds int `json:"baseDelaySeconds"`
+}
+
+func defaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxRetries: defaultMaxRetries, BaseDelaySeconds: defaultRetryBaseSeconds}
+}
+
+// GetRetryPolicy returns the automatic retry settings.
+func (a *App) GetRetryPolicy() (RetryPolicy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.retryPolicy, nil
+}
+
+// SetRetryPolicy changes the automatic retry settings. A MaxRetries of 0
+// turns automatic retries off.
+func (a *App) SetRetryPolicy(policy RetryPolicy) error {
+	if policy.MaxRetries < 0 || policy.MaxRetries > 10 {
+		return errors.New("max retries must be between 0 and 10")
+	}
+	if policy.BaseDelaySeconds < 1 || policy.BaseDelaySeconds > 3600 {
+		return errors.New("retry delay must be between 1 and 3600 seconds")
+	}
+	a.mu.Lock()
+	a.retryPolicy = policy
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// retryableError reports whether a failure is likely transient. Failures that
+// a retry cannot fix, such as a removed video, fail immediately.
+func retryableError(code string) bool {
+	switch code {
+	case errorNetwork, errorRateLimited, errorForbidden, errorCommandFailed:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns the wait before the given attempt, starting at 1.
+func retryDelay(policy RetryPolicy, attempt int) time.Duration {
+	delay := time.Duration(policy.BaseDelaySeconds) * time.Second
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+// scheduleRetryLocked puts a failed task back in the queue after a backoff
+// delay if it has retries left. The caller must hold a.mu.
+func (a *App) scheduleRetryLocked(task *Task, failure taskError) bool {
+	if !retryableError(failure.Code) || task.RetryCount >= task.MaxRetries {
+		return false
+	}
+	task.RetryCount++
+	task.setStatus(statusQueued)
+	task.setError(failure)
+	task.Resume = true
+	task.Speed = ""
+	task.ETA = ""
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stageRetryWait, map[string]string{
+		"attempt": strconv.Itoa(task.RetryCount),
+		"max":     strconv.Itoa(task.MaxRetries),
+	}, task.UpdatedAt)
+
+	id := task.ID
+	time.AfterFunc(retryDelay(a.retryPolicy, task.RetryCount), func() {
+		a.mu.Lock()
+		current, ok := a.tasks[id]
+		waiting := ok && current.Status == statusQueued && current.StageCode == stageRetryWait
+		a.mu.Unlock()
+		if waiting {
+			a.enqueueTasks([]string{id})
+		}
+	})
+	return true
+}
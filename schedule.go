@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+const scheduleCheckInterval = 15 * time.Second
+
+// ScheduleTask holds a task back until at. Queued, failed and canceled tasks
+// can be scheduled; failed and canceled ones continue from their partial
+// files when they start.
+func (a *App) ScheduleTask(id string, at time.Time) (Task, error) {
+	if at.IsZero() || !at.After(time.Now()) {
+		return Task{}, errors.New("scheduled time must be in the future")
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is deleted")
+	}
+	switch task.Status {
+	case statusRunning, statusPaused, statusSuccess:
+		a.mu.Unlock()
+		return Task{}, errors.New("task has already started downloading")
+	case statusFailed, statusCanceled:
+		requeueTaskLocked(task, stageScheduled, true)
+	}
+	scheduled := at
+	task.ScheduledAt = &scheduled
+	task.UpdatedAt = time.Now()
+	task.setStageCode(stageScheduled, map[string]string{
+		"time": at.Local().Format("2006-01-02 15:04"),
+	}, task.UpdatedAt)
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	a.emitQueueSummary()
+	return updated, nil
+}
+
+// ClearSchedule drops a task's start time so it runs with the rest of the
+// queue.
+func (a *App) ClearSchedule(id string) (Task, error) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.ScheduledAt == nil {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is not scheduled")
+	}
+	task.ScheduledAt = nil
+	task.UpdatedAt = time.Now()
+	queued := task.Status == statusQueued
+	if queued {
+		task.setStageCode(stageParseURL, nil, task.UpdatedAt)
+	}
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	a.emitQueueSummary()
+	if queued {
+		a.enqueueTasks([]string{id})
+	}
+	return updated, nil
+}
+
+// scheduledLater reports whether the task is waiting for a start time that
+// has not come yet.
+func (t *Task) scheduledLater(now time.Time) bool {
+	return t.ScheduledAt != nil && t.ScheduledAt.After(now)
+}
+
+// scheduleLoop queues scheduled tasks once their start time passes. Tasks
+// are matched by their stored time, so schedules survive a restart.
+func (a *App) scheduleLoop() {
+	ticker := time.NewTicker(scheduleCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		var due []string
+		var updated []Task
+		a.mu.Lock()
+		for _, id := range a.order {
+			task, ok := a.tasks[id]
+			if !ok || task.ScheduledAt == nil || task.scheduledLater(now) {
+				continue
+			}
+			task.ScheduledAt = nil
+			task.UpdatedAt = now
+			if task.Status == statusQueued {
+				due = append(due, id)
+			}
+			updated = append(updated, *task)
+		}
+		a.mu.Unlock()
+		if len(updated) == 0 {
+			continue
+		}
+		for _, task := range updated {
+			a.emitTaskUpdate(task)
+		}
+		a.saveTasks()
+		a.enqueueTasks(due)
+		a.emitQueueSummary()
+	}
+}
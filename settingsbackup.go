@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const settingsBackupVersion = 1
+
+// SettingsBackup bundles everything needed to set FetchForge up on another
+// machine: config.json (which holds the organize, gallery-dl, bandwidth and
+// host rules), custom profiles, pipelines, task templates, subscriptions and
+// recurring downloads. Tasks are not
+// included. Site logins are left out because their passwords live in the
+// system keychain, and so is the remote access token.
+type SettingsBackup struct {
+	Version       int            `json:"version"`
+	ExportedAt    time.Time      `json:"exportedAt"`
+	Config        appConfig      `json:"config"`
+	Profiles      []Profile      `json:"profiles"`
+	Pipelines     []Pipeline     `json:"pipelines"`
+	Templates     []TaskTemplate `json:"templates"`
+	Subscriptions []Subscription `json:"subscriptions"`
+	Recurrences   []Recurrence   `json:"recurrences"`
+}
+
+// ExportSettings returns the current settings as one JSON document.
+func (a *App) ExportSettings() (string, error) {
+	backup := SettingsBackup{
+		Version:    settingsBackupVersion,
+		ExportedAt: time.Now(),
+		Config:     a.configSnapshot(),
+	}
+	backup.Config.SiteCredentials = nil
+	backup.Config.Remote.Token = ""
+	a.mu.Lock()
+	backup.Profiles = append([]Profile{}, a.customProfiles...)
+	backup.Pipelines = append([]Pipeline{}, a.pipelines...)
+	backup.Templates = append([]TaskTemplate{}, a.taskTemplates...)
+	backup.Subscriptions = append([]Subscription{}, a.subscriptions...)
+	backup.Recurrences = append([]Recurrence{}, a.recurrences...)
+	a.mu.Unlock()
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ImportSettings replaces the current settings with an ExportSettings
+// document. Existing tasks and site logins are kept.
+func (a *App) ImportSettings(data string) error {
+	var backup SettingsBackup
+	if err := json.Unmarshal([]byte(data), &backup); err != nil {
+		return errors.New("not a FetchForge settings backup")
+	}
+	if backup.Version < 1 || backup.Version > settingsBackupVersion {
+		return fmt.Errorf("unsupported settings backup version %d", backup.Version)
+	}
+	profiles := make([]Profile, 0, len(backup.Profiles))
+	for _, profile := range backup.Profiles {
+		id := profile.ID
+		normalized, err := normalizeProfile(profile)
+		if err != nil {
+			return fmt.Errorf("profile %q: %w", profile.Name, err)
+		}
+		if id == "" {
+			id = newID()
+		}
+		normalized.ID = id
+		profiles = append(profiles, normalized)
+	}
+	for _, pipeline := range backup.Pipelines {
+		for _, step := range pipeline.Steps {
+			if err := validatePipelineStep(step); err != nil {
+				return fmt.Errorf("pipeline %q: %w", pipeline.Name, err)
+			}
+		}
+	}
+	templates := make([]TaskTemplate, 0, len(backup.Templates))
+	for _, template := range backup.Templates {
+		id := template.ID
+		normalized, err := normalizeTaskTemplate(template)
+		if err != nil {
+			return fmt.Errorf("template %q: %w", template.Name, err)
+		}
+		if id == "" {
+			id = newID()
+		}
+		normalized.ID = id
+		templates = append(templates, normalized)
+	}
+	for i := range backup.Subscriptions {
+		if backup.Subscriptions[i].Kind == "" {
+			backup.Subscriptions[i].Kind = subscriptionKindPlaylist
+		}
+		if backup.Subscriptions[i].Seen == nil {
+			backup.Subscriptions[i].Seen = []string{}
+		}
+	}
+	now := time.Now()
+	recurrences := make([]Recurrence, 0, len(backup.Recurrences))
+	for _, recurrence := range backup.Recurrences {
+		normalized, err := normalizeRecurrence(recurrence)
+		if err != nil {
+			return fmt.Errorf("recurrence %q: %w", recurrence.Title, err)
+		}
+		if normalized.ID == "" {
+			normalized.ID = newID()
+		}
+		if normalized.NextRunAt, err = normalized.nextRun(now); err != nil {
+			return fmt.Errorf("recurrence %q: %w", recurrence.Title, err)
+		}
+		recurrences = append(recurrences, normalized)
+	}
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	backup.Config.SiteCredentials = a.siteCredentials
+	backup.Config.Remote.Token = a.remoteConfig.Token
+	a.customProfiles = profiles
+	a.pipelines = backup.Pipelines
+	a.taskTemplates = templates
+	a.subscriptions = backup.Subscriptions
+	a.recurrences = recurrences
+	a.mu.Unlock()
+	a.saveProfiles()
+	a.savePipelines()
+	a.saveTaskTemplates()
+	a.saveSubscriptions()
+	a.saveRecurrences()
+	// loadConfig applies the same validation as a config.json read at launch.
+	writeJSONFile(path, backup.Config)
+	a.loadConfig()
+	a.saveConfig()
+
+	select {
+	case a.mqttReconnect <- struct{}{}:
+	default:
+	}
+	a.stopRemoteServer()
+	a.mu.Lock()
+	remoteEnabled := a.remoteConfig.Enabled
+	a.mu.Unlock()
+	if remoteEnabled {
+		_ = a.startRemoteServer()
+	}
+	a.emitQueueSummary()
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const maxSnapshots = 20
+
+// Snapshot describes a saved copy of the task store taken before a
+// destructive operation.
+type Snapshot struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Reason    string    `json:"reason"`
+	TaskCount int       `json:"taskCount"`
+}
+
+type snapshotFile struct {
+	Snapshot
+	Tasks []Task `json:"tasks"`
+}
+
+func snapshotsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "snapshots"), nil
+}
+
+// snapshotTasks saves the current task store so the change that follows can
+// be undone. Only the newest maxSnapshots are kept.
+func (a *App) snapshotTasks(reason string) {
+	dir, err := snapshotsDir()
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	tasks := make([]Task, 0, len(a.order))
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok {
+			tasks = append(tasks, *task)
+		}
+	}
+	a.mu.Unlock()
+	if len(tasks) == 0 {
+		return
+	}
+
+	now := time.Now()
+	snapshot := snapshotFile{
+		Snapshot: Snapshot{
+			ID:        now.Format("20060102-150405.000000"),
+			CreatedAt: now,
+			Reason:    reason,
+			TaskCount: len(tasks),
+		},
+		Tasks: tasks,
+	}
+	writeJSONFile(filepath.Join(dir, snapshot.ID+".json"), snapshot)
+	pruneSnapshots(dir)
+}
+
+func pruneSnapshots(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) <= maxSnapshots {
+		return
+	}
+	sort.Strings(names)
+	for _, name := range names[:len(names)-maxSnapshots] {
+		_ = os.Remove(filepath.Join(dir, name))
+	}
+}
+
+// ListSnapshots returns the saved task store snapshots, newest first.
+func (a *App) ListSnapshots() ([]Snapshot, error) {
+	dir, err := snapshotsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]Snapshot, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		snapshot, err := readSnapshot(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot.Snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+func readSnapshot(path string) (snapshotFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshotFile{}, err
+	}
+	var snapshot snapshotFile
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshotFile{}, err
+	}
+	return snapshot, nil
+}
+
+// RestoreSnapshot replaces the task store with a snapshot. The current store
+// is snapshotted first so the restore itself can be undone. Tasks that are
+// downloading right now are kept as they are.
+func (a *App) RestoreSnapshot(id string) ([]Task, error) {
+	dir, err := snapshotsDir()
+	if err != nil {
+		return nil, err
+	}
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, errors.New("snapshot not found")
+	}
+	snapshot, err := readSnapshot(filepath.Join(dir, id+".json"))
+	if err != nil {
+		return nil, errors.New("snapshot not found")
+	}
+	a.snapshotTasks("Before restoring " + snapshot.ID)
+
+	for i := range snapshot.Tasks {
+		outputs, _ := refreshOutputs(snapshot.Tasks[i].Outputs)
+		snapshot.Tasks[i].setOutputs(outputs)
+	}
+
+	var enqueueIDs []string
+	a.mu.Lock()
+	previous := a.tasks
+	a.tasks = make(map[string]*Task, len(snapshot.Tasks))
+	a.order = make([]string, 0, len(snapshot.Tasks))
+	for i := range snapshot.Tasks {
+		item := snapshot.Tasks[i]
+		current, existed := previous[item.ID]
+		if _, running := a.running[item.ID]; running && existed {
+			a.tasks[item.ID] = current
+			a.order = append(a.order, item.ID)
+			continue
+		}
+		wasQueued := existed && current.Status == statusQueued
+		if item.Status == statusRunning {
+			item.setStatus(statusQueued)
+			item.Resume = true
+		}
+		copy := item
+		a.tasks[item.ID] = &copy
+		a.order = append(a.order, item.ID)
+		if item.Status == statusQueued && !wasQueued {
+			enqueueIDs = append(enqueueIDs, item.ID)
+		}
+	}
+	for id := range a.running {
+		if _, kept := a.tasks[id]; kept {
+			continue
+		}
+		if task, ok := previous[id]; ok {
+			a.tasks[id] = task
+			a.order = append(a.order, id)
+		}
+	}
+	out := make([]Task, 0, len(a.order))
+	for _, id := range a.order {
+		out = append(out, *a.tasks[id])
+	}
+	a.mu.Unlock()
+
+	a.enqueueTasks(enqueueIDs)
+	a.saveTasks()
+	return out, nil
+}
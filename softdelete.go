@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	deleteUndoWindow    = 30 * time.Second
+	deleteSweepInterval = 5 * time.Second
+)
+
+// deleted reports whether the task is waiting to be purged. Deleted tasks
+// are left out of lists, stats and exports and cannot be restarted.
+func (t *Task) deleted() bool {
+	return t.DeletedAt != nil
+}
+
+// markTasksDeleted is the first phase of deleting tasks: active tasks are
+// stopped and every task gets DeletedAt, but the task and its files stay
+// until deleteSweepLoop purges them after deleteUndoWindow.
+func (a *App) markTasksDeleted(ids []string) BatchResult {
+	result := newBatchResult()
+	now := time.Now()
+	var updated []string
+	a.mu.Lock()
+	for _, id := range uniqueIDs(ids) {
+		task, ok := a.tasks[id]
+		if !ok {
+			result.Failed[id] = "task not found"
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, id)
+		if task.deleted() {
+			continue
+		}
+		if cmd, ok := a.running[id]; ok {
+			_ = killProcessTree(cmd)
+		}
+		delete(a.suspended, id)
+		if task.Status == statusQueued || task.Status == statusRunning || task.Status == statusPaused || task.Status == statusAwaitingConfirmation {
+			task.setStatus(statusCanceled)
+			task.Speed = ""
+			task.ETA = ""
+			task.closeStage(now)
+		}
+		deletedAt := now
+		task.DeletedAt = &deletedAt
+		task.UpdatedAt = now
+		updated = append(updated, task.ID)
+	}
+	a.mu.Unlock()
+
+	if len(updated) == 0 {
+		return result
+	}
+	a.emitTasksRemoved(updated)
+	a.saveTasks()
+	a.emitQueueSummary()
+	return result
+}
+
+// UndoDelete restores a task deleted within the last 30 seconds, files
+// included. A task that was downloading comes back as Canceled and can be
+// resumed.
+func (a *App) UndoDelete(id string) (Task, error) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if !task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is not deleted")
+	}
+	task.DeletedAt = nil
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	a.emitQueueSummary()
+	return updated, nil
+}
+
+// deleteSweepLoop purges deleted tasks once their undo window has passed and
+// emits "tasks:deleted" with their ids. Deletions left pending when the app
+// quit are purged after the next launch.
+func (a *App) deleteSweepLoop() {
+	ticker := time.NewTicker(deleteSweepInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		var expired []string
+		a.mu.Lock()
+		for _, id := range a.order {
+			if task, ok := a.tasks[id]; ok && task.deleted() && now.Sub(*task.DeletedAt) >= deleteUndoWindow {
+				expired = append(expired, id)
+			}
+		}
+		a.mu.Unlock()
+		if len(expired) == 0 {
+			continue
+		}
+		result := a.purgeTasks(expired, false)
+		if len(result.Succeeded) > 0 && a.ctx != nil {
+			wailsruntime.EventsEmit(a.ctx, "tasks:deleted", result.Succeeded)
+		}
+		if len(result.Failed) > 0 {
+			a.restoreFailedDeletions(result.Failed)
+		}
+	}
+}
+
+// emitTasksRemoved emits "tasks:removed" with the ids of tasks that are
+// deleted, so the UI drops them from its list right away.
+func (a *App) emitTasksRemoved(ids []string) {
+	a.remoteHub.broadcast("tasks:removed", ids)
+	if a.ctx == nil {
+		return
+	}
+	wailsruntime.EventsEmit(a.ctx, "tasks:removed", ids)
+}
+
+// restoreFailedDeletions brings back tasks whose files could not be moved to
+// the trash, so they are not retried every sweep and the error is visible.
+func (a *App) restoreFailedDeletions(failed map[string]string) {
+	var updated []Task
+	a.mu.Lock()
+	for id, message := range failed {
+		task, ok := a.tasks[id]
+		if !ok {
+			continue
+		}
+		task.DeletedAt = nil
+		task.setError(taskError{Code: errorTrashFailed, Params: map[string]string{"detail": message}})
+		task.UpdatedAt = time.Now()
+		updated = append(updated, *task)
+	}
+	a.mu.Unlock()
+	for _, task := range updated {
+		a.emitTaskUpdate(task)
+	}
+	a.saveTasks()
+}
@@ -0,0 +1,62 @@
+package main
+
+import "time"
+
+// GetAutoResumeOnLaunch reports whether downloads interrupted by quitting or
+// a crash are queued again when the app starts.
+func (a *App) GetAutoResumeOnLaunch() (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return !a.noAutoResume, nil
+}
+
+// SetAutoResumeOnLaunch chooses whether interrupted downloads are queued
+// again at launch. When off they stay Interrupted until resumed by hand.
+func (a *App) SetAutoResumeOnLaunch(resume bool) error {
+	a.mu.Lock()
+	a.noAutoResume = !resume
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// recoverTasks runs once after the tasks are loaded. Nothing is running yet,
+// so tasks still marked Running were cut off by a crash and become
+// Interrupted. Queued tasks are put back in the queue, and so are
+// Interrupted ones unless auto-resume is off.
+func (a *App) recoverTasks() {
+	now := time.Now()
+	var ids []string
+	changed := false
+	a.mu.Lock()
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok {
+			continue
+		}
+		if task.Status == statusRunning {
+			task.setStatus(statusInterrupted)
+			task.Resume = true
+			task.Speed = ""
+			task.ETA = ""
+			// The crash time is unknown; the last update is the best guess.
+			task.closeStage(task.UpdatedAt)
+			task.UpdatedAt = now
+			changed = true
+		}
+		switch {
+		case task.Status == statusQueued:
+			ids = append(ids, id)
+		case task.Status == statusInterrupted && !a.noAutoResume:
+			requeueTaskLocked(task, stageResume, true)
+			ids = append(ids, id)
+			changed = true
+		}
+	}
+	a.mu.Unlock()
+
+	if changed {
+		a.saveTasks()
+	}
+	a.enqueueTasks(ids)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// StatisticsBucket sums finished tasks for one group. Bytes and speed cover
+// successful downloads only; SuccessRate ignores canceled tasks.
+type StatisticsBucket struct {
+	Key             string  `json:"key"`
+	Count           int     `json:"count"`
+	Succeeded       int     `json:"succeeded"`
+	Failed          int     `json:"failed"`
+	Canceled        int     `json:"canceled"`
+	Bytes           int64   `json:"bytes"`
+	DownloadSeconds float64 `json:"downloadSeconds"`
+	AverageSpeed    float64 `json:"averageSpeed"`
+	SuccessRate     float64 `json:"successRate"`
+}
+
+// DownloadStatistics breaks finished tasks down by the local day they
+// finished and by source host. Days are sorted oldest first, hosts by task
+// count.
+type DownloadStatistics struct {
+	Totals StatisticsBucket   `json:"totals"`
+	ByDay  []StatisticsBucket `json:"byDay"`
+	ByHost []StatisticsBucket `json:"byHost"`
+}
+
+// GetStatistics summarizes finished tasks in the task list and the archived
+// history.
+func (a *App) GetStatistics() (DownloadStatistics, error) {
+	totals := &StatisticsBucket{Key: "all"}
+	days := map[string]*StatisticsBucket{}
+	hosts := map[string]*StatisticsBucket{}
+	add := func(task Task) {
+		if !task.finished() {
+			return
+		}
+		day := task.UpdatedAt.Local().Format("2006-01-02")
+		host := normalizeOverrideHost(task.SourceHost)
+		if host == "" {
+			host = "unknown"
+		}
+		if days[day] == nil {
+			days[day] = &StatisticsBucket{Key: day}
+		}
+		if hosts[host] == nil {
+			hosts[host] = &StatisticsBucket{Key: host}
+		}
+		for _, bucket := range []*StatisticsBucket{totals, days[day], hosts[host]} {
+			bucket.add(task)
+		}
+	}
+
+	a.mu.Lock()
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok && !task.deleted() {
+			add(*task)
+		}
+	}
+	a.mu.Unlock()
+	if a.store != nil {
+		if err := a.store.viewHistory(add); err != nil {
+			return DownloadStatistics{}, err
+		}
+	}
+
+	stats := DownloadStatistics{ByDay: []StatisticsBucket{}, ByHost: []StatisticsBucket{}}
+	totals.finish()
+	stats.Totals = *totals
+	for _, bucket := range days {
+		bucket.finish()
+		stats.ByDay = append(stats.ByDay, *bucket)
+	}
+	for _, bucket := range hosts {
+		bucket.finish()
+		stats.ByHost = append(stats.ByHost, *bucket)
+	}
+	sort.Slice(stats.ByDay, func(i, j int) bool { return stats.ByDay[i].Key < stats.ByDay[j].Key })
+	sort.Slice(stats.ByHost, func(i, j int) bool {
+		if stats.ByHost[i].Count != stats.ByHost[j].Count {
+			return stats.ByHost[i].Count > stats.ByHost[j].Count
+		}
+		return stats.ByHost[i].Key < stats.ByHost[j].Key
+	})
+	return stats, nil
+}
+
+func (b *StatisticsBucket) add(task Task) {
+	b.Count++
+	switch task.Status {
+	case statusSuccess:
+		b.Succeeded++
+		b.Bytes += downloadedSize(task)
+		b.DownloadSeconds += stageSeconds(task, stageLabels[stageDownload])
+	case statusFailed:
+		b.Failed++
+	case statusCanceled:
+		b.Canceled++
+	}
+}
+
+func (b *StatisticsBucket) finish() {
+	if b.DownloadSeconds > 0 {
+		b.AverageSpeed = float64(b.Bytes) / b.DownloadSeconds
+	}
+	if attempted := b.Succeeded + b.Failed; attempted > 0 {
+		b.SuccessRate = float64(b.Succeeded) / float64(attempted)
+	}
+}
+
+// downloadedSize prefers the size of the files on disk, then the size yt-dlp
+// reported.
+func downloadedSize(task Task) int64 {
+	var size int64
+	for _, output := range task.Outputs {
+		size += output.Size
+	}
+	if size > 0 {
+		return size
+	}
+	return taskSize(task)
+}
+
+// stageSeconds adds up the finished timeline spans labelled stage.
+func stageSeconds(task Task, stage string) float64 {
+	var total time.Duration
+	for _, span := range task.Timeline {
+		if span.Stage == stage && span.EndedAt != nil {
+			total += span.EndedAt.Sub(span.StartedAt)
+		}
+	}
+	return total.Seconds()
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	subscriptionCheckInterval = time.Minute
+	minSubscriptionInterval   = 15
+	maxSubscriptionSeen       = 5000
+
+	subscriptionKindPlaylist = "playlist"
+	subscriptionKindFeed     = "feed"
+)
+
+// Subscription watches a channel, playlist or RSS/Atom feed and queues its
+// new entries. IntervalMinutes is how often it is checked. Entries already
+// present when the subscription is added are not downloaded. Seen holds entry
+// URLs for playlists and item GUIDs for feeds.
+type Subscription struct {
+	ID              string    `json:"id"`
+	Kind            string    `json:"kind"`
+	URL             string    `json:"url"`
+	Title           string    `json:"title"`
+	IntervalMinutes int       `json:"intervalMinutes"`
+	ProfileID       string    `json:"profileId"`
+	Seen            []string  `json:"seen"`
+	LastCheckedAt   time.Time `json:"lastCheckedAt"`
+	LastError       string    `json:"lastError"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// ListSubscriptions returns the watched channels and playlists.
+func (a *App) ListSubscriptions() ([]Subscription, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]Subscription, len(a.subscriptions))
+	copy(out, a.subscriptions)
+	return out, nil
+}
+
+// AddSubscription starts watching a channel or playlist URL. interval is in
+// minutes; an empty profileID uses the active profile when tasks run.
+func (a *App) AddSubscription(url string, interval int, profileID string) (Subscription, error) {
+	url = strings.TrimSpace(url)
+	if err := a.validateSubscription(url, interval, profileID); err != nil {
+		return Subscription{}, err
+	}
+	probe, err := a.probeURL(url)
+	if err != nil {
+		return Subscription{}, err
+	}
+	if probe.Type != "playlist" && len(probe.Entries) == 0 {
+		return Subscription{}, errors.New("url is not a channel or playlist")
+	}
+
+	now := time.Now()
+	subscription := Subscription{
+		ID:              newID(),
+		Kind:            subscriptionKindPlaylist,
+		URL:             url,
+		Title:           strings.TrimSpace(probe.Title),
+		IntervalMinutes: interval,
+		ProfileID:       profileID,
+		Seen:            []string{},
+		LastCheckedAt:   now,
+		CreatedAt:       now,
+	}
+	if subscription.Title == "" {
+		subscription.Title = defaultTitleFromURL(url)
+	}
+	for _, entry := range playlistEntries(probe.Entries, 0) {
+		subscription.Seen = append(subscription.Seen, entry.URL)
+	}
+	return a.addSubscription(subscription)
+}
+
+func (a *App) validateSubscription(url string, interval int, profileID string) error {
+	if url == "" {
+		return errors.New("url is required")
+	}
+	if interval < minSubscriptionInterval {
+		return errors.New("interval must be at least 15 minutes")
+	}
+	if profileID != "" {
+		a.mu.Lock()
+		_, ok := a.findProfileLocked(profileID)
+		a.mu.Unlock()
+		if !ok {
+			return errors.New("profile not found")
+		}
+	}
+	return nil
+}
+
+func (a *App) addSubscription(subscription Subscription) (Subscription, error) {
+	a.mu.Lock()
+	for _, existing := range a.subscriptions {
+		if existing.URL == subscription.URL {
+			a.mu.Unlock()
+			return Subscription{}, errors.New("already subscribed to this url")
+		}
+	}
+	a.subscriptions = append(a.subscriptions, subscription)
+	a.mu.Unlock()
+	a.saveSubscriptions()
+	return subscription, nil
+}
+
+// RemoveSubscription stops watching a channel, playlist or feed. Tasks it already
+// created are kept.
+func (a *App) RemoveSubscription(id string) error {
+	a.mu.Lock()
+	index := -1
+	for i := range a.subscriptions {
+		if a.subscriptions[i].ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		a.mu.Unlock()
+		return errors.New("subscription not found")
+	}
+	a.subscriptions = append(a.subscriptions[:index:index], a.subscriptions[index+1:]...)
+	a.mu.Unlock()
+	a.saveSubscriptions()
+	return nil
+}
+
+func (a *App) subscriptionLoop() {
+	ticker := time.NewTicker(subscriptionCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		a.mu.Lock()
+		var due []Subscription
+		for _, subscription := range a.subscriptions {
+			interval := time.Duration(subscription.IntervalMinutes) * time.Minute
+			if now.Sub(subscription.LastCheckedAt) >= interval {
+				due = append(due, subscription)
+			}
+		}
+		a.mu.Unlock()
+		for _, subscription := range due {
+			a.checkSubscription(subscription)
+		}
+	}
+}
+
+// checkSubscription probes a subscription and queues entries it has not
+// seen before.
+func (a *App) checkSubscription(subscription Subscription) {
+	seen := make(map[string]bool, len(subscription.Seen))
+	for _, key := range subscription.Seen {
+		seen[key] = true
+	}
+	var fresh []PreviewEntry
+	var keys []string
+	var err error
+	if subscription.Kind == subscriptionKindFeed {
+		var items []feedItem
+		if _, items, err = fetchFeed(subscription.URL); err == nil {
+			for _, item := range items {
+				if !seen[item.GUID] {
+					seen[item.GUID] = true
+					keys = append(keys, item.GUID)
+					fresh = append(fresh, PreviewEntry{URL: item.URL, Title: item.Title})
+				}
+			}
+		}
+	} else {
+		var probe ytdlpProbe
+		if probe, err = a.probeURL(subscription.URL); err == nil {
+			for _, entry := range playlistEntries(probe.Entries, 0) {
+				if !seen[entry.URL] {
+					seen[entry.URL] = true
+					keys = append(keys, entry.URL)
+					fresh = append(fresh, entry)
+				}
+			}
+		}
+	}
+
+	a.mu.Lock()
+	var profileID string
+	found := false
+	for i := range a.subscriptions {
+		current := &a.subscriptions[i]
+		if current.ID != subscription.ID {
+			continue
+		}
+		found = true
+		profileID = current.ProfileID
+		current.LastCheckedAt = time.Now()
+		current.LastError = ""
+		if err != nil {
+			current.LastError = err.Error()
+			break
+		}
+		next := append(append([]string{}, current.Seen...), keys...)
+		if len(next) > maxSubscriptionSeen {
+			next = next[len(next)-maxSubscriptionSeen:]
+		}
+		current.Seen = next
+		break
+	}
+	a.mu.Unlock()
+	if !found {
+		return
+	}
+	a.saveSubscriptions()
+	if len(fresh) > 0 {
+		a.createTasks(fresh, newTaskOptions{batchID: newID(), profileID: profileID})
+	}
+}
+
+func subscriptionsFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "subscriptions.json"), nil
+}
+
+func (a *App) loadSubscriptions() {
+	path, err := subscriptionsFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var items []Subscription
+	if err := json.Unmarshal(data, &items); err != nil {
+		return
+	}
+	for i := range items {
+		if items[i].Kind == "" {
+			items[i].Kind = subscriptionKindPlaylist
+		}
+	}
+	a.mu.Lock()
+	a.subscriptions = items
+	a.mu.Unlock()
+}
+
+func (a *App) saveSubscriptions() {
+	path, err := subscriptionsFilePath()
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	snapshot := make([]Subscription, len(a.subscriptions))
+	copy(snapshot, a.subscriptions)
+	a.mu.Unlock()
+	writeJSONFile(path, snapshot)
+}
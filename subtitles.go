@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SubtitleOptions controls subtitle downloads. Languages are yt-dlp
+// --sub-langs entries such as "en", "zh-Hans" or "en.*"; empty uses
+// yt-dlp's default. Embed writes the subtitles into the media file instead
+// of keeping sidecar files.
+type SubtitleOptions struct {
+	Enabled       bool     `json:"enabled"`
+	Languages     []string `json:"languages"`
+	AutoGenerated bool     `json:"autoGenerated"`
+	Embed         bool     `json:"embed"`
+}
+
+var subtitleLanguagePattern = regexp.MustCompile(`^-?[A-Za-z0-9_.*-]+$`)
+
+// SetTaskSubtitles overrides the profile's subtitle settings for a task that
+// has not started downloading. nil goes back to the profile's settings.
+func (a *App) SetTaskSubtitles(id string, options *SubtitleOptions) (Task, error) {
+	options, err := normalizeSubtitleOptions(options)
+	if err != nil {
+		return Task{}, err
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.Status == statusRunning || task.Status == statusPaused || task.Status == statusSuccess {
+		a.mu.Unlock()
+		return Task{}, errors.New("task has already started downloading")
+	}
+	task.Subtitles = options
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
+
+func normalizeSubtitleOptions(options *SubtitleOptions) (*SubtitleOptions, error) {
+	if options == nil {
+		return nil, nil
+	}
+	normalized := *options
+	normalized.Languages = make([]string, 0, len(options.Languages))
+	for _, language := range options.Languages {
+		language = strings.TrimSpace(language)
+		if language == "" {
+			continue
+		}
+		if !subtitleLanguagePattern.MatchString(language) {
+			return nil, fmt.Errorf("invalid subtitle language %q", language)
+		}
+		normalized.Languages = append(normalized.Languages, language)
+	}
+	return &normalized, nil
+}
+
+// subtitleArgs returns the yt-dlp arguments for options. A nil or disabled
+// setting adds nothing.
+func subtitleArgs(options *SubtitleOptions) []string {
+	if options == nil || !options.Enabled {
+		return nil
+	}
+	args := []string{"--write-subs"}
+	if options.AutoGenerated {
+		args = append(args, "--write-auto-subs")
+	}
+	if len(options.Languages) > 0 {
+		args = append(args, "--sub-langs", strings.Join(options.Languages, ","))
+	}
+	if options.Embed {
+		args = append(args, "--embed-subs")
+	}
+	return args
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RenameTask changes a task's title. With renameFile set, the task's output
+// files are renamed on disk to match, keeping sidecar suffixes such as
+// ".en.vtt" intact.
+func (a *App) RenameTask(id string, title string, renameFile bool) (Task, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return Task{}, errors.New("title is required")
+	}
+
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is deleted")
+	}
+	if renameFile && task.Status == statusRunning {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is running")
+	}
+	primary := task.OutputPath
+	paths := task.outputPaths()
+	a.mu.Unlock()
+
+	renames := make(map[string]string)
+	if renameFile {
+		var err error
+		if renames, err = renameOutputFiles(primary, paths, title); err != nil {
+			return Task{}, err
+		}
+	}
+
+	a.mu.Lock()
+	task, ok = a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	task.Title = title
+	task.TitleEdited = true
+	for from, to := range renames {
+		task.replaceOutputPath(from, to)
+	}
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
+
+// RenameTaskOutput renames a finished task's files on disk without changing
+// its title. newName is the new file name, with or without the extension;
+// sidecar files such as subtitles are renamed to match.
+func (a *App) RenameTaskOutput(id string, newName string) (Task, error) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is deleted")
+	}
+	if task.Status == statusRunning {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is running")
+	}
+	primary := task.OutputPath
+	paths := task.outputPaths()
+	a.mu.Unlock()
+
+	newName = strings.TrimSpace(newName)
+	if ext := filepath.Ext(primary); ext != "" && strings.EqualFold(filepath.Ext(newName), ext) {
+		newName = strings.TrimSuffix(newName, filepath.Ext(newName))
+	}
+	if newName == "" {
+		return Task{}, errors.New("file name is required")
+	}
+	renames, err := renameOutputFiles(primary, paths, newName)
+	if err != nil {
+		return Task{}, err
+	}
+	return a.applyOutputMoves(id, renames)
+}
+
+// MoveTaskOutput moves a finished task's files into destDir, copying and
+// deleting when the folder is on another drive.
+func (a *App) MoveTaskOutput(id string, destDir string) (Task, error) {
+	destDir, err := validateOutputDir(destDir)
+	if err != nil {
+		return Task{}, err
+	}
+	if destDir == "" {
+		return Task{}, errors.New("destination folder is required")
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is deleted")
+	}
+	if task.Status == statusRunning {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is running")
+	}
+	primary := task.OutputPath
+	paths := task.outputPaths()
+	a.mu.Unlock()
+
+	if primary == "" || !fileExists(primary) {
+		return Task{}, errors.New("output file not available")
+	}
+	moves := make(map[string]string)
+	for _, path := range paths {
+		if !fileExists(path) {
+			continue
+		}
+		target := filepath.Join(destDir, filepath.Base(path))
+		if target == path {
+			continue
+		}
+		if _, err := os.Stat(target); err == nil {
+			return Task{}, errors.New("a file named " + filepath.Base(target) + " already exists")
+		}
+		moves[path] = target
+	}
+	done := make(map[string]string, len(moves))
+	for from, to := range moves {
+		if err := moveFile(from, to); err != nil {
+			for undoFrom, undoTo := range done {
+				_ = moveFile(undoTo, undoFrom)
+			}
+			return Task{}, err
+		}
+		done[from] = to
+	}
+	return a.applyOutputMoves(id, moves)
+}
+
+// applyOutputMoves records files moved on disk in the task's outputs.
+func (a *App) applyOutputMoves(id string, moves map[string]string) (Task, error) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	for from, to := range moves {
+		task.replaceOutputPath(from, to)
+	}
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
+
+// renameOutputFiles renames the output files that share the primary file's
+// base name to use newName instead, keeping sidecar suffixes such as
+// ".en.vtt". Nothing is renamed if any target already exists, and renames
+// done before a failure are undone. It returns the old and new paths.
+func renameOutputFiles(primary string, paths []string, newName string) (map[string]string, error) {
+	if primary == "" || !fileExists(primary) {
+		return nil, errors.New("output file not available")
+	}
+	oldBase := strings.TrimSuffix(filepath.Base(primary), filepath.Ext(primary))
+	newBase := sanitizeFilename(newName)
+	if newBase == "" {
+		return nil, errors.New("name has no usable filename characters")
+	}
+	renames := make(map[string]string)
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if !strings.HasPrefix(name, oldBase) || !fileExists(path) {
+			continue
+		}
+		target := filepath.Join(filepath.Dir(path), newBase+strings.TrimPrefix(name, oldBase))
+		if target == path {
+			continue
+		}
+		if _, err := os.Stat(target); err == nil {
+			return nil, errors.New("a file named " + filepath.Base(target) + " already exists")
+		}
+		renames[path] = target
+	}
+	done := make(map[string]string, len(renames))
+	for from, to := range renames {
+		if err := os.Rename(from, to); err != nil {
+			for undoFrom, undoTo := range done {
+				_ = os.Rename(undoTo, undoFrom)
+			}
+			return nil, err
+		}
+		done[from] = to
+	}
+	return renames, nil
+}
+
+// UpdateTaskDetails sets a task's title and notes together, as edited in the
+// task's details. Like RenameTask, a changed title is kept from then on
+// instead of being replaced by the title metadata reports.
+func (a *App) UpdateTaskDetails(id string, title string, notes string) (Task, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return Task{}, errors.New("title is required")
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return Task{}, errors.New("task is deleted")
+	}
+	if title != task.Title {
+		task.Title = title
+		task.TitleEdited = true
+	}
+	task.Notes = strings.TrimSpace(notes)
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
+
+// needsTitle reports whether a fetched title may replace the task's title:
+// it is still a placeholder and the user has not edited it.
+func (t *Task) needsTitle() bool {
+	return !t.TitleEdited && shouldUpdateTitle(t.Title)
+}
+
+// SetTaskNotes stores free-text notes on a task.
+func (a *App) SetTaskNotes(id string, notes string) error {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return errors.New("task not found")
+	}
+	task.Notes = strings.TrimSpace(notes)
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return nil
+}
+
+// SetTaskTags replaces a task's tags. Tags are lowercased and deduplicated.
+func (a *App) SetTaskTags(id string, tags []string) (Task, error) {
+	normalized := normalizeTags(tags)
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	task.Tags = normalized
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
+
+// normalizeTags lowercases tags and drops empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func hasAnyTag(tags []string, wanted []string) bool {
+	for _, tag := range tags {
+		if slices.Contains(wanted, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTaskOutputDir changes the folder a task downloads into. Empty input
+// returns the task to the default dated folder. Only tasks that have not
+// started downloading can be moved.
+func (a *App) SetTaskOutputDir(id string, outputDir string) (Task, error) {
+	outputDir, err := validateOutputDir(outputDir)
+	if err != nil {
+		return Task{}, err
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return Task{}, errors.New("task not found")
+	}
+	if task.Status == statusRunning || task.Status == statusPaused || task.Status == statusSuccess {
+		a.mu.Unlock()
+		return Task{}, errors.New("task has already started downloading")
+	}
+	task.OutputDir = outputDir
+	task.FilenameTemplate = ""
+	task.UpdatedAt = time.Now()
+	updated := *task
+	a.mu.Unlock()
+
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+	return updated, nil
+}
+
+// sanitizeFilename makes a title safe to use as a file name on all platforms.
+func sanitizeFilename(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			continue
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			b.WriteRune('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Trim(strings.TrimSpace(b.String()), ".")
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Each task has a plain-text log in ~/.fetchforge/logs/<taskID>.log holding
+// the full output of its tools. A log larger than taskLogMaxBytes is rotated
+// to <taskID>.log.1 when the next run starts, and logs untouched for
+// taskLogMaxAge are pruned by the cleanup loop.
+const (
+	taskLogMaxBytes = 2 << 20
+	taskLogMaxAge   = 30 * 24 * time.Hour
+)
+
+// TaskLogLine is the payload of the task:log event.
+type TaskLogLine struct {
+	ID   string `json:"id"`
+	Line string `json:"line"`
+}
+
+func taskLogPath(id string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "logs", id+".log"), nil
+}
+
+// appendTaskLog adds a timestamped section to a task's log.
+func appendTaskLog(id string, title string, text string) {
+	path, err := taskLogPath(id)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintf(file, "== %s %s ==\n", time.Now().Format(time.RFC3339), title)
+	if text = strings.TrimRight(text, "\n"); text != "" {
+		fmt.Fprintln(file, text)
+	}
+}
+
+// isProgressLine reports whether a line is a progress tick from yt-dlp,
+// aria2c or ffmpeg's -progress output.
+func isProgressLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "progress:") || aria2ProgressPattern.MatchString(line) || ffmpegProgressPattern.MatchString(line)
+}
+
+// runTaskCommand runs cmd like runCommandWithLines and streams every output
+// line to the task's log and the task:log event. Progress ticks are passed
+// to onLine but not logged.
+func (a *App) runTaskCommand(id string, cmd *exec.Cmd, onLine func(string)) (string, string, error) {
+	file := openTaskLog(id, strings.Join(redactArgs(cmd.Args), " "))
+	if file == nil {
+		return a.runCommandWithLines(cmd, onLine)
+	}
+	defer file.Close()
+	var mu sync.Mutex
+	return a.runCommandWithLines(cmd, func(line string) {
+		if onLine != nil {
+			onLine(line)
+		}
+		if isProgressLine(line) {
+			return
+		}
+		mu.Lock()
+		fmt.Fprintln(file, line)
+		mu.Unlock()
+		if a.ctx != nil {
+			wailsruntime.EventsEmit(a.ctx, "task:log", TaskLogLine{ID: id, Line: line})
+		}
+	})
+}
+
+// openTaskLog rotates an oversized log, opens it for appending and writes a
+// header for the command. It returns nil when the log cannot be opened.
+func openTaskLog(id, command string) *os.File {
+	path, err := taskLogPath(id)
+	if err != nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > taskLogMaxBytes {
+		_ = os.Rename(path, path+".1")
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil
+	}
+	fmt.Fprintf(file, "== %s %s ==\n", time.Now().Format(time.RFC3339), command)
+	return file
+}
+
+// GetTaskLog returns a task's log. With tailLines > 0 only the last lines
+// are returned. A task that has not run yet has an empty log.
+func (a *App) GetTaskLog(id string, tailLines int) (string, error) {
+	if id == "" || filepath.Base(id) != id || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid task id %q", id)
+	}
+	path, err := taskLogPath(id)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	text := string(data)
+	if tailLines <= 0 {
+		return text, nil
+	}
+	lines := strings.SplitAfter(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > tailLines {
+		lines = lines[len(lines)-tailLines:]
+	}
+	return strings.Join(lines, "") + "\n", nil
+}
+
+// pruneTaskLogs deletes logs not written to within taskLogMaxAge, except
+// those of tasks still in the list.
+func (a *App) pruneTaskLogs() {
+	path, err := taskLogPath("")
+	if err != nil {
+		return
+	}
+	dir := filepath.Dir(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-taskLogMaxAge)
+	for _, entry := range entries {
+		name := entry.Name()
+		id := strings.TrimSuffix(strings.TrimSuffix(name, ".1"), ".log")
+		if entry.IsDir() || id == name {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		a.mu.Lock()
+		_, live := a.tasks[id]
+		a.mu.Unlock()
+		if !live {
+			_ = os.Remove(filepath.Join(dir, name))
+		}
+	}
+}
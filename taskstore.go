@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Tasks live in ~/.fetchforge/tasks.db, a bbolt database with three buckets:
+//
+//	tasks      task ID -> task JSON
+//	order      big-endian position -> task ID, the list order
+//	by_status  statusCode + "\x00" + task ID -> nothing
+//	history    archived tasks, see history.go
+//
+// saveTasks only writes tasks whose JSON changed since the last save, so a
+// progress tick on one download no longer rewrites the whole history.
+// taskSaveDebounce is how long progress updates may wait before they are
+// written. Status changes still call saveTasks directly.
+const taskSaveDebounce = 2 * time.Second
+
+var (
+	taskBucket       = []byte("tasks")
+	taskOrderBucket  = []byte("order")
+	taskStatusBucket = []byte("by_status")
+)
+
+type taskStore struct {
+	mu      sync.Mutex
+	db      *bolt.DB
+	written map[string]uint64 // task ID -> hash of the JSON last written
+	status  map[string]string // task ID -> status code in the index
+	order   []string
+}
+
+func openTaskStore() (*taskStore, error) {
+	path, err := taskStorePath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{taskBucket, taskOrderBucket, taskStatusBucket, historyBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &taskStore{db: db, written: map[string]uint64{}, status: map[string]string{}}, nil
+}
+
+func (s *taskStore) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Close()
+}
+
+// load returns the stored tasks in list order. Tasks missing from the order
+// bucket are appended by creation time.
+func (s *taskStore) load() ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.written = map[string]uint64{}
+	s.status = map[string]string{}
+	s.order = nil
+	var items []Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		byID := map[string]Task{}
+		err := tx.Bucket(taskBucket).ForEach(func(key, value []byte) error {
+			var task Task
+			if err := json.Unmarshal(value, &task); err != nil {
+				return nil
+			}
+			byID[string(key)] = task
+			s.written[string(key)] = hashJSON(value)
+			s.status[string(key)] = statusCodeFor(task.Status)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		err = tx.Bucket(taskOrderBucket).ForEach(func(_, value []byte) error {
+			id := string(value)
+			if task, ok := byID[id]; ok {
+				items = append(items, task)
+				s.order = append(s.order, id)
+				delete(byID, id)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		rest := make([]Task, 0, len(byID))
+		for _, task := range byID {
+			rest = append(rest, task)
+		}
+		sort.Slice(rest, func(i, j int) bool { return rest[i].CreatedAt.Before(rest[j].CreatedAt) })
+		for _, task := range rest {
+			items = append(items, task)
+			s.order = append(s.order, task.ID)
+		}
+		return nil
+	})
+	return items, err
+}
+
+// saveLocked writes the tasks that changed since the last save, drops the
+// ones no longer listed, and rewrites the order only when it changed. The
+// caller must hold s.mu.
+func (s *taskStore) saveLocked(items []Task) error {
+	encoded := make(map[string][]byte)
+	hashes := make(map[string]uint64)
+	order := make([]string, 0, len(items))
+	for _, task := range items {
+		order = append(order, task.ID)
+		data, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+		hash := hashJSON(data)
+		if s.written[task.ID] != hash {
+			encoded[task.ID] = data
+			hashes[task.ID] = hash
+		}
+	}
+	listed := make(map[string]bool, len(order))
+	for _, id := range order {
+		listed[id] = true
+	}
+	var removed []string
+	for id := range s.written {
+		if !listed[id] {
+			removed = append(removed, id)
+		}
+	}
+	orderChanged := !slices.Equal(order, s.order)
+	if len(encoded) == 0 && len(removed) == 0 && !orderChanged {
+		return nil
+	}
+
+	statuses := make(map[string]string, len(encoded))
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		tasks := tx.Bucket(taskBucket)
+		index := tx.Bucket(taskStatusBucket)
+		for _, id := range removed {
+			if err := tasks.Delete([]byte(id)); err != nil {
+				return err
+			}
+			if err := index.Delete(statusKey(s.status[id], id)); err != nil {
+				return err
+			}
+		}
+		for _, task := range items {
+			data, ok := encoded[task.ID]
+			if !ok {
+				continue
+			}
+			if err := tasks.Put([]byte(task.ID), data); err != nil {
+				return err
+			}
+			if previous, ok := s.status[task.ID]; ok && previous != statusCodeFor(task.Status) {
+				if err := index.Delete(statusKey(previous, task.ID)); err != nil {
+					return err
+				}
+			}
+			if err := index.Put(statusKey(statusCodeFor(task.Status), task.ID), nil); err != nil {
+				return err
+			}
+			statuses[task.ID] = statusCodeFor(task.Status)
+		}
+		if !orderChanged {
+			return nil
+		}
+		if err := tx.DeleteBucket(taskOrderBucket); err != nil {
+			return err
+		}
+		bucket, err := tx.CreateBucket(taskOrderBucket)
+		if err != nil {
+			return err
+		}
+		for position, id := range order {
+			key := make([]byte, 8)
+			binary.BigEndian.PutUint64(key, uint64(position))
+			if err := bucket.Put(key, []byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range removed {
+		delete(s.written, id)
+		delete(s.status, id)
+	}
+	for id, hash := range hashes {
+		s.written[id] = hash
+		s.status[id] = statuses[id]
+	}
+	s.order = order
+	return nil
+}
+
+// idsWithStatus returns the IDs of tasks stored with statusCode, using the
+// status index.
+func (s *taskStore) idsWithStatus(statusCode string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	prefix := statusKey(statusCode, "")
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(taskStatusBucket).Cursor()
+		for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+			ids = append(ids, string(key[len(prefix):]))
+		}
+		return nil
+	})
+	return ids, err
+}
+
+// migrateTasksJSON imports tasks.json into an empty store once and renames
+// the file so it is not imported again.
+func (s *taskStore) migrateTasksJSON() error {
+	path, err := tasksFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	empty := true
+	err = s.db.View(func(tx *bolt.Tx) error {
+		key, _ := tx.Bucket(taskBucket).Cursor().First()
+		empty = key == nil
+		return nil
+	})
+	if err != nil || !empty {
+		return err
+	}
+	var items []Task
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if err := s.saveLocked(items); err != nil {
+		return err
+	}
+	return os.Rename(path, path+".migrated")
+}
+
+func statusKey(statusCode, id string) []byte {
+	return []byte(statusCode + "\x00" + id)
+}
+
+func hashJSON(data []byte) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write(data)
+	return hash.Sum64()
+}
+
+func taskStorePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "tasks.db"), nil
+}
+
+// saveTasksSoon marks the tasks dirty and saves them within
+// taskSaveDebounce. Updates from every running download in that window are
+// written together, and a saveTasks call in the meantime covers them.
+func (a *App) saveTasksSoon() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.savePending {
+		return
+	}
+	a.savePending = true
+	time.AfterFunc(taskSaveDebounce, func() {
+		a.mu.Lock()
+		pending := a.savePending
+		a.mu.Unlock()
+		if pending {
+			a.saveTasks()
+		}
+	})
+}
+
+// tasksWithStatus looks tasks up through the status index. ok is false when
+// there is no store or no status to filter by, and the caller should scan
+// the task list instead.
+func (a *App) tasksWithStatus(status string) ([]Task, bool) {
+	code := statusCodeFor(strings.TrimSpace(status))
+	if a.store == nil || code == "" {
+		return nil, false
+	}
+	ids, err := a.store.idsWithStatus(code)
+	if err != nil {
+		return nil, false
+	}
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]Task, 0, len(ids))
+	for _, id := range a.order {
+		if task, ok := a.tasks[id]; ok && wanted[id] && !task.deleted() && statusCodeFor(task.Status) == code {
+			out = append(out, *task)
+		}
+	}
+	return out, true
+}
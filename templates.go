@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TaskTemplate is a named set of task settings that can be applied to a
+// batch of links. Empty fields fall back to the usual defaults: the active
+// profile, the profile's format and subtitles, and the dated download folder.
+type TaskTemplate struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	ProfileID string           `json:"profileId"`
+	FormatID  string           `json:"formatId"`
+	OutputDir string           `json:"outputDir"`
+	Subtitles *SubtitleOptions `json:"subtitles"`
+	Tags      []string         `json:"tags"`
+}
+
+// ListTaskTemplates returns the saved task templates.
+func (a *App) ListTaskTemplates() ([]TaskTemplate, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]TaskTemplate, len(a.taskTemplates))
+	copy(out, a.taskTemplates)
+	return out, nil
+}
+
+// SaveTaskTemplate creates a template, or replaces the one with the same id.
+func (a *App) SaveTaskTemplate(template TaskTemplate) (TaskTemplate, error) {
+	template, err := normalizeTaskTemplate(template)
+	if err != nil {
+		return TaskTemplate{}, err
+	}
+	if strings.TrimSpace(template.ID) == "" {
+		template.ID = newID()
+	}
+
+	a.mu.Lock()
+	if template.ProfileID != "" {
+		if _, ok := a.findProfileLocked(template.ProfileID); !ok {
+			a.mu.Unlock()
+			return TaskTemplate{}, errors.New("profile not found")
+		}
+	}
+	replaced := false
+	for i := range a.taskTemplates {
+		if a.taskTemplates[i].ID == template.ID {
+			a.taskTemplates[i] = template
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		a.taskTemplates = append(a.taskTemplates, template)
+	}
+	a.mu.Unlock()
+	a.saveTaskTemplates()
+	return template, nil
+}
+
+// DeleteTaskTemplate removes a template by id.
+func (a *App) DeleteTaskTemplate(id string) error {
+	a.mu.Lock()
+	index := -1
+	for i := range a.taskTemplates {
+		if a.taskTemplates[i].ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		a.mu.Unlock()
+		return errors.New("template not found")
+	}
+	a.taskTemplates = append(a.taskTemplates[:index], a.taskTemplates[index+1:]...)
+	a.mu.Unlock()
+	a.saveTaskTemplates()
+	return nil
+}
+
+// CreateTasksFromTemplate queues one task per URL in text with the
+// template's settings.
+func (a *App) CreateTasksFromTemplate(templateID string, text string) ([]Task, error) {
+	a.mu.Lock()
+	var template TaskTemplate
+	found := false
+	for _, item := range a.taskTemplates {
+		if item.ID == templateID {
+			template, found = item, true
+			break
+		}
+	}
+	if found && template.ProfileID != "" {
+		if _, ok := a.findProfileLocked(template.ProfileID); !ok {
+			a.mu.Unlock()
+			return nil, errors.New("the template's profile no longer exists")
+		}
+	}
+	a.mu.Unlock()
+	if !found {
+		return nil, errors.New("template not found")
+	}
+
+	urls := extractURLs(text)
+	if len(urls) == 0 {
+		return []Task{}, nil
+	}
+	entries := make([]PreviewEntry, 0, len(urls))
+	for _, url := range urls {
+		entries = append(entries, PreviewEntry{URL: url})
+	}
+	return a.createTasks(entries, newTaskOptions{
+		outputDir: template.OutputDir,
+		profileID: template.ProfileID,
+		formatID:  template.FormatID,
+		subtitles: template.Subtitles,
+		tags:      template.Tags,
+	}), nil
+}
+
+// normalizeTaskTemplate validates and cleans user input for a template.
+func normalizeTaskTemplate(template TaskTemplate) (TaskTemplate, error) {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return TaskTemplate{}, errors.New("template name is required")
+	}
+	template.ProfileID = strings.TrimSpace(template.ProfileID)
+	template.FormatID = strings.TrimSpace(template.FormatID)
+	if !validFormatID(template.FormatID) {
+		return TaskTemplate{}, errors.New("invalid format id")
+	}
+	outputDir, err := validateOutputDir(template.OutputDir)
+	if err != nil {
+		return TaskTemplate{}, err
+	}
+	template.OutputDir = outputDir
+	subtitles, err := normalizeSubtitleOptions(template.Subtitles)
+	if err != nil {
+		return TaskTemplate{}, err
+	}
+	template.Subtitles = subtitles
+	template.Tags = normalizeTags(template.Tags)
+	return template, nil
+}
+
+func taskTemplatesFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fetchforge", "templates.json"), nil
+}
+
+func (a *App) loadTaskTemplates() {
+	path, err := taskTemplatesFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var items []TaskTemplate
+	if err := json.Unmarshal(data, &items); err != nil {
+		return
+	}
+	a.mu.Lock()
+	a.taskTemplates = items
+	a.mu.Unlock()
+}
+
+func (a *App) saveTaskTemplates() {
+	path, err := taskTemplatesFilePath()
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	snapshot := make([]TaskTemplate, len(a.taskTemplates))
+	copy(snapshot, a.taskTemplates)
+	a.mu.Unlock()
+	writeJSONFile(path, snapshot)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// StageSpan records how long a task spent in one stage.
+// EndedAt is nil while the stage is still in progress.
+type StageSpan struct {
+	Stage     string     `json:"stage"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt"`
+}
+
+// StageStat aggregates completed stage spans across all tasks.
+type StageStat struct {
+	Stage          string  `json:"stage"`
+	Count          int     `json:"count"`
+	TotalSeconds   float64 `json:"totalSeconds"`
+	AverageSeconds float64 `json:"averageSeconds"`
+}
+
+// setStage moves the task into stage, closing the previous timeline span.
+// Re-entering the current stage is a no-op.
+func (t *Task) setStage(stage string, at time.Time) {
+	if t.Stage == stage && len(t.Timeline) > 0 && t.Timeline[len(t.Timeline)-1].EndedAt == nil {
+		return
+	}
+	t.closeStage(at)
+	t.Stage = stage
+	t.Timeline = append(t.Timeline, StageSpan{Stage: stage, StartedAt: at})
+}
+
+// closeStage ends the open timeline span, if any. The slice is copied so
+// snapshots handed out earlier are never mutated.
+func (t *Task) closeStage(at time.Time) {
+	last := len(t.Timeline) - 1
+	if last < 0 || t.Timeline[last].EndedAt != nil {
+		return
+	}
+	timeline := make([]StageSpan, len(t.Timeline))
+	copy(timeline, t.Timeline)
+	ended := at
+	timeline[last].EndedAt = &ended
+	t.Timeline = timeline
+}
+
+// GetTaskTimeline returns the stage history for a task.
+func (a *App) GetTaskTimeline(id string) ([]StageSpan, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	task, ok := a.tasks[id]
+	if !ok {
+		return nil, errors.New("task not found")
+	}
+	out := make([]StageSpan, len(task.Timeline))
+	copy(out, task.Timeline)
+	return out, nil
+}
+
+// GetStageStats returns per-stage duration totals and averages.
+func (a *App) GetStageStats() ([]StageStat, error) {
+	a.mu.Lock()
+	byStage := make(map[string]*StageStat)
+	for _, task := range a.tasks {
+		if task.deleted() {
+			continue
+		}
+		for _, span := range task.Timeline {
+			if span.EndedAt == nil {
+				continue
+			}
+			stat, ok := byStage[span.Stage]
+			if !ok {
+				stat = &StageStat{Stage: span.Stage}
+				byStage[span.Stage] = stat
+			}
+			stat.Count++
+			stat.TotalSeconds += span.EndedAt.Sub(span.StartedAt).Seconds()
+		}
+	}
+	a.mu.Unlock()
+
+	out := make([]StageStat, 0, len(byStage))
+	for _, stat := range byStage {
+		stat.AverageSeconds = stat.TotalSeconds / float64(stat.Count)
+		out = append(out, *stat)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TotalSeconds > out[j].TotalSeconds })
+	return out, nil
+}
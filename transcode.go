@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errPostProcessingStopped is returned when a transcode or pipeline step is
+// killed because the run was canceled, the task deleted or the app quit.
+var errPostProcessingStopped = errors.New("post-processing stopped")
+
+// TranscodePreset is a built-in ffmpeg conversion run after a download. Ext
+// is the extension of the converted file.
+type TranscodePreset struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Ext  string   `json:"ext"`
+	Args []string `json:"args"`
+}
+
+var transcodePresets = []TranscodePreset{
+	{
+		ID:   "h264-1080p",
+		Name: "H.264 MP4 1080p",
+		Ext:  "mp4",
+		Args: []string{"-vf", "scale=-2:'min(1080,ih)'", "-c:v", "libx264", "-preset", "medium", "-crf", "20", "-c:a", "aac", "-b:a", "192k", "-movflags", "+faststart"},
+	},
+	{
+		ID:   "h264-720p",
+		Name: "H.264 MP4 720p",
+		Ext:  "mp4",
+		Args: []string{"-vf", "scale=-2:'min(720,ih)'", "-c:v", "libx264", "-preset", "medium", "-crf", "22", "-c:a", "aac", "-b:a", "160k", "-movflags", "+faststart"},
+	},
+	{
+		ID:   "opus-audio",
+		Name: "Opus audio",
+		Ext:  "opus",
+		Args: []string{"-vn", "-c:a", "libopus", "-b:a", "128k"},
+	},
+	{
+		ID:   "mp3-audio",
+		Name: "MP3 audio",
+		Ext:  "mp3",
+		Args: []string{"-vn", "-c:a", "libmp3lame", "-q:a", "2"},
+	},
+}
+
+// ListTranscodePresets returns the built-in transcode presets.
+func (a *App) ListTranscodePresets() ([]TranscodePreset, error) {
+	return transcodePresets, nil
+}
+
+func findTranscodePreset(id string) (TranscodePreset, bool) {
+	for _, preset := range transcodePresets {
+		if preset.ID == id {
+			return preset, true
+		}
+	}
+	return TranscodePreset{}, false
+}
+
+// TranscodeTask converts a finished task's output with a preset. It runs in
+// the background; progress and the result show up on the task.
+func (a *App) TranscodeTask(id string, presetID string) error {
+	preset, ok := findTranscodePreset(presetID)
+	if !ok {
+		return errors.New("transcode preset not found")
+	}
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return errors.New("task not found")
+	}
+	if task.deleted() {
+		a.mu.Unlock()
+		return errors.New("task is deleted")
+	}
+	if task.Status != statusSuccess || task.OutputPath == "" {
+		a.mu.Unlock()
+		return errors.New("task has no downloaded file")
+	}
+	if a.pipelineTasks[id] {
+		a.mu.Unlock()
+		return errors.New("task is already being processed")
+	}
+	a.pipelineTasks[id] = true
+	a.mu.Unlock()
+	go func() {
+		defer a.endPostProcessing(id)
+		a.runTranscode(id, preset)
+	}()
+	return nil
+}
+
+// runProfileTranscode applies the profile's preset after a download.
+func (a *App) runProfileTranscode(id string, profile Profile) {
+	preset, ok := findTranscodePreset(profile.TranscodePreset)
+	if !ok {
+		return
+	}
+	a.mu.Lock()
+	if a.pipelineTasks[id] {
+		a.mu.Unlock()
+		return
+	}
+	a.pipelineTasks[id] = true
+	a.mu.Unlock()
+	defer a.endPostProcessing(id)
+	a.runTranscode(id, preset)
+}
+
+func (a *App) endPostProcessing(id string) {
+	a.mu.Lock()
+	delete(a.pipelineTasks, id)
+	a.mu.Unlock()
+}
+
+// runTranscode converts the task's output into a temporary file next to it
+// and renames it into place once ffmpeg succeeds, so a failed or interrupted
+// run never leaves a half-written file under the final name. An existing
+// file is never overwritten; the converted file gets a " (2)" style name
+// instead. The original download is kept as a second output.
+func (a *App) runTranscode(id string, preset TranscodePreset) {
+	params := map[string]string{"preset": preset.Name}
+	task, ok := a.setTaskStage(id, stageTranscoding, params, "0%")
+	if !ok {
+		return
+	}
+	input := task.OutputPath
+	base := strings.TrimSuffix(input, filepath.Ext(input))
+	output := base + "." + preset.Ext
+	if output == input {
+		output = base + "." + preset.ID + "." + preset.Ext
+	}
+	tmp := base + ".transcoding." + preset.Ext
+
+	args := append([]string{"-y", "-i", input}, preset.Args...)
+	args = append(args, tmp)
+	err := a.runFfmpegWithProgress(id, task.Duration, args)
+	if err == nil {
+		output = uniquePath(output)
+		err = os.Rename(tmp, output)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		if errors.Is(err, errPostProcessingStopped) {
+			a.finishTranscode(id, "", nil)
+			return
+		}
+		params["detail"] = err.Error()
+		a.finishTranscode(id, "", &taskError{Code: errorTranscodeFailed, Params: params, Message: "Transcoding to " + preset.Name + " failed: " + err.Error()})
+		return
+	}
+	a.finishTranscode(id, output, nil)
+}
+
+// finishTranscode makes the converted file the task's primary output, or
+// records the failure, and closes the Transcoding stage. With neither set
+// the run was stopped and only the stage is closed.
+func (a *App) finishTranscode(id, output string, failure *taskError) {
+	a.mu.Lock()
+	task, ok := a.tasks[id]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	task.UpdatedAt = time.Now()
+	task.Progress = "100%"
+	if failure != nil {
+		task.setError(*failure)
+	} else if output != "" {
+		task.clearError()
+		task.prependOutput(output)
+	}
+	task.closeStage(task.UpdatedAt)
+	updated := *task
+	a.mu.Unlock()
+	a.emitTaskUpdate(updated)
+	a.saveTasks()
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetPermanentDeleteFallback reports whether files are deleted outright when
+// they cannot be moved to the trash.
+func (a *App) GetPermanentDeleteFallback() (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.permanentDeleteFallback, nil
+}
+
+// SetPermanentDeleteFallback chooses whether files that cannot be moved to
+// the trash are deleted permanently. It is off by default, so a failed trash
+// keeps the file and reports an error instead.
+func (a *App) SetPermanentDeleteFallback(enabled bool) error {
+	a.mu.Lock()
+	a.permanentDeleteFallback = enabled
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// moveToTrash sends target to the system trash, deleting it instead only when
+// the permanent delete fallback is on.
+func (a *App) moveToTrash(target string) error {
+	err := trashFile(target)
+	if err == nil {
+		return nil
+	}
+	a.mu.Lock()
+	allowDelete := a.permanentDeleteFallback
+	a.mu.Unlock()
+	if allowDelete && os.Remove(target) == nil {
+		return nil
+	}
+	return err
+}
+
+// freedesktopTrash moves path into the trash described by the freedesktop.org
+// Trash specification, for Linux desktops without gio. Files on the home
+// filesystem go to $XDG_DATA_HOME/Trash; files on other filesystems go to
+// $topdir/.Trash/$uid or $topdir/.Trash-$uid, as file managers expect.
+func freedesktopTrash(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return err
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	homeTrash := filepath.Join(dataHome, "Trash")
+	if err := os.MkdirAll(homeTrash, 0o700); err != nil {
+		return err
+	}
+	if sameDevice(path, homeTrash) {
+		return trashInto(homeTrash, path, path)
+	}
+
+	top, err := mountPoint(path)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(top, path)
+	if err != nil {
+		return err
+	}
+	uid := strconv.Itoa(os.Getuid())
+	shared := filepath.Join(top, ".Trash")
+	if info, err := os.Lstat(shared); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		dir := filepath.Join(shared, uid)
+		if err := os.MkdirAll(dir, 0o700); err == nil {
+			if err := trashInto(dir, path, rel); err == nil {
+				return nil
+			}
+		}
+	}
+	dir := filepath.Join(top, ".Trash-"+uid)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	return trashInto(dir, path, rel)
+}
+
+// trashInto moves path into the trash directory trash. infoPath is the path
+// recorded in the .trashinfo file: absolute for the home trash, relative to
+// the filesystem's top directory otherwise. The info file is created first
+// so that two deletions cannot claim the same name.
+func trashInto(trash, path, infoPath string) error {
+	filesDir := filepath.Join(trash, "files")
+	infoDir := filepath.Join(trash, "info")
+	if err := os.MkdirAll(filesDir, 0o700); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(infoDir, 0o700); err != nil {
+		return err
+	}
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(filepath.Base(path), ext)
+	info := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: filepath.ToSlash(infoPath)}).EscapedPath(),
+		time.Now().Format("2006-01-02T15:04:05"))
+	for n := 1; n < 10000; n++ {
+		name := stem + ext
+		if n > 1 {
+			name = stem + "." + strconv.Itoa(n) + ext
+		}
+		infoFile := filepath.Join(infoDir, name+".trashinfo")
+		file, err := os.OpenFile(infoFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = file.WriteString(info)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(path, filepath.Join(filesDir, name))
+		}
+		if err != nil {
+			_ = os.Remove(infoFile)
+			return err
+		}
+		return nil
+	}
+	return errors.New("trash has too many files with this name")
+}
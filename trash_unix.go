@@ -0,0 +1,41 @@
+//go:build !windows
+
+package main
+
+import (
+	"path/filepath"
+	"syscall"
+)
+
+func deviceID(path string) (uint64, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Dev), nil
+}
+
+func sameDevice(a, b string) bool {
+	deviceA, errA := deviceID(a)
+	deviceB, errB := deviceID(b)
+	return errA == nil && errB == nil && deviceA == deviceB
+}
+
+// mountPoint returns the top directory of the filesystem holding path.
+func mountPoint(path string) (string, error) {
+	device, err := deviceID(path)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Dir(path)
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir, nil
+		}
+		if parentDevice, err := deviceID(parent); err != nil || parentDevice != device {
+			return dir, nil
+		}
+		dir = parent
+	}
+}
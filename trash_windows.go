@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+import "errors"
+
+// The freedesktop trash is only used on Linux; Windows has the Recycle Bin.
+
+func sameDevice(a, b string) bool {
+	return false
+}
+
+func mountPoint(path string) (string, error) {
+	return "", errors.New("not supported on Windows")
+}
@@ -0,0 +1,171 @@
+//go:build !darwin || cgo
+
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"fyne.io/systray"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const trayRecentLimit = 5
+
+//go:embed build/appicon.png
+var trayIconPNG []byte
+
+//go:embed build/windows/icon.ico
+var trayIconICO []byte
+
+// trayMenu holds the tray menu items that change while the app runs. The
+// recent-completion slots are created once and hidden until used, since not
+// every platform can remove items reliably.
+type trayMenu struct {
+	status *systray.MenuItem
+	pause  *systray.MenuItem
+	resume *systray.MenuItem
+	open   *systray.MenuItem
+	quit   *systray.MenuItem
+	recent []*systray.MenuItem
+
+	mu      sync.Mutex
+	targets []string
+}
+
+// onTrayReady builds the tray menu and keeps it in step with the queue.
+func (a *App) onTrayReady() {
+	if runtime.GOOS == "windows" {
+		systray.SetIcon(trayIconICO)
+	} else {
+		systray.SetIcon(trayIconPNG)
+	}
+	systray.SetTooltip("FetchForge")
+
+	menu := &trayMenu{}
+	menu.status = systray.AddMenuItem("Idle", "")
+	menu.status.Disable()
+	systray.AddSeparator()
+	menu.pause = systray.AddMenuItem("Pause all", "Pause the queue and running downloads")
+	menu.resume = systray.AddMenuItem("Resume all", "Resume the queue and paused downloads")
+	menu.open = systray.AddMenuItem("Open FetchForge", "")
+	systray.AddSeparator()
+	recentHeader := systray.AddMenuItem("Recently completed", "")
+	recentHeader.Disable()
+	for i := 0; i < trayRecentLimit; i++ {
+		item := systray.AddMenuItem("", "Open the download folder")
+		item.Hide()
+		menu.recent = append(menu.recent, item)
+	}
+	menu.targets = make([]string, trayRecentLimit)
+	systray.AddSeparator()
+	menu.quit = systray.AddMenuItem("Quit", "")
+
+	go a.trayClicks(menu)
+	ticker := time.NewTicker(queueSummaryInterval)
+	defer ticker.Stop()
+	for {
+		a.updateTray(menu)
+		<-ticker.C
+	}
+}
+
+func (a *App) trayClicks(menu *trayMenu) {
+	recentClicks := make(chan int)
+	for i, item := range menu.recent {
+		go func(index int, item *systray.MenuItem) {
+			for range item.ClickedCh {
+				recentClicks <- index
+			}
+		}(i, item)
+	}
+	for {
+		select {
+		case <-menu.pause.ClickedCh:
+			_ = a.PauseAll()
+		case <-menu.resume.ClickedCh:
+			_ = a.ResumeAll()
+		case <-menu.open.ClickedCh:
+			a.showWindow()
+		case <-menu.quit.ClickedCh:
+			if a.ctx != nil {
+				wailsruntime.Quit(a.ctx)
+			}
+		case index := <-recentClicks:
+			menu.mu.Lock()
+			target := menu.targets[index]
+			menu.mu.Unlock()
+			if target != "" {
+				_ = a.OpenPath(target)
+			}
+		}
+	}
+}
+
+// updateTray shows the running count and combined progress of running
+// downloads, and lists the most recent completions.
+func (a *App) updateTray(menu *trayMenu) {
+	a.mu.Lock()
+	running := 0
+	var downloaded, total int64
+	var finished []Task
+	for _, id := range a.order {
+		task, ok := a.tasks[id]
+		if !ok || task.deleted() {
+			continue
+		}
+		switch task.Status {
+		case statusRunning:
+			running++
+			downloaded += task.DownloadedBytes
+			total += taskSize(*task)
+		case statusSuccess:
+			if task.OutputPath != "" {
+				finished = append(finished, *task)
+			}
+		}
+	}
+	paused := a.queuePaused
+	a.mu.Unlock()
+
+	status := "Idle"
+	if running > 0 {
+		status = fmt.Sprintf("%d downloading", running)
+		if total > 0 {
+			status += fmt.Sprintf(" · %.0f%%", float64(downloaded)/float64(total)*100)
+		}
+	}
+	if paused {
+		status += " (queue paused)"
+	}
+	menu.status.SetTitle(status)
+	systray.SetTooltip("FetchForge — " + status)
+
+	sort.Slice(finished, func(i, j int) bool { return finished[i].UpdatedAt.After(finished[j].UpdatedAt) })
+	for i, item := range menu.recent {
+		target := ""
+		if i < len(finished) {
+			target = finished[i].OutputPath
+			item.SetTitle(finished[i].Title)
+			item.Show()
+		} else {
+			item.Hide()
+		}
+		menu.mu.Lock()
+		menu.targets[i] = target
+		menu.mu.Unlock()
+	}
+}
+
+// showWindow brings the main window to the front.
+func (a *App) showWindow() {
+	if a.ctx == nil {
+		return
+	}
+	wailsruntime.WindowUnminimise(a.ctx)
+	wailsruntime.WindowShow(a.ctx)
+}
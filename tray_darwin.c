@@ -0,0 +1,12 @@
+//go:build darwin && cgo
+
+#include <dispatch/dispatch.h>
+#include "_cgo_export.h"
+
+static void startTrayOnMain(void *context) {
+	fetchforgeTrayStart();
+}
+
+void fetchforgeDispatchTrayStart(void) {
+	dispatch_async_f(dispatch_get_main_queue(), NULL, startTrayOnMain);
+}
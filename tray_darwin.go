@@ -0,0 +1,31 @@
+//go:build darwin && cgo
+
+package main
+
+/*
+void fetchforgeDispatchTrayStart(void);
+*/
+import "C"
+
+import "fyne.io/systray"
+
+// trayStart creates the status item. AppKit objects must be made on the main
+// thread, which Wails owns, so it is dispatched there.
+var trayStart func()
+
+// startTray adds a menu bar item using Wails' own AppKit run loop.
+func (a *App) startTray() {
+	trayStart, _ = systray.RunWithExternalLoop(a.onTrayReady, nil)
+	C.fetchforgeDispatchTrayStart()
+}
+
+// stopTray leaves the status item to go away with the process; ending the
+// tray's loop on macOS would terminate NSApp while Wails shuts down.
+func (a *App) stopTray() {}
+
+//export fetchforgeTrayStart
+func fetchforgeTrayStart() {
+	if trayStart != nil {
+		trayStart()
+	}
+}
@@ -0,0 +1,8 @@
+//go:build darwin && !cgo
+
+package main
+
+// The macOS tray needs cgo; builds without it have no menu bar item.
+func (a *App) startTray() {}
+
+func (a *App) stopTray() {}
@@ -0,0 +1,23 @@
+//go:build !darwin
+
+package main
+
+import (
+	"runtime"
+
+	"fyne.io/systray"
+)
+
+// startTray runs the tray on its own locked OS thread. Windows delivers tray
+// messages to the thread that created the icon, and Linux uses D-Bus, so
+// neither needs Wails' UI thread.
+func (a *App) startTray() {
+	go func() {
+		runtime.LockOSThread()
+		systray.Run(a.onTrayReady, nil)
+	}()
+}
+
+func (a *App) stopTray() {
+	systray.Quit()
+}
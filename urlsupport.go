@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	urlSupported   = "supported"
+	urlGeneric     = "generic"
+	urlUnsupported = "unsupported"
+)
+
+const listExtractorsTimeout = 30 * time.Second
+
+// URLSupport says whether a link can be downloaded. Status is "supported"
+// when an engine or a site-specific yt-dlp extractor handles it, "generic"
+// when only yt-dlp's generic extractor would try the page, and
+// "unsupported" when it is not a web link. Broken is set when yt-dlp marks
+// the matching extractor as currently broken.
+type URLSupport struct {
+	URL       string `json:"url"`
+	Status    string `json:"status"`
+	Engine    string `json:"engine"`
+	Extractor string `json:"extractor,omitempty"`
+	Broken    bool   `json:"broken,omitempty"`
+}
+
+// extractorHostAliases maps short or renamed domains to the extractor that
+// handles them.
+var extractorHostAliases = map[string]string{
+	"youtu.be":     "youtube",
+	"x.com":        "twitter",
+	"b23.tv":       "bilibili",
+	"nicovideo.jp": "niconico",
+	"redd.it":      "reddit",
+	"fb.watch":     "facebook",
+}
+
+// extractorList is yt-dlp's --list-extractors output, keyed by the lowercase
+// site part of each extractor name ("youtube" for "youtube:tab").
+type extractorList struct {
+	names  map[string]string
+	broken map[string]bool
+}
+
+// extractorCache holds the extractor list for one yt-dlp binary. It is
+// reloaded when the binary changes, e.g. after UpdateYtDlp.
+var extractorCache struct {
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+	list    *extractorList
+}
+
+// CheckURLSupport reports, for each URL, whether it can be downloaded, so
+// unsupported links can be flagged before tasks are created.
+func (a *App) CheckURLSupport(urls []string) ([]URLSupport, error) {
+	results := make([]URLSupport, 0, len(urls))
+	var extractors *extractorList
+	for _, rawURL := range urls {
+		rawURL = strings.TrimSpace(rawURL)
+		result := URLSupport{URL: rawURL, Status: urlUnsupported}
+		parsed, err := url.Parse(rawURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+			results = append(results, result)
+			continue
+		}
+		result.Engine = a.engineForURL(rawURL)
+		if result.Engine != engineYtDlp {
+			result.Status = urlSupported
+			results = append(results, result)
+			continue
+		}
+		if extractors == nil {
+			extractors, err = a.loadExtractors()
+			if err != nil {
+				return nil, err
+			}
+		}
+		result.Status = urlGeneric
+		if name, broken, ok := extractors.match(parsed.Hostname()); ok {
+			result.Status = urlSupported
+			result.Extractor = name
+			result.Broken = broken
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// loadExtractors returns the cached extractor list, running yt-dlp
+// --list-extractors the first time and whenever the binary has changed.
+func (a *App) loadExtractors() (*extractorList, error) {
+	path := a.ytDlpBinary()
+	if path == "" {
+		path = "yt-dlp"
+	}
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+
+	extractorCache.mu.Lock()
+	defer extractorCache.mu.Unlock()
+	if extractorCache.list != nil && extractorCache.path == path && extractorCache.modTime.Equal(modTime) {
+		return extractorCache.list, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), listExtractorsTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, "--list-extractors")
+	configureProcessGroup(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	list := parseExtractorList(string(output))
+	extractorCache.path = path
+	extractorCache.modTime = modTime
+	extractorCache.list = list
+	return list, nil
+}
+
+func parseExtractorList(output string) *extractorList {
+	list := &extractorList{names: make(map[string]string), broken: make(map[string]bool)}
+	for _, line := range strings.Split(output, "\n") {
+		name := strings.TrimSpace(line)
+		broken := strings.HasSuffix(name, "(CURRENTLY BROKEN)")
+		name = strings.TrimSpace(strings.TrimSuffix(name, "(CURRENTLY BROKEN)"))
+		site, _, _ := strings.Cut(strings.ToLower(name), ":")
+		if site == "" || site == "generic" {
+			continue
+		}
+		if _, ok := list.names[site]; !ok {
+			list.names[site] = name
+			list.broken[site] = broken
+		}
+	}
+	return list
+}
+
+// match finds the extractor for host by comparing its labels, e.g. "vimeo"
+// in "player.vimeo.com", with the extractor site names.
+func (l *extractorList) match(host string) (string, bool, bool) {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	for domain, site := range extractorHostAliases {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			if name, ok := l.names[site]; ok {
+				return name, l.broken[site], true
+			}
+		}
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) > 1 {
+		labels = labels[:len(labels)-1]
+	}
+	for i := len(labels) - 1; i >= 0; i-- {
+		if name, ok := l.names[labels[i]]; ok {
+			return name, l.broken[labels[i]], true
+		}
+	}
+	return "", false, false
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	watchFolderInterval = 5 * time.Second
+	// watchFolderSettle is how long a file must stay unchanged before it is
+	// read, so files still being written are left alone.
+	watchFolderSettle = 2 * time.Second
+)
+
+// WatchFolderImport is the payload of the watch:imported event.
+type WatchFolderImport struct {
+	File   string       `json:"file"`
+	Result ImportResult `json:"result"`
+	Error  string       `json:"error"`
+}
+
+// GetWatchDirectory returns the folder watched for link files. Empty means
+// watching is off.
+func (a *App) GetWatchDirectory() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.watchDir, nil
+}
+
+// SetWatchDirectory sets the folder watched for link files. A .txt, .url or
+// .json file dropped into it is imported like ImportURLsFromFile and then
+// moved to its processed/ subfolder, or failed/ when it cannot be read.
+// Empty input turns watching off.
+func (a *App) SetWatchDirectory(path string) (string, error) {
+	dir, err := validateOutputDir(path)
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	a.watchDir = dir
+	a.mu.Unlock()
+	a.saveConfig()
+	return dir, nil
+}
+
+// watchFolderLoop polls the watch folder. Polling works the same on every
+// platform and on network shares, where change notifications are
+// unreliable.
+func (a *App) watchFolderLoop() {
+	ticker := time.NewTicker(watchFolderInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.mu.Lock()
+		dir := a.watchDir
+		a.mu.Unlock()
+		if dir != "" {
+			a.scanWatchFolder(dir)
+		}
+	}
+}
+
+func (a *App) scanWatchFolder(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".txt", ".url", ".json":
+		default:
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < watchFolderSettle {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		event := WatchFolderImport{File: entry.Name()}
+		destination := "processed"
+		if urls, err := readURLFile(path); err != nil {
+			event.Error = err.Error()
+			destination = "failed"
+		} else {
+			event.Result = a.importURLs(urls)
+		}
+		target := uniquePath(filepath.Join(dir, destination, entry.Name()))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err == nil {
+			_ = moveFile(path, target)
+		}
+		if a.ctx != nil {
+			wailsruntime.EventsEmit(a.ctx, "watch:imported", event)
+		}
+	}
+}
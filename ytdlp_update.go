@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	ytDlpReleaseURL      = "https://api.github.com/repos/yt-dlp/yt-dlp/releases/latest"
+	ytDlpChecksumAsset   = "SHA2-256SUMS"
+	ytDlpUpdateInterval  = 24 * time.Hour
+	ytDlpDownloadTimeout = 10 * time.Minute
+)
+
+var ytDlpReleaseClient = &http.Client{Timeout: ytDlpDownloadTimeout}
+
+// toolInstallMu keeps a manual install and the daily yt-dlp check from
+// writing to ~/.fetchforge/bin at the same time.
+var toolInstallMu sync.Mutex
+
+// YtDlpUpdateInfo describes the installed yt-dlp and the latest release.
+// Managed is true when the binary in use is the one FetchForge installs in
+// ~/.fetchforge/bin.
+type YtDlpUpdateInfo struct {
+	Path            string `json:"path"`
+	CurrentVersion  string `json:"currentVersion"`
+	LatestVersion   string `json:"latestVersion"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+	Managed         bool   `json:"managed"`
+}
+
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name   string `json:"name"`
+		URL    string `json:"browser_download_url"`
+		Digest string `json:"digest"`
+	} `json:"assets"`
+}
+
+// GetYtDlpVersion returns the version of the yt-dlp binary in use.
+func (a *App) GetYtDlpVersion() (string, error) {
+	path := a.ytDlpBinary()
+	if path == "" {
+		return "", errors.New("yt-dlp was not found")
+	}
+	return commandVersion(path, "--version")
+}
+
+// CheckForYtDlpUpdate compares the installed yt-dlp with the latest release.
+func (a *App) CheckForYtDlpUpdate() (YtDlpUpdateInfo, error) {
+	info := a.ytDlpInstallInfo()
+	release, err := fetchGitHubRelease(ytDlpReleaseURL)
+	if err != nil {
+		return info, err
+	}
+	info.LatestVersion = release.TagName
+	info.UpdateAvailable = info.CurrentVersion != release.TagName
+	return info, nil
+}
+
+// UpdateYtDlp downloads the latest yt-dlp release for this platform into
+// ~/.fetchforge/bin, verifies its checksum and switches to it.
+func (a *App) UpdateYtDlp() (YtDlpUpdateInfo, error) {
+	toolInstallMu.Lock()
+	defer toolInstallMu.Unlock()
+	release, err := fetchGitHubRelease(ytDlpReleaseURL)
+	if err != nil {
+		return YtDlpUpdateInfo{}, err
+	}
+	asset := ytDlpAssetName(runtime.GOOS, runtime.GOARCH)
+	if asset == "" {
+		return YtDlpUpdateInfo{}, fmt.Errorf("no yt-dlp release for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+	var binaryURL, checksumURL string
+	for _, candidate := range release.Assets {
+		switch candidate.Name {
+		case asset:
+			binaryURL = candidate.URL
+		case ytDlpChecksumAsset:
+			checksumURL = candidate.URL
+		}
+	}
+	if binaryURL == "" || checksumURL == "" {
+		return YtDlpUpdateInfo{}, fmt.Errorf("release %s has no %s", release.TagName, asset)
+	}
+	expected, err := fetchReleaseChecksum(checksumURL, asset)
+	if err != nil {
+		return YtDlpUpdateInfo{}, err
+	}
+	target, err := managedToolPath("yt-dlp")
+	if err != nil {
+		return YtDlpUpdateInfo{}, err
+	}
+	if err := installReleaseBinary("yt-dlp", binaryURL, expected, target, false); err != nil {
+		return YtDlpUpdateInfo{}, err
+	}
+
+	a.mu.Lock()
+	a.ytDlpPath = target
+	a.mu.Unlock()
+	info := a.ytDlpInstallInfo()
+	info.LatestVersion = release.TagName
+	info.UpdateAvailable = info.CurrentVersion != release.TagName
+	return info, nil
+}
+
+// ytDlpUpdateLoop checks for yt-dlp releases once a day. A managed binary is
+// updated in place when no download is running; otherwise the UI is told
+// through a "ytdlp:update" event.
+func (a *App) ytDlpUpdateLoop() {
+	ticker := time.NewTicker(ytDlpUpdateInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		info, err := a.CheckForYtDlpUpdate()
+		if err != nil || !info.UpdateAvailable {
+			continue
+		}
+		a.mu.Lock()
+		idle := len(a.running) == 0
+		a.mu.Unlock()
+		if info.Managed && idle {
+			if updated, err := a.UpdateYtDlp(); err == nil {
+				info = updated
+			}
+		}
+		if a.ctx != nil {
+			wailsruntime.EventsEmit(a.ctx, "ytdlp:update", info)
+		}
+	}
+}
+
+func (a *App) ytDlpBinary() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.ytDlpPath
+}
+
+func (a *App) ytDlpInstallInfo() YtDlpUpdateInfo {
+	info := YtDlpUpdateInfo{Path: a.ytDlpBinary()}
+	if managed, err := managedToolPath("yt-dlp"); err == nil && info.Path != "" {
+		info.Managed = filepath.Clean(info.Path) == managed
+	}
+	if info.Path != "" {
+		info.CurrentVersion, _ = commandVersion(info.Path, "--version")
+	}
+	return info
+}
+
+// ytDlpAssetName maps a platform to the standalone yt-dlp release asset.
+func ytDlpAssetName(goos, goarch string) string {
+	switch goos + "/" + goarch {
+	case "darwin/amd64", "darwin/arm64":
+		return "yt-dlp_macos"
+	case "linux/amd64":
+		return "yt-dlp_linux"
+	case "linux/arm64":
+		return "yt-dlp_linux_aarch64"
+	case "linux/arm":
+		return "yt-dlp_linux_armv7l"
+	case "windows/amd64":
+		return "yt-dlp.exe"
+	case "windows/386":
+		return "yt-dlp_x86.exe"
+	case "windows/arm64":
+		return "yt-dlp_arm64.exe"
+	}
+	return ""
+}
+
+func fetchGitHubRelease(url string) (githubRelease, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return githubRelease{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := ytDlpReleaseClient.Do(req)
+	if err != nil {
+		return githubRelease{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return githubRelease{}, fmt.Errorf("release check failed: %s", resp.Status)
+	}
+	var release githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return githubRelease{}, errors.New("could not read release information")
+	}
+	if release.TagName == "" {
+		return githubRelease{}, errors.New("release has no version")
+	}
+	return release, nil
+}
+
+// fetchReleaseChecksum reads the SHA-256 for asset from a SHA2-256SUMS file.
+func fetchReleaseChecksum(url, asset string) (string, error) {
+	resp, err := ytDlpReleaseClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("checksum download failed: %s", resp.Status)
+	}
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 1<<20))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", asset)
+}
+
+// copyDownload writes body to dst, gunzipping it when needed, and reads the
+// body to the end so its checksum covers every byte.
+func copyDownload(dst io.Writer, body io.Reader, gzipped bool) error {
+	if gzipped {
+		reader, err := gzip.NewReader(body)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, reader); err != nil {
+			return err
+		}
+		if err := reader.Close(); err != nil {
+			return err
+		}
+	} else if _, err := io.Copy(dst, body); err != nil {
+		return err
+	}
+	_, err := io.Copy(io.Discard, body)
+	return err
+}
+
+// installReleaseBinary downloads tool from url next to target, checks the
+// SHA-256 of the download and then swaps it into place. gzipped downloads are
+// decompressed on the way. Without an expected checksum nothing is
+// installed. On Windows a running binary cannot be replaced, so the old one
+// is moved aside first.
+func installReleaseBinary(tool, url, expected, target string, gzipped bool) error {
+	if expected == "" {
+		return errors.New("no published checksum to verify the download against")
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	resp, err := ytDlpReleaseClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed: %s", resp.Status)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+tool+"-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	hash := sha256.New()
+	copyErr := copyDownload(tmp, io.TeeReader(resp.Body, hash), gzipped)
+	closeErr := tmp.Close()
+	if copyErr != nil || closeErr != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Join(copyErr, closeErr)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("downloaded %s does not match its published checksum", tool)
+	}
+	if err := os.Chmod(tmpPath, 0o755); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if runtime.GOOS == "windows" && fileExists(target) {
+		oldPath := target + ".old"
+		_ = os.Remove(oldPath)
+		if err := os.Rename(target, oldPath); err != nil {
+			_ = os.Remove(tmpPath)
+			return err
+		}
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}